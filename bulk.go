@@ -1,8 +1,16 @@
 package usps
 
 import (
+	"bufio"
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/my-eq/go-usps/models"
@@ -29,10 +37,32 @@ type BulkConfig struct {
 	RequestsPerSecond int
 	// MaxRetries is the maximum number of retry attempts for failed requests (default: 3)
 	MaxRetries int
-	// RetryBackoff is the base duration for exponential backoff (default: 1 second)
+	// RetryBackoff is the base duration for exponential backoff (default: 1 second).
+	// A Retry-After header on a 429 or 503 response takes precedence. Which
+	// errors are retried is decided by the client's WithRetryClassifier.
 	RetryBackoff time.Duration
 	// ProgressCallback is called after each request completes (optional)
 	ProgressCallback func(completed, total int, err error)
+	// Limiter overrides the built-in in-process rate limiter (optional).
+	// When set, RequestsPerSecond is ignored and Wait is called before every attempt.
+	Limiter Limiter
+	// AdaptiveRateLimit makes the built-in limiter halve its rate on every 429
+	// response, pause for the Retry-After duration when one is given, and
+	// gradually recover toward RequestsPerSecond as requests succeed. It has no
+	// effect when a custom Limiter is set.
+	AdaptiveRateLimit bool
+	// BeforeRetry is called before each retry with the retry number (starting
+	// at 1), the error that triggered it, and the backoff about to be applied
+	// (optional). Returning false stops retrying and returns the error.
+	BeforeRetry func(attempt int, err error, backoff time.Duration) bool
+}
+
+// Limiter controls the rate at which bulk requests are issued.
+// Implementations can coordinate limits across processes, for example with a
+// shared token bucket in Redis. Wait must block until a request may proceed or
+// return an error if ctx is done.
+type Limiter interface {
+	Wait(ctx context.Context) error
 }
 
 // DefaultBulkConfig returns a BulkConfig with sensible defaults
@@ -45,12 +75,77 @@ func DefaultBulkConfig() *BulkConfig {
 	}
 }
 
+// Validate reports configuration values that NewBulkProcessor would replace
+// with defaults: a MaxConcurrency, RequestsPerSecond, or RetryBackoff that is
+// not positive, or a negative MaxRetries. A zero RequestsPerSecond is allowed
+// when a custom Limiter is set, since it is ignored. The returned error lists
+// every adjusted field; Validate returns nil when the config is used as is.
+func (c *BulkConfig) Validate() error {
+	if c == nil {
+		return nil
+	}
+
+	var errs []error
+	if c.MaxConcurrency <= 0 {
+		errs = append(errs, fmt.Errorf("bulk config: MaxConcurrency must be positive, got %d", c.MaxConcurrency))
+	}
+	if c.RequestsPerSecond <= 0 && c.Limiter == nil {
+		errs = append(errs, fmt.Errorf("bulk config: RequestsPerSecond must be positive, got %d", c.RequestsPerSecond))
+	}
+	if c.MaxRetries < 0 {
+		errs = append(errs, fmt.Errorf("bulk config: MaxRetries must not be negative, got %d", c.MaxRetries))
+	}
+	if c.RetryBackoff <= 0 {
+		errs = append(errs, fmt.Errorf("bulk config: RetryBackoff must be positive, got %v", c.RetryBackoff))
+	}
+	return errors.Join(errs...)
+}
+
 // AddressResult represents the result of a bulk address validation
 type AddressResult struct {
 	Index    int
 	Request  *models.AddressRequest
 	Response *models.AddressResponse
 	Error    error
+	Duration time.Duration // Time from the first attempt to the final result, including retries
+}
+
+// MergeAddressResults combines the results of several BulkProcessor runs over
+// shards of one job into a single slice ordered by Index. Each shard's Index
+// values must be positions in the original, unsharded input, so together the
+// shards must cover 0 through n-1 exactly once; an error reports any nil
+// result, negative index, duplicate, or gap.
+func MergeAddressResults(shards ...[]*AddressResult) ([]*AddressResult, error) {
+	total := 0
+	for _, shard := range shards {
+		total += len(shard)
+	}
+
+	byIndex := make(map[int]*AddressResult, total)
+	for _, shard := range shards {
+		for _, result := range shard {
+			if result == nil {
+				return nil, errors.New("merge address results: nil result")
+			}
+			if result.Index < 0 {
+				return nil, fmt.Errorf("merge address results: negative index %d", result.Index)
+			}
+			if _, ok := byIndex[result.Index]; ok {
+				return nil, fmt.Errorf("merge address results: duplicate index %d", result.Index)
+			}
+			byIndex[result.Index] = result
+		}
+	}
+
+	merged := make([]*AddressResult, total)
+	for i := range merged {
+		result, ok := byIndex[i]
+		if !ok {
+			return nil, fmt.Errorf("merge address results: missing index %d", i)
+		}
+		merged[i] = result
+	}
+	return merged, nil
 }
 
 // CityStateResult represents the result of a bulk city/state lookup
@@ -59,6 +154,7 @@ type CityStateResult struct {
 	Request  *models.CityStateRequest
 	Response *models.CityStateResponse
 	Error    error
+	Duration time.Duration // Time from the first attempt to the final result, including retries
 }
 
 // ZIPCodeResult represents the result of a bulk ZIP code lookup
@@ -67,16 +163,21 @@ type ZIPCodeResult struct {
 	Request  *models.ZIPCodeRequest
 	Response *models.ZIPCodeResponse
 	Error    error
+	Duration time.Duration // Time from the first attempt to the final result, including retries
 }
 
 // BulkProcessor handles bulk operations with rate limiting and retries
 type BulkProcessor struct {
 	client  *Client
 	config  *BulkConfig
-	limiter *rateLimiter
+	limiter Limiter
 }
 
-// NewBulkProcessor creates a new BulkProcessor with the given client and config
+// NewBulkProcessor creates a new BulkProcessor with the given client and config.
+// The config is copied, and any value rejected by BulkConfig.Validate is
+// replaced with its default from DefaultBulkConfig, so a zero or negative
+// MaxConcurrency never blocks processing. Call Validate first to detect or
+// log such adjustments.
 func NewBulkProcessor(client *Client, config *BulkConfig) *BulkProcessor {
 	defaults := DefaultBulkConfig()
 	if config == nil {
@@ -102,8 +203,19 @@ func NewBulkProcessor(client *Client, config *BulkConfig) *BulkProcessor {
 	return &BulkProcessor{
 		client:  client,
 		config:  config,
-		limiter: newRateLimiter(config.RequestsPerSecond),
+		limiter: newBulkLimiter(config),
+	}
+}
+
+// newBulkLimiter returns the custom Limiter from config, or a built-in one
+func newBulkLimiter(config *BulkConfig) Limiter {
+	if config.Limiter != nil {
+		return config.Limiter
+	}
+	if config.AdaptiveRateLimit {
+		return newAdaptiveRateLimiter(config.RequestsPerSecond)
 	}
+	return newRateLimiter(config.RequestsPerSecond)
 }
 
 // rateLimiter implements a simple token bucket rate limiter using only stdlib
@@ -128,8 +240,8 @@ func newRateLimiter(requestsPerSecond int) *rateLimiter {
 	}
 }
 
-// wait blocks until a token is available, respecting context cancellation
-func (rl *rateLimiter) wait(ctx context.Context) error {
+// Wait blocks until a token is available, respecting context cancellation
+func (rl *rateLimiter) Wait(ctx context.Context) error {
 	for {
 		select {
 		case <-ctx.Done():
@@ -165,6 +277,7 @@ func (rl *rateLimiter) wait(ctx context.Context) error {
 			return nil
 		}
 
+		poll := rl.refillRate / 2
 		rl.mu.Unlock()
 
 		// Sleep briefly before retrying (half the refill rate to poll efficiently
@@ -173,7 +286,89 @@ func (rl *rateLimiter) wait(ctx context.Context) error {
 		select {
 		case <-ctx.Done():
 			return ctx.Err()
-		case <-time.After(rl.refillRate / 2):
+		case <-time.After(poll):
+		}
+	}
+}
+
+// maxAdaptiveRefillRate is the slowest rate the adaptive limiter backs off
+// to, one request per second
+const maxAdaptiveRefillRate = time.Second
+
+// responseObserver is implemented by limiters that adjust to API responses
+type responseObserver interface {
+	observeResponse(err error)
+}
+
+// adaptiveRateLimiter is a rateLimiter that slows down when the API responds
+// with 429 Too Many Requests and speeds back up as requests succeed
+type adaptiveRateLimiter struct {
+	*rateLimiter
+	baseRefillRate time.Duration
+	pausedUntil    time.Time // guarded by rateLimiter.mu
+}
+
+// newAdaptiveRateLimiter creates an adaptive rate limiter starting at
+// requestsPerSecond
+func newAdaptiveRateLimiter(requestsPerSecond int) *adaptiveRateLimiter {
+	rl := newRateLimiter(requestsPerSecond)
+	return &adaptiveRateLimiter{
+		rateLimiter:    rl,
+		baseRefillRate: rl.refillRate,
+	}
+}
+
+// Wait blocks until any Retry-After pause has passed and a token is available
+func (al *adaptiveRateLimiter) Wait(ctx context.Context) error {
+	al.mu.Lock()
+	pause := time.Until(al.pausedUntil)
+	al.mu.Unlock()
+
+	if pause > 0 {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(pause):
+		}
+	}
+	return al.rateLimiter.Wait(ctx)
+}
+
+// observeResponse halves the rate on a 429 response, pausing for its
+// Retry-After duration if present, and recovers a tenth of the way back to
+// the base rate on success
+func (al *adaptiveRateLimiter) observeResponse(err error) {
+	al.mu.Lock()
+	defer al.mu.Unlock()
+
+	if err == nil {
+		step := (al.refillRate - al.baseRefillRate) / 10
+		if step < al.baseRefillRate/100 {
+			al.refillRate = al.baseRefillRate
+		} else {
+			al.refillRate -= step
+		}
+		return
+	}
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusTooManyRequests {
+		return
+	}
+
+	now := time.Now()
+	al.refillRate *= 2
+	if al.refillRate > maxAdaptiveRefillRate {
+		al.refillRate = maxAdaptiveRefillRate
+	}
+	if al.refillRate < al.baseRefillRate {
+		al.refillRate = al.baseRefillRate
+	}
+	al.tokens = 0
+	al.lastRefill = now
+	if retryAfter, ok := apiErr.RetryAfterDuration(); ok {
+		if until := now.Add(retryAfter); until.After(al.pausedUntil) {
+			al.pausedUntil = until
 		}
 	}
 }
@@ -185,11 +380,12 @@ func (bp *BulkProcessor) ProcessAddresses(ctx context.Context, requests []*model
 		results[i] = &AddressResult{Index: i, Request: requests[i]}
 	}
 
-	bp.processBulk(ctx, len(requests), func(idx int, limiter *rateLimiter) error {
-		resp, err := bp.processWithRetry(ctx, limiter, func() (interface{}, error) {
+	bp.processBulk(ctx, len(requests), func(idx int, limiter Limiter) error {
+		resp, elapsed, err := bp.processWithRetry(ctx, limiter, func() (interface{}, error) {
 			return bp.client.GetAddress(ctx, requests[idx])
 		})
 
+		results[idx].Duration = elapsed
 		if err != nil {
 			results[idx].Error = err
 		} else {
@@ -205,6 +401,150 @@ func (bp *BulkProcessor) ProcessAddresses(ctx context.Context, requests []*model
 	return results
 }
 
+// maxStreamLineSize is the longest NDJSON line ProcessAddressesReader accepts.
+const maxStreamLineSize = 1 << 20
+
+// ProcessAddressesReader validates addresses read lazily from r, which must
+// contain one JSON-encoded AddressRequest per line (NDJSON). Lines are decoded
+// only as worker slots free up, so memory use is bounded by MaxConcurrency
+// regardless of input size. Results are sent on the returned channel in
+// completion order, with Index set to the zero-based line number; the channel
+// is closed once all lines are processed, r fails, or ctx is canceled.
+//
+// Blank lines are skipped. A malformed line produces a result whose Error
+// names the line number, and processing continues with the next line. Since
+// the total is unknown up front, ProgressCallback receives 0 as total. The
+// caller must drain the channel until it is closed.
+func (bp *BulkProcessor) ProcessAddressesReader(ctx context.Context, r io.Reader) <-chan *AddressResult {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxStreamLineSize)
+
+	line := -1
+	done := false
+	return bp.streamAddresses(ctx, func() (streamRequest, bool) {
+		if done {
+			return streamRequest{}, false
+		}
+		for scanner.Scan() {
+			line++
+			text := bytes.TrimSpace(scanner.Bytes())
+			if len(text) == 0 {
+				continue
+			}
+
+			var req models.AddressRequest
+			if err := json.Unmarshal(text, &req); err != nil {
+				return streamRequest{index: line, err: fmt.Errorf("line %d: invalid address request: %w", line+1, err)}, true
+			}
+			return streamRequest{index: line, req: &req}, true
+		}
+
+		done = true
+		if err := scanner.Err(); err != nil {
+			return streamRequest{index: line + 1, err: fmt.Errorf("line %d: failed to read address requests: %w", line+2, err)}, true
+		}
+		return streamRequest{}, false
+	})
+}
+
+// StreamAddresses validates addresses received from requests and sends each
+// result on the returned channel as soon as it completes, so neither the
+// requests nor the results need to be held in memory at once. Index is the
+// zero-based position of the request in the order it was received. Like
+// ProcessAddresses, at most MaxConcurrency requests are in flight and every
+// attempt waits for the rate limiter.
+//
+// The returned channel is closed once requests is closed and all in-flight
+// requests have finished, or, if ctx is canceled, once in-flight requests
+// have finished; requests is not drained after cancellation. Since the total
+// is unknown up front, ProgressCallback receives 0 as total. The caller must
+// drain the returned channel until it is closed.
+func (bp *BulkProcessor) StreamAddresses(ctx context.Context, requests <-chan *models.AddressRequest) <-chan *AddressResult {
+	idx := 0
+	return bp.streamAddresses(ctx, func() (streamRequest, bool) {
+		select {
+		case req, ok := <-requests:
+			next := streamRequest{index: idx, req: req}
+			idx++
+			return next, ok
+		case <-ctx.Done():
+			return streamRequest{}, false
+		}
+	})
+}
+
+// streamRequest is one input to streamAddresses: a request to validate, or
+// an error to report in its place without calling the API.
+type streamRequest struct {
+	index int
+	req   *models.AddressRequest
+	err   error
+}
+
+// streamAddresses runs the worker loop shared by StreamAddresses and
+// ProcessAddressesReader. next is called from a single goroutine, only once a
+// worker slot is free, and returns false when there is no more input.
+func (bp *BulkProcessor) streamAddresses(ctx context.Context, next func() (streamRequest, bool)) <-chan *AddressResult {
+	results := make(chan *AddressResult, bp.config.MaxConcurrency)
+
+	go func() {
+		defer close(results)
+
+		limiter := bp.sharedLimiter()
+		sem := make(chan struct{}, bp.config.MaxConcurrency)
+		var wg sync.WaitGroup
+		var completed int64
+		defer wg.Wait()
+
+		report := func(result *AddressResult) {
+			if bp.config.ProgressCallback != nil {
+				bp.config.ProgressCallback(int(atomic.AddInt64(&completed, 1)), 0, result.Error)
+			}
+			results <- result
+		}
+
+		for {
+			// Acquire a worker slot before taking the next request
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+
+			item, ok := next()
+			if !ok {
+				<-sem
+				return
+			}
+			if item.err != nil {
+				<-sem
+				report(&AddressResult{Index: item.index, Error: item.err})
+				continue
+			}
+
+			wg.Add(1)
+			go func(idx int, req *models.AddressRequest) {
+				defer wg.Done()
+				defer func() { <-sem }()
+
+				result := &AddressResult{Index: idx, Request: req}
+				resp, elapsed, err := bp.processWithRetry(ctx, limiter, func() (interface{}, error) {
+					return bp.client.GetAddress(ctx, req)
+				})
+				result.Duration = elapsed
+				if err != nil {
+					result.Error = err
+				} else {
+					result.Response = resp.(*models.AddressResponse)
+				}
+				report(result)
+			}(item.index, item.req)
+		}
+	}()
+
+	return results
+}
+
 // ProcessCityStates looks up city/state for multiple ZIP codes concurrently with rate limiting
 func (bp *BulkProcessor) ProcessCityStates(ctx context.Context, requests []*models.CityStateRequest) []*CityStateResult {
 	results := make([]*CityStateResult, len(requests))
@@ -212,11 +552,12 @@ func (bp *BulkProcessor) ProcessCityStates(ctx context.Context, requests []*mode
 		results[i] = &CityStateResult{Index: i, Request: requests[i]}
 	}
 
-	bp.processBulk(ctx, len(requests), func(idx int, limiter *rateLimiter) error {
-		resp, err := bp.processWithRetry(ctx, limiter, func() (interface{}, error) {
+	bp.processBulk(ctx, len(requests), func(idx int, limiter Limiter) error {
+		resp, elapsed, err := bp.processWithRetry(ctx, limiter, func() (interface{}, error) {
 			return bp.client.GetCityState(ctx, requests[idx])
 		})
 
+		results[idx].Duration = elapsed
 		if err != nil {
 			results[idx].Error = err
 		} else {
@@ -239,11 +580,12 @@ func (bp *BulkProcessor) ProcessZIPCodes(ctx context.Context, requests []*models
 		results[i] = &ZIPCodeResult{Index: i, Request: requests[i]}
 	}
 
-	bp.processBulk(ctx, len(requests), func(idx int, limiter *rateLimiter) error {
-		resp, err := bp.processWithRetry(ctx, limiter, func() (interface{}, error) {
+	bp.processBulk(ctx, len(requests), func(idx int, limiter Limiter) error {
+		resp, elapsed, err := bp.processWithRetry(ctx, limiter, func() (interface{}, error) {
 			return bp.client.GetZIPCode(ctx, requests[idx])
 		})
 
+		results[idx].Duration = elapsed
 		if err != nil {
 			results[idx].Error = err
 		} else {
@@ -264,14 +606,10 @@ func (bp *BulkProcessor) ProcessZIPCodes(ctx context.Context, requests []*models
 func (bp *BulkProcessor) processBulk(
 	ctx context.Context,
 	count int,
-	processFunc func(idx int, limiter *rateLimiter) error,
+	processFunc func(idx int, limiter Limiter) error,
 	progressFunc func(idx int, err error),
 ) {
-	limiter := bp.limiter
-	if limiter == nil {
-		limiter = newRateLimiter(bp.config.RequestsPerSecond)
-		bp.limiter = limiter
-	}
+	limiter := bp.sharedLimiter()
 	sem := make(chan struct{}, bp.config.MaxConcurrency)
 	var wg sync.WaitGroup
 
@@ -300,43 +638,72 @@ func (bp *BulkProcessor) processBulk(
 	wg.Wait()
 }
 
-// processWithRetry handles the retry logic with exponential backoff and rate limiting
+// sharedLimiter returns the limiter shared by all bulk methods, creating it on
+// first use if the processor was not built with NewBulkProcessor
+func (bp *BulkProcessor) sharedLimiter() Limiter {
+	if bp.limiter == nil {
+		bp.limiter = newBulkLimiter(bp.config)
+	}
+	return bp.limiter
+}
+
+// processWithRetry handles the retry logic with exponential backoff and rate
+// limiting. elapsed is the wall-clock time from the first attempt, after its
+// rate limiter wait, to the final result, including backoff and later waits;
+// it is zero if no attempt was made.
 func (bp *BulkProcessor) processWithRetry(
 	ctx context.Context,
-	limiter *rateLimiter,
+	limiter Limiter,
 	apiCall func() (interface{}, error),
-) (interface{}, error) {
-	var resp interface{}
-	var err error
+) (resp interface{}, elapsed time.Duration, err error) {
+	var start time.Time
+	defer func() {
+		if !start.IsZero() {
+			elapsed = time.Since(start)
+		}
+	}()
 
 	for attempt := 0; attempt <= bp.config.MaxRetries; attempt++ {
 		// Wait for rate limiter
-		if err := limiter.wait(ctx); err != nil {
-			return nil, err
+		if err := limiter.Wait(ctx); err != nil {
+			return nil, 0, err
+		}
+		if attempt == 0 {
+			start = time.Now()
 		}
 
 		resp, err = apiCall()
+		if al, ok := limiter.(responseObserver); ok {
+			al.observeResponse(err)
+		}
 		if err == nil {
-			return resp, nil
+			return resp, 0, nil
 		}
 
 		// Check if error is retryable
-		if !isRetryableError(err) {
-			return nil, err
+		decision := bp.client.classifyRetry(err)
+		if !decision.ShouldRetry() {
+			return nil, 0, err
 		}
 
-		// Exponential backoff
+		// Exponential backoff, unless the classifier asked for a fixed delay
 		if attempt < bp.config.MaxRetries {
 			backoff := calculateBackoff(bp.config.RetryBackoff, attempt)
+			if delay, ok := decision.Delay(); ok {
+				backoff = delay
+			}
+			if bp.config.BeforeRetry != nil && !bp.config.BeforeRetry(attempt+1, err, backoff) {
+				return nil, 0, err
+			}
 			select {
 			case <-ctx.Done():
-				return nil, ctx.Err()
+				return nil, 0, ctx.Err()
 			case <-time.After(backoff):
 			}
 		}
 	}
 
-	return nil, err
+	return nil, 0, err
 }
 
 // isRetryableError determines if an error should trigger a retry
@@ -346,16 +713,61 @@ func isRetryableError(err error) bool {
 	}
 
 	// Check for API errors
-	if apiErr, ok := err.(*APIError); ok {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
 		// Retry on 429 (rate limit), 500, 503 (service unavailable)
 		return apiErr.StatusCode == 429 || apiErr.StatusCode >= 500
 	}
 
-	// Don't retry on context errors
-	if err == context.Canceled || err == context.DeadlineExceeded {
+	// Retry when only the HTTP client's own timeout elapsed, but not when the
+	// caller canceled the context or its deadline passed
+	var timeoutErr *TimeoutError
+	if errors.As(err, &timeoutErr) {
+		return timeoutErr.ClientTimeout
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	// Don't retry requests rejected before they were sent
+	if errors.Is(err, ErrUnsupportedCountry) {
 		return false
 	}
 
 	// Retry on other network errors
 	return true
 }
+
+// GetCityStateBatch looks up city and state for a small, fixed set of ZIP codes.
+// It returns parallel slices aligned with zips: for each index, either the
+// response or the error is set. Lookups run concurrently using the default
+// bulk concurrency limit, without rate limiting or retries. Use a
+// BulkProcessor for large inputs that need either.
+func (c *Client) GetCityStateBatch(ctx context.Context, zips []string) ([]*models.CityStateResponse, []error) {
+	responses := make([]*models.CityStateResponse, len(zips))
+	errs := make([]error, len(zips))
+
+	sem := make(chan struct{}, DefaultBulkConfig().MaxConcurrency)
+	var wg sync.WaitGroup
+
+	for i, zip := range zips {
+		wg.Add(1)
+		go func(idx int, zip string) {
+			defer wg.Done()
+
+			// Acquire worker slot
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				errs[idx] = ctx.Err()
+				return
+			}
+
+			responses[idx], errs[idx] = c.GetCityState(ctx, &models.CityStateRequest{ZIPCode: zip})
+		}(i, zip)
+	}
+
+	wg.Wait()
+	return responses, errs
+}
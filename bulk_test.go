@@ -3,12 +3,15 @@ package usps
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"sync"
 	"sync/atomic"
 	"testing"
+	"testing/iotest"
 	"time"
 
 	"github.com/my-eq/go-usps/models"
@@ -100,6 +103,93 @@ func TestNewBulkProcessor(t *testing.T) {
 	})
 }
 
+func TestBulkConfig_Validate(t *testing.T) {
+	t.Run("defaults are valid", func(t *testing.T) {
+		if err := DefaultBulkConfig().Validate(); err != nil {
+			t.Errorf("Expected no error, got %v", err)
+		}
+	})
+
+	t.Run("nil config is valid", func(t *testing.T) {
+		var config *BulkConfig
+		if err := config.Validate(); err != nil {
+			t.Errorf("Expected no error, got %v", err)
+		}
+	})
+
+	t.Run("zero and negative values are reported", func(t *testing.T) {
+		config := &BulkConfig{
+			MaxConcurrency:    0,
+			RequestsPerSecond: -1,
+			MaxRetries:        -2,
+			RetryBackoff:      -time.Second,
+		}
+		err := config.Validate()
+		if err == nil {
+			t.Fatal("Expected error, got nil")
+		}
+		for _, field := range []string{"MaxConcurrency", "RequestsPerSecond", "MaxRetries", "RetryBackoff"} {
+			if !strings.Contains(err.Error(), field) {
+				t.Errorf("Expected error to mention %s, got %q", field, err.Error())
+			}
+		}
+	})
+
+	t.Run("custom limiter ignores RequestsPerSecond", func(t *testing.T) {
+		config := DefaultBulkConfig()
+		config.RequestsPerSecond = 0
+		config.Limiter = newRateLimiter(5)
+		if err := config.Validate(); err != nil {
+			t.Errorf("Expected no error, got %v", err)
+		}
+	})
+}
+
+func TestNewBulkProcessor_NegativeConcurrencyDoesNotHang(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(models.AddressResponse{
+			Address: &models.DomesticAddress{City: "NEW YORK", State: "NY"},
+		})
+	}))
+	defer server.Close()
+
+	client := NewClient(NewStaticTokenProvider("test-token"), WithBaseURL(server.URL))
+
+	for _, concurrency := range []int{0, -3} {
+		config := &BulkConfig{
+			MaxConcurrency:    concurrency,
+			RequestsPerSecond: -10,
+			MaxRetries:        -1,
+			RetryBackoff:      10 * time.Millisecond,
+		}
+		if config.Validate() == nil {
+			t.Errorf("Expected Validate to report MaxConcurrency=%d", concurrency)
+		}
+		processor := NewBulkProcessor(client, config)
+
+		if processor.config.MaxConcurrency <= 0 || processor.config.RequestsPerSecond <= 0 || processor.config.MaxRetries < 0 {
+			t.Fatalf("Expected corrected config, got %+v", processor.config)
+		}
+		if err := processor.config.Validate(); err != nil {
+			t.Errorf("Expected corrected config to validate, got %v", err)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		results := processor.ProcessAddresses(ctx, []*models.AddressRequest{
+			{StreetAddress: "123 Main St", City: "New York", State: "NY"},
+			{StreetAddress: "456 Oak Ave", City: "New York", State: "NY"},
+		})
+		cancel()
+
+		for i, result := range results {
+			if result == nil || result.Error != nil {
+				t.Errorf("MaxConcurrency=%d: result %d did not complete: %+v", concurrency, i, result)
+			}
+		}
+	}
+}
+
 func TestRateLimiter(t *testing.T) {
 	t.Run("basic rate limiting", func(t *testing.T) {
 		limiter := newRateLimiter(5) // 5 requests per second
@@ -109,7 +199,7 @@ func TestRateLimiter(t *testing.T) {
 
 		// First 5 requests should be immediate
 		for i := 0; i < 5; i++ {
-			if err := limiter.wait(ctx); err != nil {
+			if err := limiter.Wait(ctx); err != nil {
 				t.Fatalf("Unexpected error: %v", err)
 			}
 		}
@@ -120,7 +210,7 @@ func TestRateLimiter(t *testing.T) {
 		}
 
 		// Next request should wait
-		if err := limiter.wait(ctx); err != nil {
+		if err := limiter.Wait(ctx); err != nil {
 			t.Fatalf("Unexpected error: %v", err)
 		}
 
@@ -135,13 +225,13 @@ func TestRateLimiter(t *testing.T) {
 		ctx, cancel := context.WithCancel(context.Background())
 
 		// Exhaust tokens
-		_ = limiter.wait(ctx)
+		_ = limiter.Wait(ctx)
 
 		// Cancel context
 		cancel()
 
 		// Should return context error
-		err := limiter.wait(ctx)
+		err := limiter.Wait(ctx)
 		if err == nil {
 			t.Error("Expected error from cancelled context")
 		}
@@ -770,3 +860,799 @@ func TestBulkProcessor_SharesRateLimiterAcrossMethods(t *testing.T) {
 		t.Errorf("Shared rate limiter not enforced: concurrent batches completed in %v (expected at least %v)", duration, expectedMin)
 	}
 }
+
+func TestGetCityStateBatch(t *testing.T) {
+	cities := map[string]string{
+		"10001": "NEW YORK",
+		"02101": "BOSTON",
+		"60601": "CHICAGO",
+		"98101": "SEATTLE",
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		zip := r.URL.Query().Get("ZIPCode")
+		city, ok := cities[zip]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			_ = json.NewEncoder(w).Encode(models.ErrorMessage{
+				Error: &models.ErrorInfo{Message: "ZIP code not found"},
+			})
+			return
+		}
+
+		// Respond to earlier ZIPs more slowly so completion order differs from input order
+		if zip == "10001" {
+			time.Sleep(30 * time.Millisecond)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(models.CityStateResponse{City: city, ZIPCode: zip})
+	}))
+	defer server.Close()
+
+	client := NewClient(NewStaticTokenProvider("test-token"), WithBaseURL(server.URL))
+
+	zips := []string{"10001", "99999", "02101", "60601", "98101"}
+	responses, errs := client.GetCityStateBatch(context.Background(), zips)
+
+	if len(responses) != len(zips) || len(errs) != len(zips) {
+		t.Fatalf("Expected %d results, got %d responses and %d errors", len(zips), len(responses), len(errs))
+	}
+
+	for i, zip := range zips {
+		if zip == "99999" {
+			if errs[i] == nil {
+				t.Errorf("Expected error for ZIP %s", zip)
+			} else if apiErr, ok := errs[i].(*APIError); !ok || apiErr.StatusCode != http.StatusNotFound {
+				t.Errorf("Expected 404 APIError for ZIP %s, got %v", zip, errs[i])
+			}
+			if responses[i] != nil {
+				t.Errorf("Expected nil response for ZIP %s", zip)
+			}
+			continue
+		}
+
+		if errs[i] != nil {
+			t.Errorf("Unexpected error for ZIP %s: %v", zip, errs[i])
+			continue
+		}
+		if responses[i].ZIPCode != zip {
+			t.Errorf("Result %d has ZIP %s, want %s", i, responses[i].ZIPCode, zip)
+		}
+		if responses[i].City != cities[zip] {
+			t.Errorf("Result %d has city %s, want %s", i, responses[i].City, cities[zip])
+		}
+	}
+}
+
+func TestGetCityStateBatch_ContextCanceled(t *testing.T) {
+	var requestCount int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requestCount, 1)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(models.CityStateResponse{City: "NEW YORK"})
+	}))
+	defer server.Close()
+
+	client := NewClient(NewStaticTokenProvider("test-token"), WithBaseURL(server.URL))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	responses, errs := client.GetCityStateBatch(ctx, []string{"10001", "02101"})
+	for i := range errs {
+		if errs[i] == nil {
+			t.Errorf("Expected error for canceled context at index %d", i)
+		}
+		if responses[i] != nil {
+			t.Errorf("Expected nil response at index %d", i)
+		}
+	}
+}
+
+// countingLimiter is a fake distributed limiter that records each Wait call
+type countingLimiter struct {
+	calls int32
+	err   error
+}
+
+func (l *countingLimiter) Wait(ctx context.Context) error {
+	atomic.AddInt32(&l.calls, 1)
+	return l.err
+}
+
+func TestBulkProcessor_CustomLimiter(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(models.CityStateResponse{City: "NEW YORK", State: "NY"})
+	}))
+	defer server.Close()
+
+	client := NewClient(NewStaticTokenProvider("test-token"), WithBaseURL(server.URL))
+	limiter := &countingLimiter{}
+	processor := NewBulkProcessor(client, &BulkConfig{
+		MaxConcurrency: 2,
+		MaxRetries:     1,
+		RetryBackoff:   10 * time.Millisecond,
+		Limiter:        limiter,
+	})
+
+	if processor.limiter != limiter {
+		t.Fatalf("Expected custom limiter to be used, got %T", processor.limiter)
+	}
+
+	requests := []*models.CityStateRequest{{ZIPCode: "10001"}, {ZIPCode: "10002"}, {ZIPCode: "10003"}}
+	results := processor.ProcessCityStates(context.Background(), requests)
+
+	for i, result := range results {
+		if result.Error != nil {
+			t.Errorf("Result %d has unexpected error: %v", i, result.Error)
+		}
+	}
+	if got := atomic.LoadInt32(&limiter.calls); got != int32(len(requests)) {
+		t.Errorf("Expected limiter to be called %d times, got %d", len(requests), got)
+	}
+}
+
+func TestBulkProcessor_CustomLimiterError(t *testing.T) {
+	var requestCount int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requestCount, 1)
+	}))
+	defer server.Close()
+
+	client := NewClient(NewStaticTokenProvider("test-token"), WithBaseURL(server.URL))
+	limiterErr := errors.New("redis unavailable")
+	processor := NewBulkProcessor(client, &BulkConfig{Limiter: &countingLimiter{err: limiterErr}})
+
+	results := processor.ProcessCityStates(context.Background(), []*models.CityStateRequest{{ZIPCode: "10001"}})
+
+	if !errors.Is(results[0].Error, limiterErr) {
+		t.Errorf("Expected limiter error, got %v", results[0].Error)
+	}
+	if atomic.LoadInt32(&requestCount) != 0 {
+		t.Errorf("Expected no HTTP requests when limiter fails, got %d", requestCount)
+	}
+}
+
+func newAlwaysFailingServer(calls *int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(calls, 1)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_ = json.NewEncoder(w).Encode(models.ErrorMessage{
+			Error: &models.ErrorInfo{Message: "Service unavailable"},
+		})
+	}))
+}
+
+func TestBulkProcessor_BeforeRetry(t *testing.T) {
+	var calls int32
+	server := newAlwaysFailingServer(&calls)
+	defer server.Close()
+
+	client := NewClient(NewStaticTokenProvider("test-token"), WithBaseURL(server.URL))
+
+	var attempts []int
+	var backoffs []time.Duration
+	processor := NewBulkProcessor(client, &BulkConfig{
+		MaxConcurrency:    1,
+		RequestsPerSecond: 100,
+		MaxRetries:        3,
+		RetryBackoff:      time.Millisecond,
+		BeforeRetry: func(attempt int, err error, backoff time.Duration) bool {
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusServiceUnavailable {
+				t.Errorf("Expected 503 APIError, got %v", err)
+			}
+			attempts = append(attempts, attempt)
+			backoffs = append(backoffs, backoff)
+			return true
+		},
+	})
+
+	results := processor.ProcessCityStates(context.Background(), []*models.CityStateRequest{{ZIPCode: "10001"}})
+	if results[0].Error == nil {
+		t.Fatal("Expected error after exhausting retries")
+	}
+
+	wantAttempts := []int{1, 2, 3}
+	wantBackoffs := []time.Duration{time.Millisecond, 2 * time.Millisecond, 4 * time.Millisecond}
+	if len(attempts) != len(wantAttempts) {
+		t.Fatalf("Expected %d BeforeRetry calls, got %d", len(wantAttempts), len(attempts))
+	}
+	for i := range wantAttempts {
+		if attempts[i] != wantAttempts[i] {
+			t.Errorf("Expected attempt %d, got %d", wantAttempts[i], attempts[i])
+		}
+		if backoffs[i] != wantBackoffs[i] {
+			t.Errorf("Expected backoff %v, got %v", wantBackoffs[i], backoffs[i])
+		}
+	}
+	if got := atomic.LoadInt32(&calls); got != 4 {
+		t.Errorf("Expected 4 calls, got %d", got)
+	}
+}
+
+func TestBulkProcessor_PrefersRetryAfter(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusTooManyRequests)
+			_ = json.NewEncoder(w).Encode(models.ErrorMessage{
+				Error: &models.ErrorInfo{Code: "429", Message: "Too many requests"},
+			})
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(models.CityStateResponse{City: "NEW YORK", State: "NY", ZIPCode: "10001"})
+	}))
+	defer server.Close()
+
+	client := NewClient(NewStaticTokenProvider("test-token"), WithBaseURL(server.URL))
+
+	var backoffs []time.Duration
+	processor := NewBulkProcessor(client, &BulkConfig{
+		MaxConcurrency:    1,
+		RequestsPerSecond: 100,
+		MaxRetries:        1,
+		RetryBackoff:      time.Millisecond,
+		BeforeRetry: func(attempt int, err error, backoff time.Duration) bool {
+			backoffs = append(backoffs, backoff)
+			return true
+		},
+	})
+
+	results := processor.ProcessCityStates(context.Background(), []*models.CityStateRequest{{ZIPCode: "10001"}})
+	if results[0].Error != nil {
+		t.Fatalf("Expected success after retry, got %v", results[0].Error)
+	}
+	if len(backoffs) != 1 || backoffs[0] != time.Second {
+		t.Errorf("Expected a single 1s backoff from Retry-After, got %v", backoffs)
+	}
+}
+
+func TestBulkProcessor_PlainTextRateLimit(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			// A gateway throttles with a plain-text body
+			w.Header().Set("Retry-After", "1")
+			w.Header().Set("Content-Type", "text/plain")
+			w.WriteHeader(http.StatusTooManyRequests)
+			_, _ = w.Write([]byte("Too Many Requests"))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(models.CityStateResponse{City: "NEW YORK", State: "NY", ZIPCode: "10001"})
+	}))
+	defer server.Close()
+
+	client := NewClient(NewStaticTokenProvider("test-token"), WithBaseURL(server.URL))
+
+	var backoffs []time.Duration
+	processor := NewBulkProcessor(client, &BulkConfig{
+		MaxConcurrency:    1,
+		RequestsPerSecond: 100,
+		MaxRetries:        1,
+		RetryBackoff:      time.Millisecond,
+		AdaptiveRateLimit: true,
+		BeforeRetry: func(attempt int, err error, backoff time.Duration) bool {
+			backoffs = append(backoffs, backoff)
+			return true
+		},
+	})
+
+	results := processor.ProcessCityStates(context.Background(), []*models.CityStateRequest{{ZIPCode: "10001"}})
+	if results[0].Error != nil {
+		t.Fatalf("Expected success after retry, got %v", results[0].Error)
+	}
+	if len(backoffs) != 1 || backoffs[0] != time.Second {
+		t.Errorf("Expected a single 1s backoff from Retry-After, got %v", backoffs)
+	}
+
+	limiter, ok := processor.limiter.(*adaptiveRateLimiter)
+	if !ok {
+		t.Fatalf("Expected adaptive rate limiter, got %T", processor.limiter)
+	}
+	limiter.mu.Lock()
+	refillRate := limiter.refillRate
+	limiter.mu.Unlock()
+	if refillRate <= limiter.baseRefillRate {
+		t.Errorf("Expected the plain-text 429 to reduce the rate, got refill interval %v (base %v)", refillRate, limiter.baseRefillRate)
+	}
+}
+
+func TestBulkProcessor_AdaptiveRateLimit(t *testing.T) {
+	const throttled = 3
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if atomic.AddInt32(&calls, 1) <= throttled {
+			w.WriteHeader(http.StatusTooManyRequests)
+			_ = json.NewEncoder(w).Encode(models.ErrorMessage{
+				Error: &models.ErrorInfo{Code: "429", Message: "Too many requests"},
+			})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(models.CityStateResponse{City: "NEW YORK", State: "NY", ZIPCode: "10001"})
+	}))
+	defer server.Close()
+
+	client := NewClient(NewStaticTokenProvider("test-token"), WithBaseURL(server.URL))
+	processor := NewBulkProcessor(client, &BulkConfig{
+		MaxConcurrency:    1,
+		RequestsPerSecond: 100,
+		MaxRetries:        throttled,
+		RetryBackoff:      time.Millisecond,
+		AdaptiveRateLimit: true,
+	})
+
+	requests := make([]*models.CityStateRequest, 5)
+	for i := range requests {
+		requests[i] = &models.CityStateRequest{ZIPCode: "10001"}
+	}
+
+	start := time.Now()
+	results := processor.ProcessCityStates(context.Background(), requests)
+	elapsed := time.Since(start)
+
+	for i, result := range results {
+		if result.Error != nil {
+			t.Errorf("Request %d: expected success, got %v", i, result.Error)
+		}
+	}
+
+	// Without adaptation the burst of 100 tokens would let every request
+	// through immediately; after three 429s each request waits for a refill
+	if elapsed < 100*time.Millisecond {
+		t.Errorf("Expected the processor to slow down after 429s, took %v", elapsed)
+	}
+
+	limiter, ok := processor.limiter.(*adaptiveRateLimiter)
+	if !ok {
+		t.Fatalf("Expected adaptive rate limiter, got %T", processor.limiter)
+	}
+	limiter.mu.Lock()
+	refillRate := limiter.refillRate
+	limiter.mu.Unlock()
+	if refillRate <= limiter.baseRefillRate {
+		t.Errorf("Expected reduced rate, got refill interval %v (base %v)", refillRate, limiter.baseRefillRate)
+	}
+
+	// Successes bring the rate back to RequestsPerSecond
+	for i := 0; i < 100; i++ {
+		limiter.observeResponse(nil)
+	}
+	if limiter.refillRate != limiter.baseRefillRate {
+		t.Errorf("Expected refill interval to recover to %v, got %v", limiter.baseRefillRate, limiter.refillRate)
+	}
+}
+
+func TestAdaptiveRateLimiter_RetryAfterPause(t *testing.T) {
+	limiter := newAdaptiveRateLimiter(100)
+	limiter.observeResponse(&APIError{StatusCode: http.StatusTooManyRequests, RetryAfter: 50 * time.Millisecond})
+
+	start := time.Now()
+	if err := limiter.Wait(context.Background()); err != nil {
+		t.Fatalf("Wait failed: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Errorf("Expected Wait to pause for Retry-After, took %v", elapsed)
+	}
+
+	// Other errors leave the rate unchanged
+	limiter = newAdaptiveRateLimiter(100)
+	limiter.observeResponse(&APIError{StatusCode: http.StatusServiceUnavailable})
+	if limiter.refillRate != limiter.baseRefillRate {
+		t.Errorf("Expected unchanged refill interval for 503, got %v", limiter.refillRate)
+	}
+}
+
+func TestNewBulkProcessor_AdaptiveRateLimitDisabledByDefault(t *testing.T) {
+	client := NewClient(NewStaticTokenProvider("test-token"))
+
+	if _, ok := NewBulkProcessor(client, nil).limiter.(*rateLimiter); !ok {
+		t.Error("Expected the plain rate limiter by default")
+	}
+	custom := &countingLimiter{}
+	processor := NewBulkProcessor(client, &BulkConfig{Limiter: custom, AdaptiveRateLimit: true})
+	if processor.limiter != Limiter(custom) {
+		t.Error("Expected a custom Limiter to take precedence over AdaptiveRateLimit")
+	}
+}
+
+func TestBulkProcessor_CanceledMidRetryIsNotRetried(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			// The first attempt fails and is retried
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		// The caller gives up while the retry is in flight
+		cancel()
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	client := NewClient(NewStaticTokenProvider("test-token"), WithBaseURL(server.URL))
+
+	var retryErrs []error
+	processor := NewBulkProcessor(client, &BulkConfig{
+		MaxConcurrency:    1,
+		RequestsPerSecond: 100,
+		MaxRetries:        3,
+		RetryBackoff:      time.Millisecond,
+		BeforeRetry: func(attempt int, err error, backoff time.Duration) bool {
+			retryErrs = append(retryErrs, err)
+			return true
+		},
+	})
+
+	results := processor.ProcessCityStates(ctx, []*models.CityStateRequest{{ZIPCode: "10001"}})
+
+	if !errors.Is(results[0].Error, ErrRequestCanceled) {
+		t.Errorf("Expected ErrRequestCanceled, got %v", results[0].Error)
+	}
+	if len(retryErrs) != 1 {
+		t.Errorf("Expected 1 BeforeRetry call for the 503 only, got %d: %v", len(retryErrs), retryErrs)
+	}
+	if got := atomic.LoadInt32(&calls); got != 2 {
+		t.Errorf("Expected 2 calls, got %d", got)
+	}
+}
+
+func TestBulkProcessor_BeforeRetryVeto(t *testing.T) {
+	var calls int32
+	server := newAlwaysFailingServer(&calls)
+	defer server.Close()
+
+	client := NewClient(NewStaticTokenProvider("test-token"), WithBaseURL(server.URL))
+
+	hookCalls := 0
+	processor := NewBulkProcessor(client, &BulkConfig{
+		MaxConcurrency:    1,
+		RequestsPerSecond: 100,
+		MaxRetries:        3,
+		RetryBackoff:      time.Millisecond,
+		BeforeRetry: func(attempt int, err error, backoff time.Duration) bool {
+			hookCalls++
+			return attempt < 2
+		},
+	})
+
+	results := processor.ProcessCityStates(context.Background(), []*models.CityStateRequest{{ZIPCode: "10001"}})
+
+	var apiErr *APIError
+	if !errors.As(results[0].Error, &apiErr) {
+		t.Fatalf("Expected APIError after veto, got %v", results[0].Error)
+	}
+	if hookCalls != 2 {
+		t.Errorf("Expected 2 BeforeRetry calls, got %d", hookCalls)
+	}
+	if got := atomic.LoadInt32(&calls); got != 2 {
+		t.Errorf("Expected 2 calls before veto, got %d", got)
+	}
+}
+
+func TestProcessAddressesReader(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		resp := models.AddressResponse{
+			Address: &models.DomesticAddress{
+				Address: models.Address{StreetAddress: strings.ToUpper(r.URL.Query().Get("streetAddress"))},
+				State:   r.URL.Query().Get("state"),
+			},
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+
+	client := NewClient(NewStaticTokenProvider("test-token"), WithBaseURL(server.URL))
+	processor := NewBulkProcessor(client, &BulkConfig{
+		MaxConcurrency:    2,
+		RequestsPerSecond: 100,
+		MaxRetries:        0,
+	})
+
+	input := strings.Join([]string{
+		`{"streetAddress": "1 Main St", "city": "Springfield", "state": "IL"}`,
+		`{"streetAddress": "2 Main St", "city": "Springfield", "state": "IL"}`,
+		`{"streetAddress": "3 Main St", "city": `,
+		``,
+		`{"streetAddress": "5 Main St", "city": "Springfield", "state": "IL"}`,
+	}, "\n")
+
+	results := make(map[int]*AddressResult)
+	for result := range processor.ProcessAddressesReader(context.Background(), strings.NewReader(input)) {
+		results[result.Index] = result
+	}
+
+	if len(results) != 4 {
+		t.Fatalf("Expected 4 results, got %d", len(results))
+	}
+
+	for _, idx := range []int{0, 1, 4} {
+		result, ok := results[idx]
+		if !ok {
+			t.Errorf("Expected result for line index %d", idx)
+			continue
+		}
+		if result.Error != nil {
+			t.Errorf("Line index %d: expected no error, got %v", idx, result.Error)
+			continue
+		}
+		want := fmt.Sprintf("%d MAIN ST", idx+1)
+		if result.Response.Address.StreetAddress != want {
+			t.Errorf("Line index %d: expected street %q, got %q", idx, want, result.Response.Address.StreetAddress)
+		}
+	}
+
+	bad, ok := results[2]
+	if !ok || bad.Error == nil {
+		t.Fatalf("Expected error result for malformed line, got %+v", bad)
+	}
+	if !strings.Contains(bad.Error.Error(), "line 3") {
+		t.Errorf("Expected error to name line 3, got %q", bad.Error.Error())
+	}
+}
+
+func TestStreamAddresses(t *testing.T) {
+	var inFlight, maxInFlight int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			max := atomic.LoadInt32(&maxInFlight)
+			if n <= max || atomic.CompareAndSwapInt32(&maxInFlight, max, n) {
+				break
+			}
+		}
+		time.Sleep(time.Millisecond)
+
+		resp := models.AddressResponse{
+			Address: &models.DomesticAddress{
+				Address: models.Address{StreetAddress: strings.ToUpper(r.URL.Query().Get("streetAddress"))},
+			},
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+
+	client := NewClient(NewStaticTokenProvider("test-token"), WithBaseURL(server.URL))
+	processor := NewBulkProcessor(client, &BulkConfig{
+		MaxConcurrency:    4,
+		RequestsPerSecond: 1000,
+		MaxRetries:        0,
+	})
+
+	const count = 100
+	requests := make(chan *models.AddressRequest)
+	go func() {
+		defer close(requests)
+		for i := 0; i < count; i++ {
+			requests <- &models.AddressRequest{StreetAddress: fmt.Sprintf("%d Main St", i), State: "IL"}
+		}
+	}()
+
+	seen := make(map[int]bool)
+	for result := range processor.StreamAddresses(context.Background(), requests) {
+		if result.Error != nil {
+			t.Errorf("Index %d: expected no error, got %v", result.Index, result.Error)
+			continue
+		}
+		if seen[result.Index] {
+			t.Errorf("Index %d: received more than once", result.Index)
+		}
+		seen[result.Index] = true
+
+		want := fmt.Sprintf("%d MAIN ST", result.Index)
+		if result.Response.Address.StreetAddress != want {
+			t.Errorf("Index %d: expected street %q, got %q", result.Index, want, result.Response.Address.StreetAddress)
+		}
+		if result.Request.StreetAddress != fmt.Sprintf("%d Main St", result.Index) {
+			t.Errorf("Index %d: result carries request %q", result.Index, result.Request.StreetAddress)
+		}
+	}
+
+	if len(seen) != count {
+		t.Errorf("Expected %d results, got %d", count, len(seen))
+	}
+	if max := atomic.LoadInt32(&maxInFlight); max > 4 {
+		t.Errorf("Expected at most 4 concurrent requests, got %d", max)
+	}
+}
+
+func TestStreamAddresses_Canceled(t *testing.T) {
+	var calls int32
+	server := newAlwaysFailingServer(&calls)
+	defer server.Close()
+
+	client := NewClient(NewStaticTokenProvider("test-token"), WithBaseURL(server.URL))
+	processor := NewBulkProcessor(client, &BulkConfig{
+		MaxConcurrency:    1,
+		RequestsPerSecond: 100,
+		MaxRetries:        0,
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// The input channel is never closed; cancellation alone must close the output
+	requests := make(chan *models.AddressRequest)
+	for range processor.StreamAddresses(ctx, requests) {
+	}
+
+	if got := atomic.LoadInt32(&calls); got != 0 {
+		t.Errorf("Expected no requests after cancellation, got %d", got)
+	}
+}
+
+func TestProcessAddressesReader_Canceled(t *testing.T) {
+	var calls int32
+	server := newAlwaysFailingServer(&calls)
+	defer server.Close()
+
+	client := NewClient(NewStaticTokenProvider("test-token"), WithBaseURL(server.URL))
+	processor := NewBulkProcessor(client, &BulkConfig{
+		MaxConcurrency:    1,
+		RequestsPerSecond: 100,
+		MaxRetries:        0,
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	input := `{"streetAddress": "1 Main St", "state": "IL"}` + "\n" + `{"streetAddress": "2 Main St", "state": "IL"}`
+	for range processor.ProcessAddressesReader(ctx, strings.NewReader(input)) {
+	}
+
+	if got := atomic.LoadInt32(&calls); got != 0 {
+		t.Errorf("Expected no requests after cancellation, got %d", got)
+	}
+}
+
+func TestProcessAddressesReader_ReadError(t *testing.T) {
+	client := NewClient(NewStaticTokenProvider("test-token"), WithBaseURL("http://127.0.0.1:1"))
+	processor := NewBulkProcessor(client, nil)
+
+	readErr := errors.New("disk failure")
+	var results []*AddressResult
+	for result := range processor.ProcessAddressesReader(context.Background(), iotest.ErrReader(readErr)) {
+		results = append(results, result)
+	}
+
+	if len(results) != 1 {
+		t.Fatalf("Expected 1 result, got %d", len(results))
+	}
+	if !errors.Is(results[0].Error, readErr) {
+		t.Errorf("Expected read error, got %v", results[0].Error)
+	}
+}
+
+func TestMergeAddressResults(t *testing.T) {
+	result := func(i int) *AddressResult {
+		return &AddressResult{Index: i, Request: &models.AddressRequest{StreetAddress: fmt.Sprintf("%d MAIN ST", i)}}
+	}
+
+	// Two shards with interleaved global indices, each in completion order
+	even := []*AddressResult{result(4), result(0), result(2)}
+	odd := []*AddressResult{result(3), result(1)}
+
+	merged, err := MergeAddressResults(even, odd)
+	if err != nil {
+		t.Fatalf("MergeAddressResults failed: %v", err)
+	}
+	if len(merged) != 5 {
+		t.Fatalf("Expected 5 results, got %d", len(merged))
+	}
+	for i, r := range merged {
+		if r.Index != i {
+			t.Errorf("Position %d: expected Index %d, got %d", i, i, r.Index)
+		}
+		if want := fmt.Sprintf("%d MAIN ST", i); r.Request.StreetAddress != want {
+			t.Errorf("Position %d: expected request %q, got %q", i, want, r.Request.StreetAddress)
+		}
+	}
+}
+
+func TestMergeAddressResults_Inconsistent(t *testing.T) {
+	result := func(i int) *AddressResult { return &AddressResult{Index: i} }
+
+	tests := []struct {
+		name    string
+		shards  [][]*AddressResult
+		wantErr string
+	}{
+		{"duplicate", [][]*AddressResult{{result(0), result(1)}, {result(1), result(2)}}, "duplicate index 1"},
+		{"gap", [][]*AddressResult{{result(0), result(1)}, {result(3)}}, "missing index 2"},
+		{"negative", [][]*AddressResult{{result(-1)}}, "negative index -1"},
+		{"nil result", [][]*AddressResult{{result(0), nil}}, "nil result"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			merged, err := MergeAddressResults(tt.shards...)
+			if err == nil {
+				t.Fatalf("Expected error, got %d results", len(merged))
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestBulkProcessor_ResultDuration(t *testing.T) {
+	const latency = 50 * time.Millisecond
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(latency)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"city":"NEW YORK","state":"NY","address":{"streetAddress":"123 MAIN ST"}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(NewStaticTokenProvider("test-token"), WithBaseURL(server.URL))
+	processor := NewBulkProcessor(client, &BulkConfig{
+		MaxConcurrency:    2,
+		RequestsPerSecond: 100,
+		MaxRetries:        1,
+		RetryBackoff:      time.Millisecond,
+	})
+
+	var durations []time.Duration
+	for _, r := range processor.ProcessAddresses(context.Background(), []*models.AddressRequest{{StreetAddress: "123 Main St", State: "NY"}}) {
+		durations = append(durations, r.Duration)
+	}
+	for _, r := range processor.ProcessCityStates(context.Background(), []*models.CityStateRequest{{ZIPCode: "10001"}}) {
+		durations = append(durations, r.Duration)
+	}
+	for _, r := range processor.ProcessZIPCodes(context.Background(), []*models.ZIPCodeRequest{{StreetAddress: "123 Main St", State: "NY"}}) {
+		durations = append(durations, r.Duration)
+	}
+
+	for i, d := range durations {
+		if d < latency || d > latency+time.Second {
+			t.Errorf("Result %d: expected duration of about %v, got %v", i, latency, d)
+		}
+	}
+}
+
+func TestBulkProcessor_ResultDurationIncludesRetries(t *testing.T) {
+	const latency = 30 * time.Millisecond
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(latency)
+		w.Header().Set("Content-Type", "application/json")
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = w.Write([]byte(`{"error":{"code":"503","message":"Service Unavailable"}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"city":"NEW YORK","state":"NY"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(NewStaticTokenProvider("test-token"), WithBaseURL(server.URL))
+	processor := NewBulkProcessor(client, &BulkConfig{
+		MaxConcurrency:    1,
+		RequestsPerSecond: 100,
+		MaxRetries:        1,
+		RetryBackoff:      20 * time.Millisecond,
+	})
+
+	results := processor.ProcessCityStates(context.Background(), []*models.CityStateRequest{{ZIPCode: "10001"}})
+	if results[0].Error != nil {
+		t.Fatalf("Expected success after retry, got %v", results[0].Error)
+	}
+	if want := 2*latency + 20*time.Millisecond; results[0].Duration < want {
+		t.Errorf("Expected duration of at least %v, got %v", want, results[0].Duration)
+	}
+}
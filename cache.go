@@ -0,0 +1,115 @@
+package usps
+
+import (
+	"container/list"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/my-eq/go-usps/models"
+)
+
+// DefaultCityStateCacheSize is the maximum number of ZIP codes kept by the
+// cache enabled with WithCityStateCache. When full, the least recently used
+// entry is evicted.
+const DefaultCityStateCacheSize = 10000
+
+// WithCityStateCache caches GetCityState responses in memory, keyed by ZIP
+// code, for ttl. City and state assignments rarely change, so repeated lookups
+// of the same ZIP within ttl are answered without an HTTP request or quota use.
+// Errors are never cached. The cache holds at most DefaultCityStateCacheSize
+// entries and is safe for concurrent use. A zero or negative ttl disables it.
+func WithCityStateCache(ttl time.Duration) Option {
+	return func(c *Client) {
+		if ttl <= 0 {
+			c.cityStateCache = nil
+			return
+		}
+		c.cityStateCache = newCityStateCache(ttl, DefaultCityStateCacheSize)
+	}
+}
+
+// cityStateCache is a TTL cache with least-recently-used eviction
+type cityStateCache struct {
+	ttl        time.Duration
+	maxEntries int
+	entries    map[string]*list.Element
+	order      *list.List // front is most recently used
+	now        func() time.Time
+	mu         sync.Mutex
+}
+
+// cityStateEntry is a cached response and its expiration time
+type cityStateEntry struct {
+	key     string
+	resp    models.CityStateResponse
+	expires time.Time
+}
+
+// newCityStateCache creates an empty cache
+func newCityStateCache(ttl time.Duration, maxEntries int) *cityStateCache {
+	return &cityStateCache{
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		entries:    make(map[string]*list.Element),
+		order:      list.New(),
+		now:        time.Now,
+	}
+}
+
+// cityStateCacheKey builds the cache key for a request sent to baseURL from
+// its normalized ZIP code, so that responses from one environment are never
+// served for another. It returns "" when the request has no ZIP code.
+func cityStateCacheKey(baseURL string, req *models.CityStateRequest) string {
+	if req == nil {
+		return ""
+	}
+	zip := strings.TrimSpace(req.ZIPCode)
+	if zip == "" {
+		return ""
+	}
+	return baseURL + " " + zip
+}
+
+// get returns a copy of the cached response for key, if present and unexpired
+func (cc *cityStateCache) get(key string) (*models.CityStateResponse, bool) {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+
+	elem, ok := cc.entries[key]
+	if !ok {
+		return nil, false
+	}
+	entry := elem.Value.(*cityStateEntry)
+	if !cc.now().Before(entry.expires) {
+		cc.order.Remove(elem)
+		delete(cc.entries, key)
+		return nil, false
+	}
+	cc.order.MoveToFront(elem)
+	resp := entry.resp
+	return &resp, true
+}
+
+// put stores a copy of resp for key, evicting the least recently used entry
+// when the cache is full
+func (cc *cityStateCache) put(key string, resp *models.CityStateResponse) {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+
+	expires := cc.now().Add(cc.ttl)
+	if elem, ok := cc.entries[key]; ok {
+		entry := elem.Value.(*cityStateEntry)
+		entry.resp = *resp
+		entry.expires = expires
+		cc.order.MoveToFront(elem)
+		return
+	}
+
+	cc.entries[key] = cc.order.PushFront(&cityStateEntry{key: key, resp: *resp, expires: expires})
+	for cc.order.Len() > cc.maxEntries {
+		oldest := cc.order.Back()
+		cc.order.Remove(oldest)
+		delete(cc.entries, oldest.Value.(*cityStateEntry).key)
+	}
+}
@@ -0,0 +1,217 @@
+package usps
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/my-eq/go-usps/models"
+)
+
+// newCityStateServer returns a server that answers every City/State lookup
+// for the requested ZIP and counts the requests it receives.
+func newCityStateServer(calls *int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(calls, 1)
+		zip := r.URL.Query().Get("ZIPCode")
+		if zip == "00000" {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(models.ErrorMessage{
+				Error: &models.ErrorInfo{Code: "400", Message: "Invalid ZIP Code"},
+			})
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(models.CityStateResponse{City: "CITY " + zip, State: "NY", ZIPCode: zip})
+	}))
+}
+
+func TestWithCityStateCache(t *testing.T) {
+	var calls int32
+	server := newCityStateServer(&calls)
+	defer server.Close()
+
+	client := NewClient(NewStaticTokenProvider("test-token"), WithBaseURL(server.URL), WithCityStateCache(time.Hour))
+	now := time.Now()
+	client.cityStateCache.now = func() time.Time { return now }
+
+	ctx := context.Background()
+	req := &models.CityStateRequest{ZIPCode: "10001"}
+
+	first, err := client.GetCityState(ctx, req)
+	if err != nil {
+		t.Fatalf("First GetCityState failed: %v", err)
+	}
+	second, err := client.GetCityState(ctx, req)
+	if err != nil {
+		t.Fatalf("Second GetCityState failed: %v", err)
+	}
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		t.Errorf("Expected 1 request within the TTL, got %d", got)
+	}
+	if second.City != first.City || second.State != first.State || second.ZIPCode != first.ZIPCode {
+		t.Errorf("Expected cached response %+v, got %+v", first, second)
+	}
+
+	// Callers modifying a response must not affect the cache
+	second.City = "CHANGED"
+	third, _ := client.GetCityState(ctx, req)
+	if third.City != "CITY 10001" {
+		t.Errorf("Expected cached city 'CITY 10001', got '%s'", third.City)
+	}
+
+	now = now.Add(time.Hour)
+	if _, err := client.GetCityState(ctx, req); err != nil {
+		t.Fatalf("GetCityState after expiration failed: %v", err)
+	}
+	if got := atomic.LoadInt32(&calls); got != 2 {
+		t.Errorf("Expected 2 requests after the TTL expired, got %d", got)
+	}
+}
+
+func TestWithCityStateCache_ErrorsNotCached(t *testing.T) {
+	var calls int32
+	server := newCityStateServer(&calls)
+	defer server.Close()
+
+	client := NewClient(NewStaticTokenProvider("test-token"), WithBaseURL(server.URL), WithCityStateCache(time.Hour))
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.GetCityState(context.Background(), &models.CityStateRequest{ZIPCode: "00000"}); err == nil {
+			t.Fatal("Expected error for invalid ZIP")
+		}
+	}
+	if got := atomic.LoadInt32(&calls); got != 2 {
+		t.Errorf("Expected 2 requests, got %d", got)
+	}
+}
+
+func TestWithCityStateCache_QueryParameters(t *testing.T) {
+	var flags []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		flag := r.URL.Query().Get("flag")
+		if flag == "" {
+			flag = "none"
+		}
+		flags = append(flags, flag)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(models.CityStateResponse{City: "CITY " + flag, State: "NY", ZIPCode: "10001"})
+	}))
+	defer server.Close()
+
+	client := NewClient(NewStaticTokenProvider("test-token"), WithBaseURL(server.URL), WithCityStateCache(time.Hour))
+	ctx := context.Background()
+	req := &models.CityStateRequest{ZIPCode: "10001"}
+
+	// Prime the cache without extras
+	if _, err := client.GetCityState(ctx, req); err != nil {
+		t.Fatalf("GetCityState failed: %v", err)
+	}
+
+	calls := []struct {
+		flag     string
+		wantCity string
+	}{
+		{"a", "CITY a"},
+		{"b", "CITY b"},
+	}
+	for _, call := range calls {
+		resp, err := client.GetCityState(ctx, req, WithQueryParameter("flag", call.flag))
+		if err != nil {
+			t.Fatalf("GetCityState with flag %s failed: %v", call.flag, err)
+		}
+		if resp.City != call.wantCity {
+			t.Errorf("Flag %s: expected city '%s', got '%s'", call.flag, call.wantCity, resp.City)
+		}
+	}
+
+	// A call without extras is still served from the cache entry it created
+	resp, err := client.GetCityState(ctx, req)
+	if err != nil {
+		t.Fatalf("GetCityState failed: %v", err)
+	}
+	if resp.City != "CITY none" {
+		t.Errorf("Expected cached city 'CITY none', got '%s'", resp.City)
+	}
+
+	if want := []string{"none", "a", "b"}; fmt.Sprint(flags) != fmt.Sprint(want) {
+		t.Errorf("Expected requests with flags %q, got %q", want, flags)
+	}
+}
+
+func TestWithCityStateCache_Disabled(t *testing.T) {
+	var calls int32
+	server := newCityStateServer(&calls)
+	defer server.Close()
+
+	client := NewClient(NewStaticTokenProvider("test-token"), WithBaseURL(server.URL), WithCityStateCache(0))
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.GetCityState(context.Background(), &models.CityStateRequest{ZIPCode: "10001"}); err != nil {
+			t.Fatalf("GetCityState failed: %v", err)
+		}
+	}
+	if got := atomic.LoadInt32(&calls); got != 2 {
+		t.Errorf("Expected 2 requests without a cache, got %d", got)
+	}
+	if client.Config().CityStateCacheTTL != 0 {
+		t.Errorf("Expected CityStateCacheTTL 0, got %v", client.Config().CityStateCacheTTL)
+	}
+}
+
+func TestCityStateCache_LRUEviction(t *testing.T) {
+	cache := newCityStateCache(time.Hour, 2)
+
+	cache.put("10001", &models.CityStateResponse{City: "NEW YORK"})
+	cache.put("20500", &models.CityStateResponse{City: "WASHINGTON"})
+	// Touch 10001 so 20500 becomes the least recently used entry
+	if _, ok := cache.get("10001"); !ok {
+		t.Fatal("Expected 10001 to be cached")
+	}
+	cache.put("62704", &models.CityStateResponse{City: "SPRINGFIELD"})
+
+	if _, ok := cache.get("20500"); ok {
+		t.Error("Expected 20500 to be evicted")
+	}
+	for _, zip := range []string{"10001", "62704"} {
+		if _, ok := cache.get(zip); !ok {
+			t.Errorf("Expected %s to be cached", zip)
+		}
+	}
+	if cache.order.Len() != 2 || len(cache.entries) != 2 {
+		t.Errorf("Expected 2 entries, got %d in list and %d in map", cache.order.Len(), len(cache.entries))
+	}
+}
+
+func TestCityStateCache_Concurrent(t *testing.T) {
+	var calls int32
+	server := newCityStateServer(&calls)
+	defer server.Close()
+
+	client := NewClient(NewStaticTokenProvider("test-token"), WithBaseURL(server.URL), WithCityStateCache(time.Hour))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			zip := fmt.Sprintf("1000%d", i%5)
+			resp, err := client.GetCityState(context.Background(), &models.CityStateRequest{ZIPCode: zip})
+			if err != nil {
+				t.Errorf("GetCityState failed: %v", err)
+				return
+			}
+			if resp.ZIPCode != zip {
+				t.Errorf("Expected ZIP %s, got %s", zip, resp.ZIPCode)
+			}
+		}(i)
+	}
+	wg.Wait()
+}
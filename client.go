@@ -2,12 +2,16 @@ package usps
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
 	"reflect"
+	"runtime/debug"
+	"strconv"
 	"strings"
 	"time"
 
@@ -23,6 +27,35 @@ const (
 	DefaultTimeout = 30 * time.Second
 )
 
+// modulePath is the import path of this module, used to find its version.
+const modulePath = "github.com/my-eq/go-usps"
+
+// DefaultUserAgent is the User-Agent header sent when WithUserAgent is not
+// used. It has the form "go-usps/<version>", where the version is the module
+// version recorded in the binary's build info, or "devel" when unavailable.
+var DefaultUserAgent = "go-usps/" + moduleVersion()
+
+// moduleVersion returns the version of this module from the build info.
+func moduleVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "devel"
+	}
+	modules := append([]*debug.Module{&info.Main}, info.Deps...)
+	for _, m := range modules {
+		if m.Path != modulePath {
+			continue
+		}
+		if m.Replace != nil {
+			m = m.Replace
+		}
+		if m.Version != "" && m.Version != "(devel)" {
+			return m.Version
+		}
+	}
+	return "devel"
+}
+
 // TokenProvider is an interface for providing OAuth tokens
 type TokenProvider interface {
 	// GetToken returns the current OAuth token
@@ -49,14 +82,89 @@ func (p *StaticTokenProvider) GetToken(ctx context.Context) (string, error) {
 
 // Client is the USPS API client
 type Client struct {
-	baseURL       string
-	httpClient    *http.Client
-	tokenProvider TokenProvider
+	baseURL            string
+	httpClient         *http.Client
+	tokenProvider      TokenProvider
+	forwardHeaders     []string
+	resultObserver     func(ctx context.Context, endpoint string, result interface{}, err error)
+	correlationIDs     bool
+	logger             Logger
+	requestURLCallback func(endpoint, url string)
+	userAgent          string
+	cityStateCache     *cityStateCache
+	retryClassifier    func(err error) RetryDecision
 }
 
 // Option is a functional option for configuring the Client
 type Option func(*Client)
 
+// RequestOption is a functional option for configuring a single API call,
+// passed as the trailing arguments of GetAddress, GetCityState, or GetZIPCode.
+type RequestOption func(*requestOptions)
+
+// requestOptions holds the per-call settings applied by RequestOption
+type requestOptions struct {
+	timeout time.Duration
+	query   url.Values
+}
+
+// WithRequestDeadline limits a single call to d, including reading the
+// response, without changing the client-wide timeout set by WithTimeout. The
+// call runs under a child of the caller's context, so a stricter deadline
+// already on ctx still applies. A zero or negative d leaves the call
+// unchanged.
+//
+// Example:
+//
+//	resp, err := client.GetAddress(ctx, req, usps.WithRequestDeadline(2*time.Second))
+func WithRequestDeadline(d time.Duration) RequestOption {
+	return func(o *requestOptions) {
+		o.timeout = d
+	}
+}
+
+// WithQueryParameter adds a query parameter to a single call, for API
+// parameters the request types do not model, such as flags supported by a
+// gateway in front of USPS or newly released USPS options. It cannot replace a
+// parameter set from the request's own fields, and an empty name is ignored.
+// The option may be repeated to add several parameters.
+//
+// Example:
+//
+//	resp, err := client.GetAddress(ctx, req, usps.WithQueryParameter("returnText", "true"))
+func WithQueryParameter(name, value string) RequestOption {
+	return func(o *requestOptions) {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			return
+		}
+		if o.query == nil {
+			o.query = url.Values{}
+		}
+		o.query.Add(name, value)
+	}
+}
+
+// extraQueryKey is the context key for query parameters added with
+// WithQueryParameter
+type extraQueryKey struct{}
+
+// applyRequestOptions derives the context for a single call from opts. The
+// returned cancel function must be called once the response has been read.
+func applyRequestOptions(ctx context.Context, opts []RequestOption) (context.Context, context.CancelFunc) {
+	var o requestOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if len(o.query) > 0 {
+		ctx = context.WithValue(ctx, extraQueryKey{}, o.query)
+	}
+	if o.timeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, o.timeout)
+}
+
 // WithBaseURL sets a custom base URL for the client
 func WithBaseURL(baseURL string) Option {
 	return func(c *Client) {
@@ -71,6 +179,18 @@ func WithHTTPClient(httpClient *http.Client) Option {
 	}
 }
 
+// WithTransport sets the RoundTripper used by the HTTP client, for example a
+// uspstest.RecordingTransport or uspstest.ReplayTransport in tests. Apply it
+// after WithHTTPClient: it sets the Transport on a copy of the current client,
+// so the *http.Client passed to WithHTTPClient is not modified.
+func WithTransport(transport http.RoundTripper) Option {
+	return func(c *Client) {
+		httpClient := *c.httpClient
+		httpClient.Transport = transport
+		c.httpClient = &httpClient
+	}
+}
+
 // WithTimeout sets a custom timeout for the HTTP client
 func WithTimeout(timeout time.Duration) Option {
 	return func(c *Client) {
@@ -78,12 +198,374 @@ func WithTimeout(timeout time.Duration) Option {
 	}
 }
 
+// Logger receives a record of each HTTP request a Client sends to the USPS API.
+// Headers are never passed to a Logger, so the Authorization header and its
+// bearer token cannot end up in logs.
+type Logger interface {
+	// LogRequest is called before the request is sent with the HTTP method
+	// and the full request URL, including query parameters.
+	LogRequest(method, url string)
+	// LogResponse is called when the round trip finishes with the response
+	// status code, or 0 if no response was received, and the time taken.
+	LogResponse(statusCode int, duration time.Duration)
+}
+
+// ContextLogger is a Logger that also receives the request context. When the
+// configured logger implements it, LogRequestContext and LogResponseContext
+// are called in place of LogRequest and LogResponse, so the logger can read
+// the call's correlation ID with CorrelationIDFromContext.
+type ContextLogger interface {
+	Logger
+	LogRequestContext(ctx context.Context, method, url string)
+	LogResponseContext(ctx context.Context, statusCode int, duration time.Duration)
+}
+
+// nopLogger is the Logger used when none is configured
+type nopLogger struct{}
+
+func (nopLogger) LogRequest(method, url string)                      {}
+func (nopLogger) LogResponse(statusCode int, duration time.Duration) {}
+
+// endpointLogger is implemented by Loggers that record a round trip as a single
+// entry together with its endpoint label. requestLog calls logRoundTrip in
+// place of LogRequest and LogResponse for such loggers.
+type endpointLogger interface {
+	logRoundTrip(ctx context.Context, endpoint, method, url string, statusCode int, duration time.Duration)
+}
+
+// requestLog reports one HTTP round trip to a Logger.
+type requestLog struct {
+	ctx      context.Context
+	logger   Logger
+	endpoint string
+	method   string
+	url      string
+	start    time.Time
+}
+
+// beginRequestLog starts timing a request and logs it. A nil logger is
+// replaced with a no-op.
+func beginRequestLog(ctx context.Context, logger Logger, endpoint, method, url string) requestLog {
+	if logger == nil {
+		logger = nopLogger{}
+	}
+	switch l := logger.(type) {
+	case endpointLogger:
+	case ContextLogger:
+		l.LogRequestContext(ctx, method, url)
+	default:
+		l.LogRequest(method, url)
+	}
+	return requestLog{ctx: ctx, logger: logger, endpoint: endpoint, method: method, url: url, start: time.Now()}
+}
+
+// end logs the response status code, or 0 if no response was received.
+func (l requestLog) end(statusCode int) {
+	duration := time.Since(l.start)
+	switch logger := l.logger.(type) {
+	case endpointLogger:
+		logger.logRoundTrip(l.ctx, l.endpoint, l.method, l.url, statusCode, duration)
+	case ContextLogger:
+		logger.LogResponseContext(l.ctx, statusCode, duration)
+	default:
+		logger.LogResponse(statusCode, duration)
+	}
+}
+
+// WithLogger sets a Logger that is called for every API request. The logger
+// runs synchronously on the request path, so it should return quickly. When
+// passed to NewOAuthClient, token and revoke requests are logged too. A nil
+// logger disables logging. A logger that implements ContextLogger also
+// receives the request context.
+func WithLogger(logger Logger) Option {
+	return func(c *Client) {
+		c.logger = logger
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent on every request, so USPS can
+// identify the integration, e.g. "acme-checkout/2.3 (ops@acme.example)". When
+// passed to NewOAuthClient it also applies to token and revoke requests. An
+// empty value keeps DefaultUserAgent.
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) {
+		if userAgent = strings.TrimSpace(userAgent); userAgent != "" {
+			c.userAgent = userAgent
+		}
+	}
+}
+
+// WithForwardHeadersFromContext forwards the named headers from the request
+// context onto each outbound request. Header values are attached to the context
+// with ContextWithForwardedHeaders, typically by inbound HTTP middleware.
+// Headers that are not present in the context are skipped.
+//
+// Example:
+//
+//	client := usps.NewClient(provider, usps.WithForwardHeadersFromContext("traceparent", "X-Request-ID"))
+//	ctx = usps.ContextWithForwardedHeaders(ctx, inbound.Header)
+//	resp, err := client.GetAddress(ctx, req)
+func WithForwardHeadersFromContext(keys ...string) Option {
+	return func(c *Client) {
+		for _, key := range keys {
+			c.forwardHeaders = append(c.forwardHeaders, http.CanonicalHeaderKey(key))
+		}
+	}
+}
+
+// Endpoint labels passed to the WithResultObserver callback. They name the
+// operation rather than the request path, so they stay the same when the
+// base URL points at a gateway that prefixes or rewrites paths.
+const (
+	EndpointAddress         = "address"
+	EndpointCityState       = "city-state"
+	EndpointZIPCode         = "zipcode"
+	EndpointOAuthToken      = "oauth-token"
+	EndpointOAuthRevoke     = "oauth-revoke"
+	EndpointOAuthDeviceCode = "oauth-device-code"
+)
+
+// WithResultObserver registers a function called at the end of every Get*
+// method with the endpoint label (EndpointAddress, EndpointCityState, or
+// EndpointZIPCode), the typed response (*models.AddressResponse,
+// *models.CityStateResponse, or *models.ZIPCodeResponse), and the error. On
+// failure result is nil. The observer runs synchronously, so it should return
+// quickly.
+//
+// When passed to NewOAuthClient, the observer is also called by PostToken with
+// EndpointOAuthToken and the token response, by PostRevoke with
+// EndpointOAuthRevoke and a nil result, and by RequestDeviceCode with
+// EndpointOAuthDeviceCode and the device code response.
+func WithResultObserver(observer func(endpoint string, result interface{}, err error)) Option {
+	if observer == nil {
+		return WithResultObserverContext(nil)
+	}
+	return WithResultObserverContext(func(_ context.Context, endpoint string, result interface{}, err error) {
+		observer(endpoint, result, err)
+	})
+}
+
+// WithResultObserverContext is like WithResultObserver, but the observer also
+// receives the call's context, from which CorrelationIDFromContext returns the
+// correlation ID assigned by WithObservabilityContext. It replaces any
+// observer set with WithResultObserver.
+func WithResultObserverContext(observer func(ctx context.Context, endpoint string, result interface{}, err error)) Option {
+	return func(c *Client) {
+		c.resultObserver = observer
+	}
+}
+
+// WithRequestURLCallback registers a function called by each Get* method with
+// the endpoint label (EndpointAddress, EndpointCityState, or EndpointZIPCode)
+// and the full request URL, including the encoded query parameters, before
+// the request is sent. The URL carries no credentials, so it is passed as is;
+// it is suitable as a cache key or for recording exactly what was queried.
+func WithRequestURLCallback(callback func(endpoint, url string)) Option {
+	return func(c *Client) {
+		c.requestURLCallback = callback
+	}
+}
+
+// observeResult reports a finished call to the configured result observer
+func (c *Client) observeResult(ctx context.Context, endpoint string, result interface{}, err error) {
+	notifyObserver(ctx, c.resultObserver, endpoint, result, err)
+}
+
+// notifyObserver calls observer, if set, with the outcome of a finished call
+func notifyObserver(ctx context.Context, observer func(ctx context.Context, endpoint string, result interface{}, err error), endpoint string, result interface{}, err error) {
+	if observer == nil {
+		return
+	}
+	if err != nil {
+		// Avoid handing the observer a non-nil interface holding a nil pointer
+		result = nil
+	}
+	observer(ctx, endpoint, result, err)
+}
+
+// forwardedHeadersKey is the context key for headers to forward downstream
+type forwardedHeadersKey struct{}
+
+// ContextWithForwardedHeaders returns a copy of ctx carrying the given headers.
+// Only headers named via WithForwardHeadersFromContext are sent to USPS.
+func ContextWithForwardedHeaders(ctx context.Context, headers http.Header) context.Context {
+	return context.WithValue(ctx, forwardedHeadersKey{}, headers.Clone())
+}
+
+// forwardedHeadersFromContext returns the headers stored by ContextWithForwardedHeaders
+func forwardedHeadersFromContext(ctx context.Context) http.Header {
+	headers, _ := ctx.Value(forwardedHeadersKey{}).(http.Header)
+	return headers
+}
+
+// CorrelationIDHeader is the request header that carries the correlation ID
+// when WithObservabilityContext is enabled.
+const CorrelationIDHeader = "X-Correlation-ID"
+
+// WithObservabilityContext gives every API call a single correlation ID so
+// that logs, metrics, and traces for the call can be joined. The ID is taken
+// from the context (see ContextWithCorrelationID) or generated when absent,
+// and is then:
+//   - stored in the request context, where an http.RoundTripper middleware or
+//     tracing transport can read it with CorrelationIDFromContext,
+//   - passed in that context to a ContextLogger set with WithLogger or
+//     WithSlogLogger, and to an observer set with WithResultObserverContext,
+//   - sent to USPS in the CorrelationIDHeader request header, unless that
+//     header is already set by WithForwardHeadersFromContext, and
+//   - recorded in ResponseMeta.CorrelationID for callers that log after the
+//     call returns.
+//
+// The WithResultObserver callback does not receive a context; use
+// WithResultObserverContext to correlate results with the call.
+func WithObservabilityContext() Option {
+	return func(c *Client) {
+		c.correlationIDs = true
+	}
+}
+
+// correlationIDKey is the context key for the call's correlation ID
+type correlationIDKey struct{}
+
+// ContextWithCorrelationID returns a copy of ctx carrying the given
+// correlation ID, which WithObservabilityContext uses instead of generating one.
+func ContextWithCorrelationID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, correlationIDKey{}, id)
+}
+
+// CorrelationIDFromContext returns the correlation ID stored in ctx, or an
+// empty string if there is none.
+func CorrelationIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(correlationIDKey{}).(string)
+	return id
+}
+
+// environmentKey is the context key for the per-call USPS environment
+type environmentKey struct{}
+
+// WithEnvironment returns a copy of ctx that routes calls made with it to the
+// given USPS environment, so that one Client can serve tenants in both
+// environments. Use "production" for ProductionBaseURL or "testing" for
+// TestingBaseURL; any other value, including "", leaves the call on the
+// client's configured base URL, which is also the default for contexts
+// without an environment. The environment only switches between those two
+// public hosts: a client whose base URL was set to a gateway with WithBaseURL
+// keeps it for every call.
+//
+// The environment only selects the base URL. The client's TokenProvider must
+// return a token issued by the same environment; a custom provider can read
+// it with EnvironmentFromContext.
+//
+// Example:
+//
+//	ctx := usps.WithEnvironment(ctx, tenant.USPSEnvironment)
+//	resp, err := client.GetAddress(ctx, req)
+func WithEnvironment(ctx context.Context, env string) context.Context {
+	return context.WithValue(ctx, environmentKey{}, env)
+}
+
+// EnvironmentFromContext returns the environment stored by WithEnvironment,
+// or an empty string if there is none.
+func EnvironmentFromContext(ctx context.Context) string {
+	env, _ := ctx.Value(environmentKey{}).(string)
+	return env
+}
+
+// baseURLFor returns the base URL for a call made with ctx. The environment
+// only switches between the public USPS hosts; a custom base URL is kept.
+func (c *Client) baseURLFor(ctx context.Context) string {
+	if c.baseURL != ProductionBaseURL && c.baseURL != TestingBaseURL {
+		return c.baseURL
+	}
+	switch EnvironmentFromContext(ctx) {
+	case "production":
+		return ProductionBaseURL
+	case "testing":
+		return TestingBaseURL
+	}
+	return c.baseURL
+}
+
+// withCorrelationID ensures ctx carries a correlation ID when enabled
+func (c *Client) withCorrelationID(ctx context.Context) context.Context {
+	if !c.correlationIDs || CorrelationIDFromContext(ctx) != "" {
+		return ctx
+	}
+	return ContextWithCorrelationID(ctx, newCorrelationID())
+}
+
+// newCorrelationID returns a random 128-bit identifier in hex
+func newCorrelationID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return strconv.FormatInt(time.Now().UnixNano(), 16)
+	}
+	return hex.EncodeToString(b[:])
+}
+
+// ResponseMeta holds metadata from a USPS API response, such as the headers
+// USPS support uses to identify a request. Attach one to a context with
+// ContextWithResponseMeta to capture it.
+type ResponseMeta struct {
+	StatusCode int
+	Header     http.Header
+	// CorrelationID is the ID sent with the request when
+	// WithObservabilityContext is enabled. It is set even if the request fails.
+	CorrelationID string
+}
+
+// RequestID returns the request identifier assigned by USPS or an intermediate
+// gateway, or an empty string if the response did not include one.
+func (m *ResponseMeta) RequestID() string {
+	if m == nil || m.Header == nil {
+		return ""
+	}
+	for _, key := range []string{"X-Request-ID", "X-Correlation-ID", "X-Amzn-Trace-Id"} {
+		if value := m.Header.Get(key); value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// responseMetaKey is the context key for capturing response metadata
+type responseMetaKey struct{}
+
+// ContextWithResponseMeta returns a copy of ctx that records the status code and
+// headers of the USPS response into meta once the call completes.
+//
+// Example:
+//
+//	var meta usps.ResponseMeta
+//	resp, err := client.GetAddress(usps.ContextWithResponseMeta(ctx, &meta), req)
+//	log.Printf("USPS request id: %s", meta.RequestID())
+func ContextWithResponseMeta(ctx context.Context, meta *ResponseMeta) context.Context {
+	return context.WithValue(ctx, responseMetaKey{}, meta)
+}
+
+// recordCorrelationID stores the correlation ID into the ResponseMeta attached to ctx, if any
+func recordCorrelationID(ctx context.Context, id string) {
+	if meta, _ := ctx.Value(responseMetaKey{}).(*ResponseMeta); meta != nil {
+		meta.CorrelationID = id
+	}
+}
+
+// recordResponseMeta stores response metadata into the ResponseMeta attached to ctx, if any
+func recordResponseMeta(ctx context.Context, resp *http.Response) {
+	meta, _ := ctx.Value(responseMetaKey{}).(*ResponseMeta)
+	if meta == nil {
+		return
+	}
+	meta.StatusCode = resp.StatusCode
+	meta.Header = resp.Header.Clone()
+}
+
 // NewClient creates a new USPS API client
 func NewClient(tokenProvider TokenProvider, opts ...Option) *Client {
 	c := &Client{
 		baseURL:       ProductionBaseURL,
 		httpClient:    &http.Client{Timeout: DefaultTimeout},
 		tokenProvider: tokenProvider,
+		userAgent:     DefaultUserAgent,
 	}
 
 	for _, opt := range opts {
@@ -93,6 +575,60 @@ func NewClient(tokenProvider TokenProvider, opts ...Option) *Client {
 	return c
 }
 
+// ClientConfig is a read-only snapshot of a Client's effective, non-secret
+// configuration, intended for logging and debugging. It never includes tokens
+// or credentials.
+type ClientConfig struct {
+	BaseURL string
+	// Timeout is the HTTP client timeout; zero means no timeout.
+	Timeout time.Duration
+	// CustomTransport is true when the HTTP client uses a non-default RoundTripper.
+	CustomTransport bool
+	// TokenProvider is the Go type of the token provider (e.g. "*usps.OAuthTokenProvider").
+	TokenProvider string
+	// ForwardHeaders lists the headers set by WithForwardHeadersFromContext.
+	ForwardHeaders []string
+	// ResultObserver is true when WithResultObserver was applied.
+	ResultObserver bool
+	// CorrelationIDs is true when WithObservabilityContext was applied.
+	CorrelationIDs bool
+	// Logger is true when WithLogger was applied with a non-nil logger.
+	Logger bool
+	// RequestURLCallback is true when WithRequestURLCallback was applied.
+	RequestURLCallback bool
+	// UserAgent is the User-Agent header sent on every request.
+	UserAgent string
+	// CityStateCacheTTL is the WithCityStateCache TTL; zero means no cache.
+	CityStateCacheTTL time.Duration
+	// RetryClassifier is true when WithRetryClassifier was applied with a
+	// non-nil classifier.
+	RetryClassifier bool
+}
+
+// Config returns a snapshot of the client's effective configuration. The
+// snapshot is a copy; modifying it does not affect the client.
+func (c *Client) Config() ClientConfig {
+	cfg := ClientConfig{
+		BaseURL:            c.baseURL,
+		TokenProvider:      fmt.Sprintf("%T", c.tokenProvider),
+		ForwardHeaders:     append([]string(nil), c.forwardHeaders...),
+		ResultObserver:     c.resultObserver != nil,
+		CorrelationIDs:     c.correlationIDs,
+		Logger:             c.logger != nil,
+		RequestURLCallback: c.requestURLCallback != nil,
+		UserAgent:          c.userAgent,
+		RetryClassifier:    c.retryClassifier != nil,
+	}
+	if c.cityStateCache != nil {
+		cfg.CityStateCacheTTL = c.cityStateCache.ttl
+	}
+	if c.httpClient != nil {
+		cfg.Timeout = c.httpClient.Timeout
+		cfg.CustomTransport = c.httpClient.Transport != nil && c.httpClient.Transport != http.DefaultTransport
+	}
+	return cfg
+}
+
 // NewTestClient creates a new USPS API client configured for the testing environment
 func NewTestClient(tokenProvider TokenProvider, opts ...Option) *Client {
 	opts = append([]Option{WithBaseURL(TestingBaseURL)}, opts...)
@@ -134,19 +670,35 @@ func NewTestClientWithOAuth(clientID, clientSecret string, opts ...OAuthTokenOpt
 	return NewTestClient(provider)
 }
 
-// doRequest executes an HTTP request and handles the response
-func (c *Client) doRequest(ctx context.Context, method, path string, queryParams interface{}) (*http.Response, error) {
+// doRequest executes an HTTP request and handles the response. endpoint is the
+// label reported to the WithRequestURLCallback callback; an empty endpoint
+// skips the callback.
+func (c *Client) doRequest(ctx context.Context, endpoint, method, path string, queryParams interface{}) (*http.Response, error) {
+	ctx = c.withCorrelationID(ctx)
+
 	// Build URL with query parameters
-	fullURL := c.baseURL + path
+	fullURL := c.baseURLFor(ctx) + path
+	values := url.Values{}
 	if queryParams != nil {
-		values, err := structToURLValues(queryParams)
+		var err error
+		values, err = structToURLValues(queryParams)
 		if err != nil {
 			return nil, fmt.Errorf("failed to encode query parameters: %w", err)
 		}
-		if len(values) > 0 {
-			fullURL += "?" + values.Encode()
+	}
+	if extra, ok := ctx.Value(extraQueryKey{}).(url.Values); ok {
+		for name, vals := range extra {
+			if _, set := values[name]; !set {
+				values[name] = append([]string(nil), vals...)
+			}
 		}
 	}
+	if len(values) > 0 {
+		fullURL += "?" + values.Encode()
+	}
+	if c.requestURLCallback != nil && endpoint != "" {
+		c.requestURLCallback(endpoint, fullURL)
+	}
 
 	// Create request
 	req, err := http.NewRequestWithContext(ctx, method, fullURL, nil)
@@ -161,12 +713,36 @@ func (c *Client) doRequest(ctx context.Context, method, path string, queryParams
 	}
 	req.Header.Set("Authorization", "Bearer "+token)
 	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", c.userAgent)
+
+	// Forward configured headers from the context
+	if len(c.forwardHeaders) > 0 {
+		if forwarded := forwardedHeadersFromContext(ctx); forwarded != nil {
+			for _, key := range c.forwardHeaders {
+				if value := forwarded.Get(key); value != "" {
+					req.Header.Set(key, value)
+				}
+			}
+		}
+	}
+
+	// Tag the request with the call's correlation ID
+	if id := CorrelationIDFromContext(ctx); c.correlationIDs && id != "" {
+		if req.Header.Get(CorrelationIDHeader) == "" {
+			req.Header.Set(CorrelationIDHeader, id)
+		}
+		recordCorrelationID(ctx, id)
+	}
 
 	// Execute request
+	log := beginRequestLog(ctx, c.logger, endpoint, method, fullURL)
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to execute request: %w", err)
+		log.end(0)
+		return nil, wrapTransportError(ctx, c.httpClient.Timeout, err)
 	}
+	log.end(resp.StatusCode)
+	recordResponseMeta(ctx, resp)
 
 	return resp, nil
 }
@@ -185,13 +761,30 @@ func (c *Client) handleResponse(resp *http.Response, target interface{}) error {
 	if resp.StatusCode >= 400 {
 		var errMsg models.ErrorMessage
 		if err := json.Unmarshal(body, &errMsg); err != nil {
-			// If we can't parse the error, return a generic error with status code
-			return fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(body))
+			// A gateway may answer with an empty or plain-text body; keep the
+			// status code, and the body as the message, so that the error
+			// still matches its sentinel and carries Retry-After
+			errMsg = models.ErrorMessage{}
+			if text := strings.TrimSpace(string(body)); text != "" {
+				errMsg.Error = &models.ErrorInfo{Message: text}
+			}
 		}
-		return &APIError{
+		apiErr := &APIError{
 			StatusCode:   resp.StatusCode,
 			ErrorMessage: errMsg,
+			RetryAfter:   parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()),
 		}
+
+		// Keep any corrections or matches USPS returned alongside the error
+		var suggestions struct {
+			Corrections []models.AddressCorrection `json:"corrections"`
+			Matches     []models.AddressMatch      `json:"matches"`
+		}
+		if err := json.Unmarshal(body, &suggestions); err == nil {
+			apiErr.Corrections = suggestions.Corrections
+			apiErr.Matches = suggestions.Matches
+		}
+		return apiErr
 	}
 
 	// Unmarshal success response
@@ -202,6 +795,28 @@ func (c *Client) handleResponse(resp *http.Response, target interface{}) error {
 	return nil
 }
 
+// parseRetryAfter converts a Retry-After header value, given either as
+// delta-seconds ("120") or as an HTTP-date, into a duration relative to now.
+// It returns zero for an empty or invalid value or a date in the past.
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		if wait := date.Sub(now); wait > 0 {
+			return wait
+		}
+	}
+	return 0
+}
+
 // structToURLValues converts a struct to url.Values using struct tags
 func structToURLValues(s interface{}) (url.Values, error) {
 	values := url.Values{}
@@ -257,8 +872,28 @@ func structToURLValues(s interface{}) (url.Values, error) {
 
 // APIError represents an error returned by the USPS API
 type APIError struct {
-	StatusCode   int
+	StatusCode int
+	// ErrorMessage is the decoded error body. When the body is not JSON, such
+	// as a plain-text response from a gateway, ErrorMessage.Error.Message
+	// holds the body text, and ErrorMessage.Error is nil if the body is empty.
 	ErrorMessage models.ErrorMessage
+
+	// Corrections and Matches hold any address corrections or match codes
+	// included in the error body, such as a hint that the street was not
+	// found. They can be used to offer suggestions when standardization fails.
+	Corrections []models.AddressCorrection
+	Matches     []models.AddressMatch
+
+	// RetryAfter is how long the server asked the caller to wait before
+	// retrying, taken from the Retry-After header of a 429 or 503 response.
+	// It is zero when the header is absent or unparseable.
+	RetryAfter time.Duration
+}
+
+// RetryAfterDuration returns the wait requested by the server's Retry-After
+// header and whether one was provided.
+func (e *APIError) RetryAfterDuration() (time.Duration, bool) {
+	return e.RetryAfter, e.RetryAfter > 0
 }
 
 // Error implements the error interface
@@ -269,9 +904,31 @@ func (e *APIError) Error() string {
 	return fmt.Sprintf("USPS API error (status %d)", e.StatusCode)
 }
 
-// GetAddress standardizes a street address
-func (c *Client) GetAddress(ctx context.Context, req *models.AddressRequest) (*models.AddressResponse, error) {
-	resp, err := c.doRequest(ctx, http.MethodGet, "/address", req)
+// Is reports whether the error's status code matches ErrBadRequest,
+// ErrUnauthorized, ErrNotFound, or ErrRateLimited
+func (e *APIError) Is(target error) bool {
+	sentinel, ok := apiErrorSentinels[e.StatusCode]
+	return ok && target == sentinel
+}
+
+// GetAddress standardizes a street address.
+// A 2xx response is always returned as a decoded AddressResponse, even when
+// Delivery Point Validation fails: the standardized components are still
+// populated and deliverability is reported by resp.IsDeliverable and the
+// AdditionalInfo helpers rather than as an error. Options such as
+// WithRequestDeadline apply to this call only.
+func (c *Client) GetAddress(ctx context.Context, req *models.AddressRequest, opts ...RequestOption) (out *models.AddressResponse, err error) {
+	ctx = c.withCorrelationID(ctx)
+	defer func() { c.observeResult(ctx, EndpointAddress, out, err) }()
+
+	ctx, cancel := applyRequestOptions(ctx, opts)
+	defer cancel()
+
+	if !req.IsDomestic() {
+		return nil, fmt.Errorf("%w (got %q)", ErrUnsupportedCountry, req.Country)
+	}
+
+	resp, err := c.doRequest(ctx, EndpointAddress, http.MethodGet, "/address", req)
 	if err != nil {
 		return nil, err
 	}
@@ -284,9 +941,29 @@ func (c *Client) GetAddress(ctx context.Context, req *models.AddressRequest) (*m
 	return &result, nil
 }
 
-// GetCityState returns the city and state for a given ZIP code
-func (c *Client) GetCityState(ctx context.Context, req *models.CityStateRequest) (*models.CityStateResponse, error) {
-	resp, err := c.doRequest(ctx, http.MethodGet, "/city-state", req)
+// GetCityState returns the city and state for a given ZIP code. With
+// WithCityStateCache, a cached response is returned without an HTTP request;
+// calls with WithQueryParameter bypass the cache, since the extra parameters
+// may change the response. Options such as WithRequestDeadline apply to this
+// call only.
+func (c *Client) GetCityState(ctx context.Context, req *models.CityStateRequest, opts ...RequestOption) (out *models.CityStateResponse, err error) {
+	ctx = c.withCorrelationID(ctx)
+	defer func() { c.observeResult(ctx, EndpointCityState, out, err) }()
+
+	ctx, cancel := applyRequestOptions(ctx, opts)
+	defer cancel()
+
+	cacheKey := cityStateCacheKey(c.baseURLFor(ctx), req)
+	if _, extra := ctx.Value(extraQueryKey{}).(url.Values); extra {
+		cacheKey = ""
+	}
+	if c.cityStateCache != nil && cacheKey != "" {
+		if cached, ok := c.cityStateCache.get(cacheKey); ok {
+			return cached, nil
+		}
+	}
+
+	resp, err := c.doRequest(ctx, EndpointCityState, http.MethodGet, "/city-state", req)
 	if err != nil {
 		return nil, err
 	}
@@ -296,12 +973,23 @@ func (c *Client) GetCityState(ctx context.Context, req *models.CityStateRequest)
 		return nil, err
 	}
 
+	if c.cityStateCache != nil && cacheKey != "" {
+		c.cityStateCache.put(cacheKey, &result)
+	}
+
 	return &result, nil
 }
 
-// GetZIPCode returns the ZIP code for a given address
-func (c *Client) GetZIPCode(ctx context.Context, req *models.ZIPCodeRequest) (*models.ZIPCodeResponse, error) {
-	resp, err := c.doRequest(ctx, http.MethodGet, "/zipcode", req)
+// GetZIPCode returns the ZIP code for a given address.
+// Options such as WithRequestDeadline apply to this call only.
+func (c *Client) GetZIPCode(ctx context.Context, req *models.ZIPCodeRequest, opts ...RequestOption) (out *models.ZIPCodeResponse, err error) {
+	ctx = c.withCorrelationID(ctx)
+	defer func() { c.observeResult(ctx, EndpointZIPCode, out, err) }()
+
+	ctx, cancel := applyRequestOptions(ctx, opts)
+	defer cancel()
+
+	resp, err := c.doRequest(ctx, EndpointZIPCode, http.MethodGet, "/zipcode", req)
 	if err != nil {
 		return nil, err
 	}
@@ -313,3 +1001,32 @@ func (c *Client) GetZIPCode(ctx context.Context, req *models.ZIPCodeRequest) (*m
 
 	return &result, nil
 }
+
+// healthCheckZIP is the ZIP code looked up by HealthCheck. It belongs to the
+// White House and is always served by the City State endpoint.
+const healthCheckZIP = "20500"
+
+// HealthCheck verifies that the client is configured correctly by obtaining a
+// token from the token provider and looking up the city and state of a known
+// ZIP code. It is intended to run once at startup so that a bad base URL or
+// bad credentials fail a deployment early. Each call makes one City State
+// request, which counts against the API quota, and may also request a token.
+// The lookup is not reported to the WithResultObserver or
+// WithRequestURLCallback callbacks.
+func (c *Client) HealthCheck(ctx context.Context) error {
+	if _, err := c.tokenProvider.GetToken(ctx); err != nil {
+		return fmt.Errorf("health check: failed to get token: %w", err)
+	}
+
+	resp, err := c.doRequest(ctx, "", http.MethodGet, "/city-state", &models.CityStateRequest{ZIPCode: healthCheckZIP})
+	if err != nil {
+		return fmt.Errorf("health check: %s unreachable: %w", c.baseURLFor(ctx), err)
+	}
+
+	var result models.CityStateResponse
+	if err := c.handleResponse(resp, &result); err != nil {
+		return fmt.Errorf("health check: city-state lookup failed: %w", err)
+	}
+
+	return nil
+}
@@ -3,8 +3,13 @@ package usps
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"reflect"
 	"strings"
 	"testing"
 	"time"
@@ -675,9 +680,79 @@ func TestHandleResponse_InvalidErrorJSON(t *testing.T) {
 		t.Fatal("Expected error, got nil")
 	}
 
-	// Should still return an error even if we can't parse the error response
-	if apiErr, ok := err.(*APIError); ok {
-		t.Errorf("Expected generic error, got APIError: %v", apiErr)
+	// An unparseable body still produces an APIError with the body as its message
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("Expected APIError, got %T: %v", err, err)
+	}
+	if apiErr.StatusCode != http.StatusBadRequest {
+		t.Errorf("Expected status 400, got %d", apiErr.StatusCode)
+	}
+	if want := "USPS API error (status 400): invalid error json"; err.Error() != want {
+		t.Errorf("Expected error message '%s', got '%s'", want, err.Error())
+	}
+}
+
+func TestHandleResponse_NonJSONErrorBody(t *testing.T) {
+	tests := []struct {
+		name           string
+		status         int
+		contentType    string
+		body           string
+		retryAfter     string
+		wantSentinel   error
+		wantMessage    string
+		wantRetryAfter time.Duration
+	}{
+		{
+			name:           "empty 429",
+			status:         http.StatusTooManyRequests,
+			retryAfter:     "7",
+			wantSentinel:   ErrRateLimited,
+			wantMessage:    "USPS API error (status 429)",
+			wantRetryAfter: 7 * time.Second,
+		},
+		{
+			name:         "text/plain 404",
+			status:       http.StatusNotFound,
+			contentType:  "text/plain",
+			body:         "Not Found\n",
+			wantSentinel: ErrNotFound,
+			wantMessage:  "USPS API error (status 404): Not Found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tt.contentType != "" {
+					w.Header().Set("Content-Type", tt.contentType)
+				}
+				if tt.retryAfter != "" {
+					w.Header().Set("Retry-After", tt.retryAfter)
+				}
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			client := NewClient(NewStaticTokenProvider("test-token"), WithBaseURL(server.URL))
+			_, err := client.GetCityState(context.Background(), &models.CityStateRequest{ZIPCode: "10001"})
+
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("Expected APIError, got %T: %v", err, err)
+			}
+			if !errors.Is(err, tt.wantSentinel) {
+				t.Errorf("Expected errors.Is(err, %v) to be true", tt.wantSentinel)
+			}
+			if err.Error() != tt.wantMessage {
+				t.Errorf("Expected error message '%s', got '%s'", tt.wantMessage, err.Error())
+			}
+			if apiErr.RetryAfter != tt.wantRetryAfter {
+				t.Errorf("Expected RetryAfter %v, got %v", tt.wantRetryAfter, apiErr.RetryAfter)
+			}
+		})
 	}
 }
 
@@ -694,6 +769,69 @@ func TestWithHTTPClient(t *testing.T) {
 	}
 }
 
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) { return f(r) }
+
+func TestWithTransport(t *testing.T) {
+	calls := 0
+	transport := roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		calls++
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": {"application/json"}},
+			Body:       io.NopCloser(strings.NewReader(`{"city":"NEW YORK","state":"NY","ZIPCode":"10001"}`)),
+			Request:    r,
+		}, nil
+	})
+
+	client := NewClient(NewStaticTokenProvider("test-token"), WithTransport(transport), WithTimeout(5*time.Second))
+
+	resp, err := client.GetCityState(context.Background(), &models.CityStateRequest{ZIPCode: "10001"})
+	if err != nil {
+		t.Fatalf("GetCityState failed: %v", err)
+	}
+	if resp.City != "NEW YORK" {
+		t.Errorf("Expected City 'NEW YORK', got '%s'", resp.City)
+	}
+	if calls != 1 {
+		t.Errorf("Expected 1 transport call, got %d", calls)
+	}
+	if !client.Config().CustomTransport {
+		t.Error("Expected CustomTransport to be true")
+	}
+	if client.httpClient.Timeout != 5*time.Second {
+		t.Errorf("Expected timeout 5s, got %v", client.httpClient.Timeout)
+	}
+}
+
+func TestWithTransport_CopiesHTTPClient(t *testing.T) {
+	transport := roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": {"application/json"}},
+			Body:       io.NopCloser(strings.NewReader(`{"city":"NEW YORK","state":"NY","ZIPCode":"10001"}`)),
+			Request:    r,
+		}, nil
+	})
+
+	shared := &http.Client{Timeout: 7 * time.Second}
+	client := NewClient(NewStaticTokenProvider("test-token"), WithHTTPClient(shared), WithTransport(transport))
+
+	if shared.Transport != nil {
+		t.Errorf("Expected the caller's HTTP client to keep a nil Transport, got %T", shared.Transport)
+	}
+	if client.httpClient == shared {
+		t.Error("Expected the client to use a copy of the caller's HTTP client")
+	}
+	if client.httpClient.Timeout != 7*time.Second {
+		t.Errorf("Expected the copy to keep timeout 7s, got %v", client.httpClient.Timeout)
+	}
+	if _, err := client.GetCityState(context.Background(), &models.CityStateRequest{ZIPCode: "10001"}); err != nil {
+		t.Fatalf("GetCityState failed: %v", err)
+	}
+}
+
 func TestGetAddress_AdditionalInfoAndCorrections(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		response := models.AddressResponse{
@@ -867,7 +1005,7 @@ func TestDoRequest_StructToURLValuesError(t *testing.T) {
 	ctx := context.Background()
 
 	// Pass a non-struct value as queryParams
-	_, err := client.doRequest(ctx, http.MethodGet, "/test", "not a struct")
+	_, err := client.doRequest(ctx, "", http.MethodGet, "/test", "not a struct")
 	if err == nil {
 		t.Fatal("Expected error from structToURLValues, got nil")
 	}
@@ -884,7 +1022,7 @@ func TestDoRequest_InvalidURL(t *testing.T) {
 	ctx := context.Background()
 
 	// Use an invalid HTTP method to trigger the error
-	_, err := client.doRequest(ctx, "INVALID\nMETHOD", "/test", nil)
+	_, err := client.doRequest(ctx, "", "INVALID\nMETHOD", "/test", nil)
 	if err == nil {
 		t.Fatal("Expected error for invalid request, got nil")
 	}
@@ -990,3 +1128,1156 @@ func (r *failingReader) Close() error {
 func stringPtr(s string) *string {
 	return &s
 }
+
+func TestWithForwardHeadersFromContext(t *testing.T) {
+	var gotTraceparent, gotRequestID, gotOther string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotTraceparent = r.Header.Get("traceparent")
+		gotRequestID = r.Header.Get("X-Request-ID")
+		gotOther = r.Header.Get("X-Not-Forwarded")
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(models.CityStateResponse{City: "NEW YORK", State: "NY", ZIPCode: "10001"})
+	}))
+	defer server.Close()
+
+	client := NewClient(
+		NewStaticTokenProvider("test-token"),
+		WithBaseURL(server.URL),
+		WithForwardHeadersFromContext("traceparent", "x-request-id", "X-Missing"),
+	)
+
+	inbound := http.Header{}
+	inbound.Set("Traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	inbound.Set("X-Request-Id", "req-123")
+	inbound.Set("X-Not-Forwarded", "secret")
+	ctx := ContextWithForwardedHeaders(context.Background(), inbound)
+
+	if _, err := client.GetCityState(ctx, &models.CityStateRequest{ZIPCode: "10001"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if gotTraceparent != "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01" {
+		t.Errorf("Expected traceparent to be forwarded, got '%s'", gotTraceparent)
+	}
+	if gotRequestID != "req-123" {
+		t.Errorf("Expected X-Request-ID 'req-123', got '%s'", gotRequestID)
+	}
+	if gotOther != "" {
+		t.Errorf("Expected unconfigured header to be dropped, got '%s'", gotOther)
+	}
+}
+
+func TestWithForwardHeadersFromContext_NoHeadersInContext(t *testing.T) {
+	var gotRequestID string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotRequestID = r.Header.Get("X-Request-ID")
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(models.CityStateResponse{City: "NEW YORK", State: "NY", ZIPCode: "10001"})
+	}))
+	defer server.Close()
+
+	client := NewClient(
+		NewStaticTokenProvider("test-token"),
+		WithBaseURL(server.URL),
+		WithForwardHeadersFromContext("X-Request-ID"),
+	)
+
+	if _, err := client.GetCityState(context.Background(), &models.CityStateRequest{ZIPCode: "10001"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if gotRequestID != "" {
+		t.Errorf("Expected no X-Request-ID header, got '%s'", gotRequestID)
+	}
+}
+
+func TestContextWithResponseMeta(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Request-ID", "usps-req-42")
+		w.Header().Set("X-API-Version", "3.0.1")
+		switch r.URL.Path {
+		case "/address":
+			_ = json.NewEncoder(w).Encode(models.AddressResponse{Address: &models.DomesticAddress{City: "NEW YORK"}})
+		case "/city-state":
+			_ = json.NewEncoder(w).Encode(models.CityStateResponse{City: "NEW YORK"})
+		case "/zipcode":
+			_ = json.NewEncoder(w).Encode(models.ZIPCodeResponse{Address: &models.DomesticAddress{ZIPCode: "10001"}})
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(NewStaticTokenProvider("test-token"), WithBaseURL(server.URL))
+
+	calls := map[string]func(ctx context.Context) error{
+		"GetAddress": func(ctx context.Context) error {
+			_, err := client.GetAddress(ctx, &models.AddressRequest{StreetAddress: "123 Main St", State: "NY"})
+			return err
+		},
+		"GetCityState": func(ctx context.Context) error {
+			_, err := client.GetCityState(ctx, &models.CityStateRequest{ZIPCode: "10001"})
+			return err
+		},
+		"GetZIPCode": func(ctx context.Context) error {
+			_, err := client.GetZIPCode(ctx, &models.ZIPCodeRequest{StreetAddress: "123 Main St", City: "New York", State: "NY"})
+			return err
+		},
+	}
+
+	for name, call := range calls {
+		t.Run(name, func(t *testing.T) {
+			var meta ResponseMeta
+			if err := call(ContextWithResponseMeta(context.Background(), &meta)); err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if meta.StatusCode != http.StatusOK {
+				t.Errorf("Expected status 200, got %d", meta.StatusCode)
+			}
+			if meta.RequestID() != "usps-req-42" {
+				t.Errorf("Expected request id 'usps-req-42', got '%s'", meta.RequestID())
+			}
+			if meta.Header.Get("X-API-Version") != "3.0.1" {
+				t.Errorf("Expected X-API-Version '3.0.1', got '%s'", meta.Header.Get("X-API-Version"))
+			}
+		})
+	}
+}
+
+func TestResponseMeta_RequestIDMissing(t *testing.T) {
+	var nilMeta *ResponseMeta
+	if nilMeta.RequestID() != "" {
+		t.Error("Expected empty request id for nil meta")
+	}
+
+	meta := &ResponseMeta{Header: http.Header{}}
+	if meta.RequestID() != "" {
+		t.Errorf("Expected empty request id, got '%s'", meta.RequestID())
+	}
+}
+
+func TestGetAddress_ArrayWrappedResponse(t *testing.T) {
+	tests := []struct {
+		name         string
+		body         string
+		wantWarnings int
+	}{
+		{
+			name: "object",
+			body: `{"address": {"streetAddress": "123 MAIN ST", "city": "NEW YORK", "state": "NY", "ZIPCode": "10001"}}`,
+		},
+		{
+			name: "single element array",
+			body: `[{"address": {"streetAddress": "123 MAIN ST", "city": "NEW YORK", "state": "NY", "ZIPCode": "10001"}}]`,
+		},
+		{
+			name:         "multi element array",
+			body:         `[{"address": {"streetAddress": "123 MAIN ST", "city": "NEW YORK", "state": "NY", "ZIPCode": "10001"}}, {"address": {"streetAddress": "125 MAIN ST"}}]`,
+			wantWarnings: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			client := NewClient(NewStaticTokenProvider("test-token"), WithBaseURL(server.URL))
+
+			resp, err := client.GetAddress(context.Background(), &models.AddressRequest{
+				StreetAddress: "123 Main St",
+				City:          "New York",
+				State:         "NY",
+			})
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if resp.Address == nil || resp.Address.StreetAddress != "123 MAIN ST" {
+				t.Fatalf("Expected first address 123 MAIN ST, got %+v", resp.Address)
+			}
+			if resp.Address.ZIPCode != "10001" {
+				t.Errorf("Expected ZIP code '10001', got '%s'", resp.Address.ZIPCode)
+			}
+			if len(resp.Warnings) != tt.wantWarnings {
+				t.Errorf("Expected %d warnings, got %v", tt.wantWarnings, resp.Warnings)
+			}
+		})
+	}
+}
+
+func TestWithResultObserver(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/address":
+			_, _ = w.Write([]byte(`{"address": {"streetAddress": "123 MAIN ST"}}`))
+		case "/city-state":
+			_, _ = w.Write([]byte(`{"city": "NEW YORK", "state": "NY", "ZIPCode": "10001"}`))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error": {"code": "400", "message": "Invalid request"}}`))
+		}
+	}))
+	defer server.Close()
+
+	type observation struct {
+		endpoint string
+		result   interface{}
+		err      error
+	}
+	var observed []observation
+
+	client := NewClient(
+		NewStaticTokenProvider("test-token"),
+		WithBaseURL(server.URL),
+		WithResultObserver(func(endpoint string, result interface{}, err error) {
+			observed = append(observed, observation{endpoint, result, err})
+		}),
+	)
+
+	ctx := context.Background()
+	addrResp, _ := client.GetAddress(ctx, &models.AddressRequest{StreetAddress: "123 Main St", State: "NY"})
+	cityResp, _ := client.GetCityState(ctx, &models.CityStateRequest{ZIPCode: "10001"})
+	_, zipErr := client.GetZIPCode(ctx, &models.ZIPCodeRequest{StreetAddress: "123 Main St", City: "New York", State: "NY"})
+
+	if len(observed) != 3 {
+		t.Fatalf("Expected 3 observations, got %d", len(observed))
+	}
+
+	if observed[0].endpoint != EndpointAddress {
+		t.Errorf("Expected endpoint 'address', got '%s'", observed[0].endpoint)
+	}
+	if got, ok := observed[0].result.(*models.AddressResponse); !ok || got != addrResp {
+		t.Errorf("Expected *models.AddressResponse %p, got %#v", addrResp, observed[0].result)
+	}
+	if observed[0].err != nil {
+		t.Errorf("Expected no error, got %v", observed[0].err)
+	}
+
+	if observed[1].endpoint != EndpointCityState {
+		t.Errorf("Expected endpoint 'city-state', got '%s'", observed[1].endpoint)
+	}
+	if got, ok := observed[1].result.(*models.CityStateResponse); !ok || got != cityResp {
+		t.Errorf("Expected *models.CityStateResponse %p, got %#v", cityResp, observed[1].result)
+	}
+
+	if observed[2].endpoint != EndpointZIPCode {
+		t.Errorf("Expected endpoint 'zipcode', got '%s'", observed[2].endpoint)
+	}
+	if observed[2].result != nil {
+		t.Errorf("Expected nil result on error, got %#v", observed[2].result)
+	}
+	if zipErr == nil || observed[2].err != zipErr {
+		t.Errorf("Expected observed error %v, got %v", zipErr, observed[2].err)
+	}
+}
+
+func TestGetAddress_Country(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Query().Has("country") || r.URL.Query().Has("Country") {
+			t.Errorf("Expected country not to be sent, got query %s", r.URL.RawQuery)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"address": {"streetAddress": "123 MAIN ST"}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(NewStaticTokenProvider("test-token"), WithBaseURL(server.URL))
+	ctx := context.Background()
+
+	_, err := client.GetAddress(ctx, &models.AddressRequest{StreetAddress: "123 Main St", State: "NY", Country: "US"})
+	if err != nil {
+		t.Fatalf("Expected no error for US country, got %v", err)
+	}
+
+	_, err = client.GetAddress(ctx, &models.AddressRequest{StreetAddress: "123 Main St", State: "ON", Country: "CA"})
+	if !errors.Is(err, ErrUnsupportedCountry) {
+		t.Fatalf("Expected ErrUnsupportedCountry, got %v", err)
+	}
+	if !strings.Contains(err.Error(), `"CA"`) {
+		t.Errorf("Expected error to name the country, got %q", err.Error())
+	}
+
+	if requests != 1 {
+		t.Errorf("Expected only the US request to reach the server, got %d requests", requests)
+	}
+}
+
+func TestGetAddress_NonDeliverableIsNotError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"address": {"streetAddress": "999 MAIN ST", "city": "NEW YORK", "state": "NY", "ZIPCode": "10001"},
+			"additionalInfo": {"DPVConfirmation": "N"}
+		}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(NewStaticTokenProvider("test-token"), WithBaseURL(server.URL))
+
+	resp, err := client.GetAddress(context.Background(), &models.AddressRequest{
+		StreetAddress: "999 Main St",
+		City:          "New York",
+		State:         "NY",
+	})
+	if err != nil {
+		t.Fatalf("Expected no error for non-deliverable address, got %v", err)
+	}
+	if resp == nil || resp.Address == nil {
+		t.Fatal("Expected standardized address in response")
+	}
+	if resp.Address.StreetAddress != "999 MAIN ST" {
+		t.Errorf("Expected street address '999 MAIN ST', got '%s'", resp.Address.StreetAddress)
+	}
+	if resp.IsDeliverable() {
+		t.Error("Expected IsDeliverable to be false for DPV N")
+	}
+}
+
+func TestClient_Config(t *testing.T) {
+	client := NewClient(
+		NewStaticTokenProvider("secret-token"),
+		WithBaseURL("https://example.test"),
+		WithHTTPClient(&http.Client{Transport: &failingTransport{}}),
+		WithTimeout(45*time.Second),
+		WithForwardHeadersFromContext("x-request-id"),
+		WithResultObserver(func(string, interface{}, error) {}),
+	)
+
+	cfg := client.Config()
+
+	if cfg.BaseURL != "https://example.test" {
+		t.Errorf("Expected BaseURL 'https://example.test', got '%s'", cfg.BaseURL)
+	}
+	if cfg.Timeout != 45*time.Second {
+		t.Errorf("Expected Timeout 45s, got %v", cfg.Timeout)
+	}
+	if !cfg.CustomTransport {
+		t.Error("Expected CustomTransport to be true")
+	}
+	if cfg.TokenProvider != "*usps.StaticTokenProvider" {
+		t.Errorf("Expected TokenProvider '*usps.StaticTokenProvider', got '%s'", cfg.TokenProvider)
+	}
+	if len(cfg.ForwardHeaders) != 1 || cfg.ForwardHeaders[0] != "X-Request-Id" {
+		t.Errorf("Expected ForwardHeaders [X-Request-Id], got %v", cfg.ForwardHeaders)
+	}
+	if !cfg.ResultObserver {
+		t.Error("Expected ResultObserver to be true")
+	}
+	if strings.Contains(fmt.Sprintf("%+v", cfg), "secret-token") {
+		t.Error("Config must not expose credentials")
+	}
+
+	// The snapshot is a copy
+	cfg.ForwardHeaders[0] = "Changed"
+	if client.Config().ForwardHeaders[0] != "X-Request-Id" {
+		t.Error("Modifying the snapshot must not affect the client")
+	}
+}
+
+func TestClient_ConfigDefaults(t *testing.T) {
+	cfg := NewClient(NewStaticTokenProvider("token")).Config()
+
+	if cfg.BaseURL != ProductionBaseURL {
+		t.Errorf("Expected BaseURL %s, got %s", ProductionBaseURL, cfg.BaseURL)
+	}
+	if cfg.Timeout != DefaultTimeout {
+		t.Errorf("Expected Timeout %v, got %v", DefaultTimeout, cfg.Timeout)
+	}
+	if cfg.CustomTransport || cfg.ResultObserver || cfg.Logger || len(cfg.ForwardHeaders) != 0 {
+		t.Errorf("Expected no customizations, got %+v", cfg)
+	}
+}
+
+func TestWithRequestURLCallback(t *testing.T) {
+	var receivedURL string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		receivedURL = r.URL.String()
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"address": {"streetAddress": "123 MAIN ST APT 4"}}`))
+	}))
+	defer server.Close()
+
+	var endpoints, urls []string
+	client := NewClient(
+		NewStaticTokenProvider("test-token"),
+		WithBaseURL(server.URL),
+		WithRequestURLCallback(func(endpoint, url string) {
+			endpoints = append(endpoints, endpoint)
+			urls = append(urls, url)
+		}),
+	)
+
+	_, err := client.GetAddress(context.Background(), &models.AddressRequest{
+		StreetAddress:    "123 Main St",
+		SecondaryAddress: "Apt #4",
+		City:             "New York",
+		State:            "NY",
+	})
+	if err != nil {
+		t.Fatalf("GetAddress failed: %v", err)
+	}
+
+	if len(urls) != 1 {
+		t.Fatalf("Expected 1 callback, got %d", len(urls))
+	}
+	if endpoints[0] != EndpointAddress {
+		t.Errorf("Expected endpoint 'address', got '%s'", endpoints[0])
+	}
+	want := server.URL + "/address?city=New+York&secondaryAddress=Apt+%234&state=NY&streetAddress=123+Main+St"
+	if urls[0] != want {
+		t.Errorf("Expected URL %s, got %s", want, urls[0])
+	}
+	if server.URL+receivedURL != urls[0] {
+		t.Errorf("Expected callback URL to match the request sent (%s), got %s", server.URL+receivedURL, urls[0])
+	}
+
+	_ = client.HealthCheck(context.Background())
+	if len(urls) != 1 {
+		t.Errorf("Expected HealthCheck not to call the callback, got %d calls", len(urls))
+	}
+}
+
+// recordingLogger is a Logger that records every call.
+type recordingLogger struct {
+	methods   []string
+	urls      []string
+	statuses  []int
+	durations []time.Duration
+}
+
+func (l *recordingLogger) LogRequest(method, url string) {
+	l.methods = append(l.methods, method)
+	l.urls = append(l.urls, url)
+}
+
+func (l *recordingLogger) LogResponse(statusCode int, duration time.Duration) {
+	l.statuses = append(l.statuses, statusCode)
+	l.durations = append(l.durations, duration)
+}
+
+func TestWithLogger(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		_ = json.NewEncoder(w).Encode(models.ErrorMessage{
+			Error: &models.ErrorInfo{Code: "404", Message: "Not found"},
+		})
+	}))
+	defer server.Close()
+
+	logger := &recordingLogger{}
+	client := NewClient(NewStaticTokenProvider("secret-token"), WithBaseURL(server.URL), WithLogger(logger))
+
+	_, err := client.GetCityState(context.Background(), &models.CityStateRequest{ZIPCode: "10001"})
+	if err == nil {
+		t.Fatal("Expected error for 404 response")
+	}
+
+	if len(logger.methods) != 1 || logger.methods[0] != http.MethodGet {
+		t.Fatalf("Expected one GET request to be logged, got %v", logger.methods)
+	}
+	loggedURL, err := url.Parse(logger.urls[0])
+	if err != nil {
+		t.Fatalf("Logged URL did not parse: %v", err)
+	}
+	if loggedURL.Path != "/city-state" {
+		t.Errorf("Expected path /city-state, got %s", loggedURL.Path)
+	}
+	if len(logger.statuses) != 1 || logger.statuses[0] != http.StatusNotFound {
+		t.Errorf("Expected status 404 to be logged, got %v", logger.statuses)
+	}
+	if logger.durations[0] <= 0 {
+		t.Errorf("Expected a positive duration, got %v", logger.durations[0])
+	}
+	if strings.Contains(fmt.Sprintf("%v", logger), "secret-token") {
+		t.Error("Logger must not receive the bearer token")
+	}
+	if !client.Config().Logger {
+		t.Error("Expected Config().Logger to be true")
+	}
+}
+
+func TestWithLogger_TransportError(t *testing.T) {
+	logger := &recordingLogger{}
+	client := NewClient(
+		NewStaticTokenProvider("test-token"),
+		WithHTTPClient(&http.Client{Transport: &failingTransport{}}),
+		WithLogger(logger),
+	)
+
+	if _, err := client.GetCityState(context.Background(), &models.CityStateRequest{ZIPCode: "10001"}); err == nil {
+		t.Fatal("Expected transport error")
+	}
+	if len(logger.statuses) != 1 || logger.statuses[0] != 0 {
+		t.Errorf("Expected status 0 to be logged, got %v", logger.statuses)
+	}
+}
+
+func TestWithLogger_Nil(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(models.CityStateResponse{City: "NEW YORK", State: "NY", ZIPCode: "10001"})
+	}))
+	defer server.Close()
+
+	client := NewClient(NewStaticTokenProvider("test-token"), WithBaseURL(server.URL), WithLogger(nil))
+
+	if _, err := client.GetCityState(context.Background(), &models.CityStateRequest{ZIPCode: "10001"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+}
+
+func TestHealthCheck_Success(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/city-state" {
+			t.Errorf("Expected path /city-state, got %s", r.URL.Path)
+		}
+		if got := r.URL.Query().Get("ZIPCode"); got != "20500" {
+			t.Errorf("Expected ZIPCode 20500, got %s", got)
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer test-token" {
+			t.Errorf("Expected Authorization 'Bearer test-token', got %s", got)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(models.CityStateResponse{City: "WASHINGTON", State: "DC", ZIPCode: "20500"})
+	}))
+	defer server.Close()
+
+	client := NewClient(NewStaticTokenProvider("test-token"), WithBaseURL(server.URL))
+
+	if err := client.HealthCheck(context.Background()); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+}
+
+func TestHealthCheck_BadCredentials(t *testing.T) {
+	called := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer server.Close()
+
+	tokenErr := errors.New("invalid client credentials")
+	client := NewClient(&mockTokenProvider{err: tokenErr}, WithBaseURL(server.URL))
+
+	err := client.HealthCheck(context.Background())
+	if !errors.Is(err, tokenErr) {
+		t.Fatalf("Expected token error, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "failed to get token") {
+		t.Errorf("Expected descriptive token error, got %q", err.Error())
+	}
+	if called {
+		t.Error("Expected no API request when the token cannot be obtained")
+	}
+}
+
+func TestHealthCheck_Unauthorized(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnauthorized)
+		_ = json.NewEncoder(w).Encode(models.ErrorMessage{
+			Error: &models.ErrorInfo{Code: "401", Message: "Unauthorized"},
+		})
+	}))
+	defer server.Close()
+
+	client := NewClient(NewStaticTokenProvider("revoked-token"), WithBaseURL(server.URL))
+
+	err := client.HealthCheck(context.Background())
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("Expected APIError, got %v", err)
+	}
+	if apiErr.StatusCode != http.StatusUnauthorized {
+		t.Errorf("Expected status code %d, got %d", http.StatusUnauthorized, apiErr.StatusCode)
+	}
+}
+
+func TestHealthCheck_UnreachableHost(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	baseURL := server.URL
+	server.Close()
+
+	client := NewClient(NewStaticTokenProvider("test-token"), WithBaseURL(baseURL))
+
+	err := client.HealthCheck(context.Background())
+	if err == nil {
+		t.Fatal("Expected error for unreachable host, got nil")
+	}
+	if !strings.Contains(err.Error(), baseURL) {
+		t.Errorf("Expected error to name %s, got %q", baseURL, err.Error())
+	}
+}
+
+func TestGetAddress_ErrorWithCorrections(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{
+			"apiVersion": "3.0",
+			"error": {"code": "400", "message": "Address Not Found."},
+			"corrections": [
+				{"code": "32", "text": "Default address: The address you entered was found but more information is needed (such as an apartment, suite, or box number) to match to a specific address."},
+				{"code": "22", "text": "Multiple addresses were found for the information you entered, and no default exists."}
+			],
+			"matches": [{"code": "31", "text": "Single Response - exact match"}]
+		}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(NewStaticTokenProvider("test-token"), WithBaseURL(server.URL))
+
+	_, err := client.GetAddress(context.Background(), &models.AddressRequest{
+		StreetAddress: "123 Nowhere St",
+		State:         "NY",
+		ZIPCode:       "10001",
+	})
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("Expected APIError, got %v", err)
+	}
+	if apiErr.ErrorMessage.Error == nil || apiErr.ErrorMessage.Error.Message != "Address Not Found." {
+		t.Errorf("Expected error message 'Address Not Found.', got %+v", apiErr.ErrorMessage.Error)
+	}
+	if len(apiErr.Corrections) != 2 {
+		t.Fatalf("Expected 2 corrections, got %d", len(apiErr.Corrections))
+	}
+	if apiErr.Corrections[0].Code != "32" || apiErr.Corrections[1].Code != "22" {
+		t.Errorf("Expected correction codes 32 and 22, got %s and %s", apiErr.Corrections[0].Code, apiErr.Corrections[1].Code)
+	}
+	if len(apiErr.Matches) != 1 || apiErr.Matches[0].Code != "31" {
+		t.Errorf("Expected one match with code 31, got %+v", apiErr.Matches)
+	}
+}
+
+func TestGetAddress_ErrorRetryAfter(t *testing.T) {
+	tests := []struct {
+		name       string
+		retryAfter string
+		want       time.Duration
+		wantOK     bool
+	}{
+		{name: "seconds", retryAfter: "120", want: 120 * time.Second, wantOK: true},
+		{name: "absent", retryAfter: "", want: 0, wantOK: false},
+		{name: "invalid", retryAfter: "soon", want: 0, wantOK: false},
+		{name: "past date", retryAfter: "Wed, 21 Oct 2015 07:28:00 GMT", want: 0, wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tt.retryAfter != "" {
+					w.Header().Set("Retry-After", tt.retryAfter)
+				}
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusTooManyRequests)
+				_ = json.NewEncoder(w).Encode(models.ErrorMessage{
+					Error: &models.ErrorInfo{Code: "429", Message: "Too many requests"},
+				})
+			}))
+			defer server.Close()
+
+			client := NewClient(NewStaticTokenProvider("test-token"), WithBaseURL(server.URL))
+
+			_, err := client.GetCityState(context.Background(), &models.CityStateRequest{ZIPCode: "10001"})
+
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("Expected APIError, got %v", err)
+			}
+			got, ok := apiErr.RetryAfterDuration()
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("Expected RetryAfterDuration() = (%v, %v), got (%v, %v)", tt.want, tt.wantOK, got, ok)
+			}
+		})
+	}
+}
+
+func TestParseRetryAfter_HTTPDate(t *testing.T) {
+	now := time.Date(2025, time.March, 1, 12, 0, 0, 0, time.UTC)
+	value := now.Add(90 * time.Second).Format(http.TimeFormat)
+
+	if got := parseRetryAfter(value, now); got != 90*time.Second {
+		t.Errorf("Expected 1m30s, got %v", got)
+	}
+}
+
+func TestGetAddress_ErrorWithoutCorrections(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(models.ErrorMessage{
+			Error: &models.ErrorInfo{Code: "400", Message: "Invalid request"},
+		})
+	}))
+	defer server.Close()
+
+	client := NewClient(NewStaticTokenProvider("test-token"), WithBaseURL(server.URL))
+
+	_, err := client.GetAddress(context.Background(), &models.AddressRequest{StreetAddress: "123 Main St", State: "NY"})
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("Expected APIError, got %v", err)
+	}
+	if apiErr.Corrections != nil || apiErr.Matches != nil {
+		t.Errorf("Expected no corrections or matches, got %+v and %+v", apiErr.Corrections, apiErr.Matches)
+	}
+}
+
+func TestWithResultObserver_EndpointLabels(t *testing.T) {
+	// Paths are prefixed by a gateway; labels must not change
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.URL.Path, "/gateway/addresses/v3/") {
+			t.Errorf("Expected gateway-prefixed path, got %s", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	var endpoints []string
+	client := NewClient(
+		NewStaticTokenProvider("test-token"),
+		WithBaseURL(server.URL+"/gateway/addresses/v3"),
+		WithResultObserver(func(endpoint string, result interface{}, err error) {
+			endpoints = append(endpoints, endpoint)
+		}),
+	)
+
+	ctx := context.Background()
+	_, _ = client.GetAddress(ctx, &models.AddressRequest{StreetAddress: "123 Main St", State: "NY"})
+	_, _ = client.GetCityState(ctx, &models.CityStateRequest{ZIPCode: "10001"})
+	_, _ = client.GetZIPCode(ctx, &models.ZIPCodeRequest{StreetAddress: "123 Main St", City: "New York", State: "NY"})
+
+	want := []string{"address", "city-state", "zipcode"}
+	if len(endpoints) != len(want) {
+		t.Fatalf("Expected %d observations, got %d", len(want), len(endpoints))
+	}
+	for i := range want {
+		if endpoints[i] != want[i] {
+			t.Errorf("Expected endpoint '%s', got '%s'", want[i], endpoints[i])
+		}
+	}
+}
+
+// correlationRecorder is a RoundTripper middleware that records the
+// correlation ID it sees, as a logging or metrics transport would.
+type correlationRecorder struct {
+	next http.RoundTripper
+	ids  []string
+}
+
+func (r *correlationRecorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	r.ids = append(r.ids, CorrelationIDFromContext(req.Context()))
+	return r.next.RoundTrip(req)
+}
+
+func TestWithObservabilityContext(t *testing.T) {
+	var serverIDs []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		serverIDs = append(serverIDs, r.Header.Get("X-Correlation-ID"))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"city": "NEW YORK", "state": "NY", "ZIPCode": "10001"}`))
+	}))
+	defer server.Close()
+
+	recorder := &correlationRecorder{next: http.DefaultTransport}
+	client := NewClient(
+		NewStaticTokenProvider("test-token"),
+		WithBaseURL(server.URL),
+		WithHTTPClient(&http.Client{Transport: recorder}),
+		WithObservabilityContext(),
+	)
+
+	var meta ResponseMeta
+	ctx := ContextWithResponseMeta(context.Background(), &meta)
+	if _, err := client.GetCityState(ctx, &models.CityStateRequest{ZIPCode: "10001"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(serverIDs) != 1 || len(recorder.ids) != 1 {
+		t.Fatalf("Expected one request, got %d at server and %d at transport", len(serverIDs), len(recorder.ids))
+	}
+	id := meta.CorrelationID
+	if len(id) != 32 {
+		t.Errorf("Expected a generated 32-character correlation ID, got %q", id)
+	}
+	if serverIDs[0] != id {
+		t.Errorf("Expected header correlation ID %q, got %q", id, serverIDs[0])
+	}
+	if recorder.ids[0] != id {
+		t.Errorf("Expected transport correlation ID %q, got %q", id, recorder.ids[0])
+	}
+
+	// A second call gets a new ID
+	var meta2 ResponseMeta
+	_, _ = client.GetCityState(ContextWithResponseMeta(context.Background(), &meta2), &models.CityStateRequest{ZIPCode: "10001"})
+	if meta2.CorrelationID == "" || meta2.CorrelationID == id {
+		t.Errorf("Expected a new correlation ID, got %q", meta2.CorrelationID)
+	}
+}
+
+// contextLogger is a ContextLogger that records the correlation ID of each call.
+type contextLogger struct {
+	recordingLogger
+	requestIDs  []string
+	responseIDs []string
+}
+
+func (l *contextLogger) LogRequestContext(ctx context.Context, method, url string) {
+	l.requestIDs = append(l.requestIDs, CorrelationIDFromContext(ctx))
+}
+
+func (l *contextLogger) LogResponseContext(ctx context.Context, statusCode int, duration time.Duration) {
+	l.responseIDs = append(l.responseIDs, CorrelationIDFromContext(ctx))
+}
+
+func TestWithObservabilityContext_SharedWithLoggerAndObserver(t *testing.T) {
+	var headerIDs []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headerIDs = append(headerIDs, r.Header.Get("X-Correlation-ID"))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"city": "NEW YORK", "state": "NY", "ZIPCode": "10001"}`))
+	}))
+	defer server.Close()
+
+	recorder := &correlationRecorder{next: http.DefaultTransport}
+	logger := &contextLogger{}
+	var observedIDs []string
+	client := NewClient(
+		NewStaticTokenProvider("test-token"),
+		WithBaseURL(server.URL),
+		WithHTTPClient(&http.Client{Transport: recorder}),
+		WithLogger(logger),
+		WithResultObserverContext(func(ctx context.Context, endpoint string, result interface{}, err error) {
+			observedIDs = append(observedIDs, CorrelationIDFromContext(ctx))
+		}),
+		WithObservabilityContext(),
+	)
+
+	if _, err := client.GetCityState(context.Background(), &models.CityStateRequest{ZIPCode: "10001"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(headerIDs) != 1 || len(recorder.ids) != 1 || len(logger.requestIDs) != 1 || len(logger.responseIDs) != 1 || len(observedIDs) != 1 {
+		t.Fatalf("Expected one ID from each hook, got header %v, transport %v, logger %v/%v, observer %v",
+			headerIDs, recorder.ids, logger.requestIDs, logger.responseIDs, observedIDs)
+	}
+	id := headerIDs[0]
+	if id == "" {
+		t.Fatal("Expected a generated correlation ID")
+	}
+	for name, got := range map[string]string{
+		"transport":       recorder.ids[0],
+		"logger request":  logger.requestIDs[0],
+		"logger response": logger.responseIDs[0],
+		"observer":        observedIDs[0],
+	} {
+		if got != id {
+			t.Errorf("Expected %s correlation ID %q, got %q", name, id, got)
+		}
+	}
+	if len(logger.methods) != 0 || len(logger.statuses) != 0 {
+		t.Errorf("Expected the context methods in place of LogRequest and LogResponse, got %d and %d calls", len(logger.methods), len(logger.statuses))
+	}
+}
+
+func TestWithObservabilityContext_IDFromContext(t *testing.T) {
+	var serverID string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		serverID = r.Header.Get("X-Correlation-ID")
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(NewStaticTokenProvider("test-token"), WithBaseURL(server.URL), WithObservabilityContext())
+
+	var meta ResponseMeta
+	ctx := ContextWithCorrelationID(context.Background(), "req-123")
+	ctx = ContextWithResponseMeta(ctx, &meta)
+	_, _ = client.GetAddress(ctx, &models.AddressRequest{StreetAddress: "123 Main St", State: "NY"})
+
+	if serverID != "req-123" {
+		t.Errorf("Expected header correlation ID 'req-123', got %q", serverID)
+	}
+	if meta.CorrelationID != "req-123" {
+		t.Errorf("Expected ResponseMeta correlation ID 'req-123', got %q", meta.CorrelationID)
+	}
+}
+
+func TestWithObservabilityContext_Disabled(t *testing.T) {
+	var serverID string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		serverID = r.Header.Get("X-Correlation-ID")
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(NewStaticTokenProvider("test-token"), WithBaseURL(server.URL))
+
+	var meta ResponseMeta
+	ctx := ContextWithCorrelationID(context.Background(), "req-123")
+	_, _ = client.GetCityState(ContextWithResponseMeta(ctx, &meta), &models.CityStateRequest{ZIPCode: "10001"})
+
+	if serverID != "" || meta.CorrelationID != "" {
+		t.Errorf("Expected no correlation ID without the option, got header %q and meta %q", serverID, meta.CorrelationID)
+	}
+}
+
+func TestWithEnvironment(t *testing.T) {
+	const customURL = "https://usps.example.com/addresses/v3"
+
+	tests := []struct {
+		name    string
+		client  func(opts ...Option) *Client
+		ctx     context.Context
+		wantURL string
+	}{
+		{
+			name:    "no environment uses client base URL",
+			client:  func(opts ...Option) *Client { return NewClient(NewStaticTokenProvider("test-token"), opts...) },
+			ctx:     context.Background(),
+			wantURL: ProductionBaseURL,
+		},
+		{
+			name:    "testing on production client",
+			client:  func(opts ...Option) *Client { return NewClient(NewStaticTokenProvider("test-token"), opts...) },
+			ctx:     WithEnvironment(context.Background(), "testing"),
+			wantURL: TestingBaseURL,
+		},
+		{
+			name:    "production on test client",
+			client:  func(opts ...Option) *Client { return NewTestClient(NewStaticTokenProvider("test-token"), opts...) },
+			ctx:     WithEnvironment(context.Background(), "production"),
+			wantURL: ProductionBaseURL,
+		},
+		{
+			name:    "no environment on test client",
+			client:  func(opts ...Option) *Client { return NewTestClient(NewStaticTokenProvider("test-token"), opts...) },
+			ctx:     context.Background(),
+			wantURL: TestingBaseURL,
+		},
+		{
+			name: "empty environment uses custom base URL",
+			client: func(opts ...Option) *Client {
+				return NewClient(NewStaticTokenProvider("test-token"), append(opts, WithBaseURL(customURL))...)
+			},
+			ctx:     WithEnvironment(context.Background(), ""),
+			wantURL: customURL,
+		},
+		{
+			name: "testing keeps custom base URL",
+			client: func(opts ...Option) *Client {
+				return NewClient(NewStaticTokenProvider("test-token"), append(opts, WithBaseURL(customURL))...)
+			},
+			ctx:     WithEnvironment(context.Background(), "testing"),
+			wantURL: customURL,
+		},
+		{
+			name: "production keeps custom base URL",
+			client: func(opts ...Option) *Client {
+				return NewClient(NewStaticTokenProvider("test-token"), append(opts, WithBaseURL(customURL))...)
+			},
+			ctx:     WithEnvironment(context.Background(), "production"),
+			wantURL: customURL,
+		},
+		{
+			name: "unknown environment uses custom base URL",
+			client: func(opts ...Option) *Client {
+				return NewClient(NewStaticTokenProvider("test-token"), append(opts, WithBaseURL(customURL))...)
+			},
+			ctx:     WithEnvironment(context.Background(), "staging"),
+			wantURL: customURL,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotURLs []string
+			transport := roundTripFunc(func(r *http.Request) (*http.Response, error) {
+				u := *r.URL
+				u.RawQuery = ""
+				gotURLs = append(gotURLs, u.String())
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Header:     http.Header{"Content-Type": {"application/json"}},
+					Body:       io.NopCloser(strings.NewReader(`{}`)),
+					Request:    r,
+				}, nil
+			})
+			client := tt.client(WithTransport(transport))
+
+			if _, err := client.GetAddress(tt.ctx, &models.AddressRequest{StreetAddress: "123 Main St", State: "NY"}); err != nil {
+				t.Fatalf("GetAddress failed: %v", err)
+			}
+			if _, err := client.GetCityState(tt.ctx, &models.CityStateRequest{ZIPCode: "10001"}); err != nil {
+				t.Fatalf("GetCityState failed: %v", err)
+			}
+
+			want := []string{tt.wantURL + "/address", tt.wantURL + "/city-state"}
+			if !reflect.DeepEqual(gotURLs, want) {
+				t.Errorf("Expected URLs %v, got %v", want, gotURLs)
+			}
+		})
+	}
+}
+
+func TestWithEnvironment_CityStateCachePerEnvironment(t *testing.T) {
+	var hosts []string
+	transport := roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		hosts = append(hosts, r.URL.Host)
+		city := "NEW YORK"
+		if strings.HasPrefix(r.URL.Host, "apis-tem.") {
+			city = "TEST CITY"
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": {"application/json"}},
+			Body:       io.NopCloser(strings.NewReader(`{"city":"` + city + `","state":"NY","ZIPCode":"10001"}`)),
+			Request:    r,
+		}, nil
+	})
+	client := NewClient(NewStaticTokenProvider("test-token"), WithTransport(transport), WithCityStateCache(time.Hour))
+
+	req := &models.CityStateRequest{ZIPCode: "10001"}
+	testingCtx := WithEnvironment(context.Background(), "testing")
+	wantCities := []struct {
+		ctx  context.Context
+		city string
+	}{
+		{testingCtx, "TEST CITY"},
+		{context.Background(), "NEW YORK"},
+		{testingCtx, "TEST CITY"},
+		{context.Background(), "NEW YORK"},
+	}
+	for i, want := range wantCities {
+		resp, err := client.GetCityState(want.ctx, req)
+		if err != nil {
+			t.Fatalf("GetCityState %d failed: %v", i, err)
+		}
+		if resp.City != want.city {
+			t.Errorf("GetCityState %d: expected city '%s', got '%s'", i, want.city, resp.City)
+		}
+	}
+
+	// One request per environment; the repeats are cached separately
+	if len(hosts) != 2 {
+		t.Errorf("Expected 2 requests, got %d: %v", len(hosts), hosts)
+	}
+}
+
+func TestEnvironmentFromContext(t *testing.T) {
+	if got := EnvironmentFromContext(context.Background()); got != "" {
+		t.Errorf("Expected empty environment, got '%s'", got)
+	}
+	if got := EnvironmentFromContext(WithEnvironment(context.Background(), "testing")); got != "testing" {
+		t.Errorf("Expected environment 'testing', got '%s'", got)
+	}
+}
+
+func TestWithUserAgent(t *testing.T) {
+	var userAgents []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userAgents = append(userAgents, r.Header.Get("User-Agent"))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(NewStaticTokenProvider("test-token"), WithBaseURL(server.URL), WithUserAgent("acme-checkout/2.3"))
+
+	ctx := context.Background()
+	if _, err := client.GetAddress(ctx, &models.AddressRequest{StreetAddress: "123 Main St", State: "NY"}); err != nil {
+		t.Fatalf("GetAddress failed: %v", err)
+	}
+	if _, err := client.GetCityState(ctx, &models.CityStateRequest{ZIPCode: "10001"}); err != nil {
+		t.Fatalf("GetCityState failed: %v", err)
+	}
+	if _, err := client.GetZIPCode(ctx, &models.ZIPCodeRequest{StreetAddress: "123 Main St", City: "New York", State: "NY"}); err != nil {
+		t.Fatalf("GetZIPCode failed: %v", err)
+	}
+
+	if len(userAgents) != 3 {
+		t.Fatalf("Expected 3 requests, got %d", len(userAgents))
+	}
+	for i, ua := range userAgents {
+		if ua != "acme-checkout/2.3" {
+			t.Errorf("Request %d: expected User-Agent 'acme-checkout/2.3', got '%s'", i, ua)
+		}
+	}
+	if got := client.Config().UserAgent; got != "acme-checkout/2.3" {
+		t.Errorf("Expected Config().UserAgent 'acme-checkout/2.3', got '%s'", got)
+	}
+}
+
+func TestWithUserAgent_Default(t *testing.T) {
+	var userAgent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userAgent = r.Header.Get("User-Agent")
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(NewStaticTokenProvider("test-token"), WithBaseURL(server.URL), WithUserAgent("  "))
+
+	if _, err := client.GetCityState(context.Background(), &models.CityStateRequest{ZIPCode: "10001"}); err != nil {
+		t.Fatalf("GetCityState failed: %v", err)
+	}
+	if userAgent != DefaultUserAgent {
+		t.Errorf("Expected User-Agent '%s', got '%s'", DefaultUserAgent, userAgent)
+	}
+	if !strings.HasPrefix(DefaultUserAgent, "go-usps/") {
+		t.Errorf("Expected DefaultUserAgent to start with 'go-usps/', got '%s'", DefaultUserAgent)
+	}
+}
+
+func TestWithQueryParameter(t *testing.T) {
+	var queries []url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.Query())
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"address": {"streetAddress": "123 MAIN ST"}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(NewStaticTokenProvider("test-token"), WithBaseURL(server.URL))
+	req := &models.AddressRequest{StreetAddress: "123 Main St", State: "NY"}
+
+	if _, err := client.GetAddress(context.Background(), req); err != nil {
+		t.Fatalf("GetAddress failed: %v", err)
+	}
+	_, err := client.GetAddress(context.Background(), req,
+		WithQueryParameter("returnText", "true"),
+		WithQueryParameter("state", "CA"),
+		WithQueryParameter(" ", "ignored"),
+	)
+	if err != nil {
+		t.Fatalf("GetAddress with query parameter failed: %v", err)
+	}
+
+	if len(queries) != 2 {
+		t.Fatalf("Expected 2 requests, got %d", len(queries))
+	}
+	if _, ok := queries[0]["returnText"]; ok {
+		t.Error("Expected no returnText parameter by default")
+	}
+	if got := queries[1].Get("returnText"); got != "true" {
+		t.Errorf("Expected returnText=true, got '%s'", got)
+	}
+	if got := queries[1]["state"]; len(got) != 1 || got[0] != "NY" {
+		t.Errorf("Expected request field state=NY to be kept, got %v", got)
+	}
+	if len(queries[1]) != 3 {
+		t.Errorf("Expected 3 query parameters, got %v", queries[1])
+	}
+}
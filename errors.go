@@ -0,0 +1,109 @@
+package usps
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+)
+
+var (
+	// ErrRequestTimeout matches (via errors.Is) requests that ran out of time,
+	// either because the caller's context deadline passed or because the HTTP
+	// client's timeout (see WithTimeout) elapsed.
+	ErrRequestTimeout = errors.New("request timed out")
+	// ErrRequestCanceled matches (via errors.Is) requests whose context was
+	// canceled by the caller before a response arrived.
+	ErrRequestCanceled = errors.New("request canceled")
+	// ErrUnsupportedCountry is returned when a request names a non-US country.
+	// The USPS address endpoints are domestic only, so such requests are
+	// rejected before anything is sent.
+	ErrUnsupportedCountry = errors.New("unsupported country: only US addresses are supported")
+	// ErrUnparseableAddress is returned by GetAddressFromText when the parser
+	// reports an error-severity diagnostic, so nothing is sent to USPS.
+	ErrUnparseableAddress = errors.New("address text could not be parsed")
+	// ErrInvalidScope is returned by OAuthTokenProvider.GetToken when a scope
+	// passed to WithOAuthScopeList contains whitespace.
+	ErrInvalidScope = errors.New("invalid OAuth scope")
+
+	// ErrBadRequest matches (via errors.Is) an APIError with status 400.
+	ErrBadRequest = errors.New("bad request")
+	// ErrUnauthorized matches (via errors.Is) an APIError with status 401,
+	// usually an expired or invalid OAuth token.
+	ErrUnauthorized = errors.New("unauthorized")
+	// ErrNotFound matches (via errors.Is) an APIError with status 404, such as
+	// an address or ZIP code USPS does not recognize.
+	ErrNotFound = errors.New("not found")
+	// ErrRateLimited matches (via errors.Is) an APIError with status 429.
+	// See APIError.RetryAfterDuration for how long to wait.
+	ErrRateLimited = errors.New("rate limited")
+)
+
+// apiErrorSentinels maps HTTP status codes to the sentinel errors an APIError
+// with that status matches.
+var apiErrorSentinels = map[int]error{
+	http.StatusBadRequest:      ErrBadRequest,
+	http.StatusUnauthorized:    ErrUnauthorized,
+	http.StatusNotFound:        ErrNotFound,
+	http.StatusTooManyRequests: ErrRateLimited,
+}
+
+// TimeoutError is returned when a request does not complete because of a
+// deadline or cancellation. Use errors.Is with ErrRequestTimeout or
+// ErrRequestCanceled, or inspect the fields to tell the causes apart.
+type TimeoutError struct {
+	// Canceled is true when the caller canceled the context.
+	Canceled bool
+	// ClientTimeout is true when the HTTP client's own timeout elapsed rather
+	// than the caller's context deadline.
+	ClientTimeout bool
+	// Timeout is the HTTP client timeout in effect when ClientTimeout is true.
+	Timeout time.Duration
+	// Err is the underlying error from the HTTP client.
+	Err error
+}
+
+// Error implements the error interface
+func (e *TimeoutError) Error() string {
+	switch {
+	case e.Canceled:
+		return fmt.Sprintf("request canceled by caller: %v", e.Err)
+	case e.ClientTimeout:
+		return fmt.Sprintf("request exceeded client timeout of %s: %v", e.Timeout, e.Err)
+	default:
+		return fmt.Sprintf("request exceeded context deadline: %v", e.Err)
+	}
+}
+
+// Unwrap returns the underlying error
+func (e *TimeoutError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether the error matches ErrRequestTimeout or ErrRequestCanceled
+func (e *TimeoutError) Is(target error) bool {
+	if e.Canceled {
+		return target == ErrRequestCanceled
+	}
+	return target == ErrRequestTimeout
+}
+
+// wrapTransportError converts a failure from http.Client.Do into a TimeoutError
+// when it was caused by the caller's context or the client timeout.
+func wrapTransportError(ctx context.Context, timeout time.Duration, err error) error {
+	switch ctxErr := ctx.Err(); {
+	case errors.Is(ctxErr, context.Canceled):
+		return &TimeoutError{Canceled: true, Err: err}
+	case errors.Is(ctxErr, context.DeadlineExceeded):
+		return &TimeoutError{Err: err}
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return &TimeoutError{ClientTimeout: true, Timeout: timeout, Err: err}
+	}
+
+	return fmt.Errorf("failed to execute request: %w", err)
+}
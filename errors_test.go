@@ -0,0 +1,251 @@
+package usps
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/my-eq/go-usps/models"
+)
+
+func newSlowServer(delay time.Duration) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(delay):
+		case <-r.Context().Done():
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{}`))
+	}))
+}
+
+func TestDoRequest_ContextDeadline(t *testing.T) {
+	server := newSlowServer(time.Second)
+	defer server.Close()
+
+	client := NewClient(NewStaticTokenProvider("test-token"), WithBaseURL(server.URL))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	_, err := client.GetCityState(ctx, &models.CityStateRequest{ZIPCode: "10001"})
+
+	var timeoutErr *TimeoutError
+	if !errors.As(err, &timeoutErr) {
+		t.Fatalf("Expected *TimeoutError, got %T: %v", err, err)
+	}
+	if timeoutErr.Canceled || timeoutErr.ClientTimeout {
+		t.Errorf("Expected context deadline cause, got %+v", timeoutErr)
+	}
+	if !errors.Is(err, ErrRequestTimeout) {
+		t.Error("Expected errors.Is(err, ErrRequestTimeout)")
+	}
+	if errors.Is(err, ErrRequestCanceled) {
+		t.Error("Did not expect errors.Is(err, ErrRequestCanceled)")
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Error("Expected underlying context.DeadlineExceeded to be preserved")
+	}
+	if !strings.Contains(err.Error(), "context deadline") {
+		t.Errorf("Expected message to mention context deadline, got %q", err.Error())
+	}
+}
+
+func TestDoRequest_ContextCanceled(t *testing.T) {
+	server := newSlowServer(time.Second)
+	defer server.Close()
+
+	client := NewClient(NewStaticTokenProvider("test-token"), WithBaseURL(server.URL))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+
+	_, err := client.GetCityState(ctx, &models.CityStateRequest{ZIPCode: "10001"})
+
+	var timeoutErr *TimeoutError
+	if !errors.As(err, &timeoutErr) {
+		t.Fatalf("Expected *TimeoutError, got %T: %v", err, err)
+	}
+	if !timeoutErr.Canceled {
+		t.Errorf("Expected Canceled to be true, got %+v", timeoutErr)
+	}
+	if !errors.Is(err, ErrRequestCanceled) {
+		t.Error("Expected errors.Is(err, ErrRequestCanceled)")
+	}
+	if errors.Is(err, ErrRequestTimeout) {
+		t.Error("Did not expect errors.Is(err, ErrRequestTimeout)")
+	}
+	if !errors.Is(err, context.Canceled) {
+		t.Error("Expected underlying context.Canceled to be preserved")
+	}
+}
+
+func TestDoRequest_ClientTimeout(t *testing.T) {
+	server := newSlowServer(time.Second)
+	defer server.Close()
+
+	client := NewClient(
+		NewStaticTokenProvider("test-token"),
+		WithBaseURL(server.URL),
+		WithTimeout(20*time.Millisecond),
+	)
+
+	_, err := client.GetCityState(context.Background(), &models.CityStateRequest{ZIPCode: "10001"})
+
+	var timeoutErr *TimeoutError
+	if !errors.As(err, &timeoutErr) {
+		t.Fatalf("Expected *TimeoutError, got %T: %v", err, err)
+	}
+	if !timeoutErr.ClientTimeout {
+		t.Errorf("Expected ClientTimeout to be true, got %+v", timeoutErr)
+	}
+	if timeoutErr.Timeout != 20*time.Millisecond {
+		t.Errorf("Expected Timeout 20ms, got %v", timeoutErr.Timeout)
+	}
+	if !errors.Is(err, ErrRequestTimeout) {
+		t.Error("Expected errors.Is(err, ErrRequestTimeout)")
+	}
+	if !strings.Contains(err.Error(), "client timeout of 20ms") {
+		t.Errorf("Expected message to mention client timeout, got %q", err.Error())
+	}
+}
+
+func TestDoRequest_NonTimeoutTransportError(t *testing.T) {
+	client := NewClient(NewStaticTokenProvider("test-token"), WithBaseURL("http://127.0.0.1:1"))
+
+	_, err := client.GetCityState(context.Background(), &models.CityStateRequest{ZIPCode: "10001"})
+	if err == nil {
+		t.Fatal("Expected error for unreachable host")
+	}
+
+	var timeoutErr *TimeoutError
+	if errors.As(err, &timeoutErr) {
+		t.Errorf("Did not expect *TimeoutError for connection failure: %v", err)
+	}
+	if !strings.Contains(err.Error(), "failed to execute request") {
+		t.Errorf("Expected generic execute error, got %q", err.Error())
+	}
+}
+
+func TestWithRequestDeadline(t *testing.T) {
+	server := newSlowServer(100 * time.Millisecond)
+	defer server.Close()
+
+	client := NewClient(NewStaticTokenProvider("test-token"), WithBaseURL(server.URL))
+	req := &models.CityStateRequest{ZIPCode: "10001"}
+
+	t.Run("short override times out", func(t *testing.T) {
+		start := time.Now()
+		_, err := client.GetCityState(context.Background(), req, WithRequestDeadline(10*time.Millisecond))
+		if !errors.Is(err, ErrRequestTimeout) {
+			t.Fatalf("Expected ErrRequestTimeout, got %v", err)
+		}
+		if elapsed := time.Since(start); elapsed >= 100*time.Millisecond {
+			t.Errorf("Expected the override to cut the call short, took %v", elapsed)
+		}
+	})
+
+	t.Run("generous override succeeds", func(t *testing.T) {
+		if _, err := client.GetCityState(context.Background(), req, WithRequestDeadline(5*time.Second)); err != nil {
+			t.Errorf("Expected no error, got %v", err)
+		}
+	})
+
+	t.Run("stricter caller deadline wins", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		_, err := client.GetCityState(ctx, req, WithRequestDeadline(5*time.Second))
+		if !errors.Is(err, ErrRequestTimeout) {
+			t.Errorf("Expected ErrRequestTimeout, got %v", err)
+		}
+	})
+
+	t.Run("no override uses client timeout", func(t *testing.T) {
+		short := NewClient(NewStaticTokenProvider("test-token"), WithBaseURL(server.URL), WithTimeout(10*time.Millisecond))
+
+		_, err := short.GetCityState(context.Background(), req)
+		var timeoutErr *TimeoutError
+		if !errors.As(err, &timeoutErr) || !timeoutErr.ClientTimeout {
+			t.Errorf("Expected client timeout error, got %v", err)
+		}
+	})
+}
+
+func TestWithRequestDeadline_AllMethods(t *testing.T) {
+	server := newSlowServer(100 * time.Millisecond)
+	defer server.Close()
+
+	client := NewClient(NewStaticTokenProvider("test-token"), WithBaseURL(server.URL))
+	ctx := context.Background()
+	deadline := WithRequestDeadline(10 * time.Millisecond)
+
+	_, err := client.GetAddress(ctx, &models.AddressRequest{StreetAddress: "123 Main St", State: "NY"}, deadline)
+	if !errors.Is(err, ErrRequestTimeout) {
+		t.Errorf("GetAddress: expected ErrRequestTimeout, got %v", err)
+	}
+	_, err = client.GetZIPCode(ctx, &models.ZIPCodeRequest{StreetAddress: "123 Main St", City: "New York", State: "NY"}, deadline)
+	if !errors.Is(err, ErrRequestTimeout) {
+		t.Errorf("GetZIPCode: expected ErrRequestTimeout, got %v", err)
+	}
+}
+
+func TestAPIError_IsSentinel(t *testing.T) {
+	sentinels := map[error]int{
+		ErrBadRequest:   http.StatusBadRequest,
+		ErrUnauthorized: http.StatusUnauthorized,
+		ErrNotFound:     http.StatusNotFound,
+		ErrRateLimited:  http.StatusTooManyRequests,
+	}
+	statuses := []int{
+		http.StatusBadRequest,
+		http.StatusUnauthorized,
+		http.StatusForbidden,
+		http.StatusNotFound,
+		http.StatusTooManyRequests,
+		http.StatusInternalServerError,
+	}
+
+	for _, status := range statuses {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(status)
+			_, _ = w.Write([]byte(`{"error": {"code": "` + http.StatusText(status) + `", "message": "failed"}}`))
+		}))
+
+		client := NewClient(NewStaticTokenProvider("test-token"), WithBaseURL(server.URL))
+		_, err := client.GetCityState(context.Background(), &models.CityStateRequest{ZIPCode: "10001"})
+		server.Close()
+
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) {
+			t.Fatalf("Status %d: expected APIError, got %v", status, err)
+		}
+		for sentinel, sentinelStatus := range sentinels {
+			if got, want := errors.Is(err, sentinel), status == sentinelStatus; got != want {
+				t.Errorf("Status %d: errors.Is(err, %v) = %v, want %v", status, sentinel, got, want)
+			}
+		}
+		if errors.Is(err, ErrRequestTimeout) {
+			t.Errorf("Status %d: expected no match for ErrRequestTimeout", status)
+		}
+	}
+}
+
+func TestAPIError_IsKeepsErrorString(t *testing.T) {
+	err := &APIError{
+		StatusCode:   http.StatusNotFound,
+		ErrorMessage: models.ErrorMessage{Error: &models.ErrorInfo{Message: "ZIP Code not found"}},
+	}
+
+	if !errors.Is(err, ErrNotFound) {
+		t.Error("Expected errors.Is(err, ErrNotFound) to be true")
+	}
+	if got := err.Error(); got != "USPS API error (status 404): ZIP Code not found" {
+		t.Errorf("Expected unchanged error string, got %q", got)
+	}
+}
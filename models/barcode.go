@@ -0,0 +1,38 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+)
+
+// DeliveryPointBarcode returns the 12-digit delivery point barcode sequence for
+// the standardized address: the 5-digit ZIP code, the 4-digit ZIP+4 add-on,
+// the 2-digit delivery point, and a mod-10 check digit that brings the sum of
+// all digits to a multiple of ten. This is the digit string encoded by POSTNET
+// and by the routing code of an Intelligent Mail barcode.
+//
+// It returns an error if the response has no address, if the ZIP code, ZIP+4,
+// or delivery point is missing, or if any of them is not all digits.
+func (r *AddressResponse) DeliveryPointBarcode() (string, error) {
+	if r == nil || r.Address == nil {
+		return "", errors.New("delivery point barcode: response has no address")
+	}
+
+	zip := r.Address.ZIPCode
+	if !isDigits(zip, 5) {
+		return "", fmt.Errorf("delivery point barcode: ZIP code %q is not 5 digits", zip)
+	}
+	if r.Address.ZIPPlus4 == nil || !isDigits(*r.Address.ZIPPlus4, 4) {
+		return "", errors.New("delivery point barcode: ZIP+4 is missing or not 4 digits")
+	}
+	if r.AdditionalInfo == nil || !isDigits(r.AdditionalInfo.DeliveryPoint, 2) {
+		return "", errors.New("delivery point barcode: delivery point is missing or not 2 digits")
+	}
+
+	digits := zip + *r.Address.ZIPPlus4 + r.AdditionalInfo.DeliveryPoint
+	sum := 0
+	for i := 0; i < len(digits); i++ {
+		sum += int(digits[i] - '0')
+	}
+	return digits + string(rune('0'+(10-sum%10)%10)), nil
+}
@@ -0,0 +1,68 @@
+package models
+
+import "testing"
+
+func TestAddressResponse_DeliveryPointBarcode(t *testing.T) {
+	plus4 := func(s string) *string { return &s }
+
+	tests := []struct {
+		name    string
+		resp    *AddressResponse
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "check digit 4",
+			resp: &AddressResponse{
+				Address:        &DomesticAddress{ZIPCode: "12345", ZIPPlus4: plus4("6789")},
+				AdditionalInfo: &AddressAdditionalInfo{DeliveryPoint: "01"},
+			},
+			want: "123456789014",
+		},
+		{
+			name: "check digit 0",
+			resp: &AddressResponse{
+				Address:        &DomesticAddress{ZIPCode: "62704", ZIPPlus4: plus4("1234"), City: "SPRINGFIELD", State: "IL"},
+				AdditionalInfo: &AddressAdditionalInfo{DeliveryPoint: "56", DPVConfirmation: "Y"},
+			},
+			want: "627041234560",
+		},
+		{
+			name: "missing ZIP+4",
+			resp: &AddressResponse{
+				Address:        &DomesticAddress{ZIPCode: "62704"},
+				AdditionalInfo: &AddressAdditionalInfo{DeliveryPoint: "56"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "missing delivery point",
+			resp: &AddressResponse{
+				Address: &DomesticAddress{ZIPCode: "62704", ZIPPlus4: plus4("1234")},
+			},
+			wantErr: true,
+		},
+		{
+			name: "non-numeric ZIP",
+			resp: &AddressResponse{
+				Address:        &DomesticAddress{ZIPCode: "6270A", ZIPPlus4: plus4("1234")},
+				AdditionalInfo: &AddressAdditionalInfo{DeliveryPoint: "56"},
+			},
+			wantErr: true,
+		},
+		{"no address", &AddressResponse{}, "", true},
+		{"nil", nil, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.resp.DeliveryPointBarcode()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("DeliveryPointBarcode() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("DeliveryPointBarcode() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
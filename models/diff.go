@@ -0,0 +1,56 @@
+package models
+
+import "strings"
+
+// FieldDiff describes a single address field whose value differs between two
+// addresses.
+type FieldDiff struct {
+	Field  string `json:"field"` // Request field name, matching its query parameter (e.g. "streetAddress")
+	Before string `json:"before"`
+	After  string `json:"after"`
+}
+
+// Diff reports which of StreetAddress, SecondaryAddress, City, State, ZIPCode,
+// and ZIPPlus4 differ between a and other, in that order. Values are compared
+// the way USPS normalizes them, ignoring case, surrounding whitespace, and
+// repeated internal spaces; Before and After hold the original values. A nil
+// address compares as empty. It returns nil when the addresses match.
+func (a *AddressRequest) Diff(other *AddressRequest) []FieldDiff {
+	if a == nil {
+		a = &AddressRequest{}
+	}
+	if other == nil {
+		other = &AddressRequest{}
+	}
+
+	fields := []struct {
+		name          string
+		before, after string
+	}{
+		{"streetAddress", a.StreetAddress, other.StreetAddress},
+		{"secondaryAddress", a.SecondaryAddress, other.SecondaryAddress},
+		{"city", a.City, other.City},
+		{"state", a.State, other.State},
+		{"ZIPCode", a.ZIPCode, other.ZIPCode},
+		{"ZIPPlus4", a.ZIPPlus4, other.ZIPPlus4},
+	}
+
+	var diffs []FieldDiff
+	for _, f := range fields {
+		if normalizeField(f.before) != normalizeField(f.after) {
+			diffs = append(diffs, FieldDiff{Field: f.name, Before: f.before, After: f.after})
+		}
+	}
+	return diffs
+}
+
+// DiffStandardized compares the request with the standardized address
+// returned by GetAddress, as in a.Diff with addr's fields.
+func (a *AddressRequest) DiffStandardized(addr *DomesticAddress) []FieldDiff {
+	return a.Diff(AddressRequestFromDomestic(addr))
+}
+
+// normalizeField uppercases s and collapses its whitespace for comparison
+func normalizeField(s string) string {
+	return strings.ToUpper(strings.Join(strings.Fields(s), " "))
+}
@@ -0,0 +1,98 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAddressRequest_Diff(t *testing.T) {
+	input := &AddressRequest{
+		StreetAddress: "123 Main Street",
+		City:          " springfield ",
+		State:         "il",
+		ZIPCode:       "62704",
+	}
+
+	tests := []struct {
+		name  string
+		other *AddressRequest
+		want  []FieldDiff
+	}{
+		{
+			name: "identical after normalization",
+			other: &AddressRequest{
+				StreetAddress: "123  MAIN STREET",
+				City:          "SPRINGFIELD",
+				State:         "IL",
+				ZIPCode:       "62704",
+			},
+			want: nil,
+		},
+		{
+			name: "ZIP+4 added",
+			other: &AddressRequest{
+				StreetAddress: "123 MAIN STREET",
+				City:          "SPRINGFIELD",
+				State:         "IL",
+				ZIPCode:       "62704",
+				ZIPPlus4:      "1234",
+			},
+			want: []FieldDiff{{Field: "ZIPPlus4", Before: "", After: "1234"}},
+		},
+		{
+			name: "street abbreviated",
+			other: &AddressRequest{
+				StreetAddress: "123 MAIN ST",
+				City:          "SPRINGFIELD",
+				State:         "IL",
+				ZIPCode:       "62704",
+			},
+			want: []FieldDiff{{Field: "streetAddress", Before: "123 Main Street", After: "123 MAIN ST"}},
+		},
+		{
+			name:  "nil other",
+			other: nil,
+			want: []FieldDiff{
+				{Field: "streetAddress", Before: "123 Main Street"},
+				{Field: "city", Before: " springfield "},
+				{Field: "state", Before: "il"},
+				{Field: "ZIPCode", Before: "62704"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := input.Diff(tt.other)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Diff() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAddressRequest_DiffStandardized(t *testing.T) {
+	input := &AddressRequest{
+		StreetAddress:    "123 Main Street",
+		SecondaryAddress: "Apt 4b",
+		City:             "Springfield",
+		State:            "IL",
+	}
+	plus4 := "1234"
+	standardized := &DomesticAddress{
+		Address:  Address{StreetAddress: "123 MAIN ST", SecondaryAddress: "APT 4B"},
+		City:     "SPRINGFIELD",
+		State:    "IL",
+		ZIPCode:  "62704",
+		ZIPPlus4: &plus4,
+	}
+
+	want := []FieldDiff{
+		{Field: "streetAddress", Before: "123 Main Street", After: "123 MAIN ST"},
+		{Field: "ZIPCode", Before: "", After: "62704"},
+		{Field: "ZIPPlus4", Before: "", After: "1234"},
+	}
+	if got := input.DiffStandardized(standardized); !reflect.DeepEqual(got, want) {
+		t.Errorf("DiffStandardized() = %+v, want %+v", got, want)
+	}
+}
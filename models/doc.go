@@ -12,16 +12,20 @@
 //   - AddressResponse: Standardized address with additional information
 //   - CityStateResponse: City and state for a given ZIP code
 //   - ZIPCodeResponse: ZIP code and ZIP+4 for a given address
+//   - VerificationReport: Flat, JSON-serializable summary of a verification
 //
 // # OAuth 2.0 Request Types
 //   - ClientCredentials: Client credentials grant request
 //   - RefreshTokenCredentials: Refresh token grant request
 //   - AuthorizationCodeCredentials: Authorization code grant request
+//   - AuthorizationRequest: Authorization endpoint query parameters, including PKCE
+//   - DeviceCodeCredentials: Device authorization grant token request
 //   - TokenRevokeRequest: Token revocation request
 //
 // # OAuth 2.0 Response Types
 //   - ProviderAccessTokenResponse: Access token response (client credentials)
 //   - ProviderTokensResponse: Access and refresh token response
+//   - DeviceCodeResponse: Device authorization response with the user code
 //   - StandardErrorResponse: OAuth error response
 //
 // # Error Types
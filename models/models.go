@@ -1,5 +1,12 @@
 package models
 
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
 // Address represents the standard address fields common to all locations.
 type Address struct {
 	StreetAddress             string `json:"streetAddress,omitempty"`
@@ -27,6 +34,39 @@ type AddressAdditionalInfo struct {
 	Business             string `json:"business,omitempty"`
 	CentralDeliveryPoint string `json:"centralDeliveryPoint,omitempty"`
 	Vacant               string `json:"vacant,omitempty"`
+	NoStat               string `json:"noStat,omitempty"`
+	ActiveFlag           string `json:"activeFlag,omitempty"`
+}
+
+// IsCentralDeliveryPoint reports whether the address is served by a central
+// delivery point such as a cluster box unit or mailroom.
+func (i *AddressAdditionalInfo) IsCentralDeliveryPoint() bool {
+	return i != nil && i.CentralDeliveryPoint == "Y"
+}
+
+// IsNoStat reports whether USPS flags the address as "no-stat", meaning it
+// is not currently receiving delivery (e.g. under construction or idle).
+func (i *AddressAdditionalInfo) IsNoStat() bool {
+	return i != nil && i.NoStat == "Y"
+}
+
+// IsActive reports whether USPS flags the delivery point as active.
+func (i *AddressAdditionalInfo) IsActive() bool {
+	return i != nil && i.ActiveFlag == "Y"
+}
+
+// IsDeliverable reports whether Delivery Point Validation confirmed the
+// address, fully (Y) or at the building level with a missing or unconfirmed
+// secondary (D or S). It returns false for N or when DPV was not returned.
+func (i *AddressAdditionalInfo) IsDeliverable() bool {
+	if i == nil {
+		return false
+	}
+	switch i.DPVConfirmation {
+	case "Y", "D", "S":
+		return true
+	}
+	return false
 }
 
 // AddressCorrection represents a code indicating how to improve the address input.
@@ -51,6 +91,101 @@ type AddressResponse struct {
 	Warnings       []string               `json:"warnings,omitempty"`
 }
 
+// ToAddressRequest builds a request from the standardized address and firm,
+// as in AddressRequestFromDomestic. It returns nil if the response has no
+// address.
+func (r *AddressResponse) ToAddressRequest() *AddressRequest {
+	if r == nil || r.Address == nil {
+		return nil
+	}
+	req := AddressRequestFromDomestic(r.Address)
+	req.Firm = r.Firm
+	return req
+}
+
+// IsDeliverable reports whether USPS confirmed the standardized address as
+// deliverable. See AddressAdditionalInfo.IsDeliverable. A response for a
+// non-deliverable address still carries the standardized components.
+func (r *AddressResponse) IsDeliverable() bool {
+	return r != nil && r.AdditionalInfo.IsDeliverable()
+}
+
+// HasZIPPlus4 reports whether the standardized address includes a 4-digit
+// ZIP+4 add-on. A response with only a 5-digit ZIP Code is standardized but
+// coarser, so callers that require ZIP+4 can flag it for follow-up.
+func (r *AddressResponse) HasZIPPlus4() bool {
+	if r == nil || r.Address == nil || r.Address.ZIPPlus4 == nil {
+		return false
+	}
+	return isDigits(*r.Address.ZIPPlus4, 4)
+}
+
+// correctionSecondaryNeeded is the USPS correction code for a "default"
+// match: the building was found but more information, such as an apartment,
+// suite, or box number, is needed to match a specific address.
+const correctionSecondaryNeeded = "32"
+
+// SecondaryRequired reports whether USPS needs a secondary address (apartment,
+// suite, unit, or box number) that was not confirmed: either none was given
+// (see SecondaryMissing) or the one given did not match (DPV S). Callers can
+// use it to prompt the user for a valid unit number.
+func (r *AddressResponse) SecondaryRequired() bool {
+	if r == nil {
+		return false
+	}
+	if r.SecondaryMissing() {
+		return true
+	}
+	return r.AdditionalInfo != nil && r.AdditionalInfo.DPVConfirmation == "S"
+}
+
+// SecondaryMissing reports whether the address requires a secondary address
+// but none was supplied, indicated by DPV confirmation D or by correction code
+// 32 (default address, more information needed).
+func (r *AddressResponse) SecondaryMissing() bool {
+	if r == nil {
+		return false
+	}
+	if r.AdditionalInfo != nil && r.AdditionalInfo.DPVConfirmation == "D" {
+		return true
+	}
+	for _, c := range r.Corrections {
+		if c.Code == correctionSecondaryNeeded {
+			return true
+		}
+	}
+	return false
+}
+
+// UnmarshalJSON decodes an address response from either a single object or an
+// array of objects, as returned by some gateways. For an array the first
+// address is used; if the array holds more than one, a warning noting how many
+// were discarded is appended to Warnings.
+func (r *AddressResponse) UnmarshalJSON(data []byte) error {
+	// addressResponse has the same fields without the UnmarshalJSON method
+	type addressResponse AddressResponse
+
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) == 0 || trimmed[0] != '[' {
+		return json.Unmarshal(data, (*addressResponse)(r))
+	}
+
+	var list []addressResponse
+	if err := json.Unmarshal(trimmed, &list); err != nil {
+		return err
+	}
+	if len(list) == 0 {
+		return errors.New("address response array is empty")
+	}
+
+	*r = AddressResponse(list[0])
+	if len(list) > 1 {
+		r.Warnings = append(r.Warnings, fmt.Sprintf(
+			"response contained %d addresses; only the first was used", len(list)))
+	}
+	return nil
+}
+
 // CityStateResponse represents the response from the city-state lookup endpoint.
 type CityStateResponse struct {
 	City    string `json:"city,omitempty"`
@@ -0,0 +1,242 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestAddressAdditionalInfo_Flags(t *testing.T) {
+	body := `{
+		"firm": "",
+		"address": {
+			"streetAddress": "123 MAIN ST",
+			"city": "SPRINGFIELD",
+			"state": "IL",
+			"ZIPCode": "62704"
+		},
+		"additionalInfo": {
+			"deliveryPoint": "23",
+			"carrierRoute": "C001",
+			"DPVConfirmation": "Y",
+			"DPVCMRA": "N",
+			"business": "N",
+			"centralDeliveryPoint": "Y",
+			"vacant": "N",
+			"noStat": "Y",
+			"activeFlag": "N"
+		}
+	}`
+
+	var resp AddressResponse
+	if err := json.Unmarshal([]byte(body), &resp); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+
+	info := resp.AdditionalInfo
+	if info == nil {
+		t.Fatal("Expected AdditionalInfo to be decoded")
+	}
+	if info.NoStat != "Y" {
+		t.Errorf("NoStat = %q, want %q", info.NoStat, "Y")
+	}
+	if info.ActiveFlag != "N" {
+		t.Errorf("ActiveFlag = %q, want %q", info.ActiveFlag, "N")
+	}
+	if !info.IsCentralDeliveryPoint() {
+		t.Error("Expected IsCentralDeliveryPoint() to be true")
+	}
+	if !info.IsNoStat() {
+		t.Error("Expected IsNoStat() to be true")
+	}
+	if info.IsActive() {
+		t.Error("Expected IsActive() to be false")
+	}
+}
+
+func TestAddressAdditionalInfo_FlagsNil(t *testing.T) {
+	var info *AddressAdditionalInfo
+
+	if info.IsCentralDeliveryPoint() {
+		t.Error("Expected IsCentralDeliveryPoint() to be false for nil info")
+	}
+	if info.IsNoStat() {
+		t.Error("Expected IsNoStat() to be false for nil info")
+	}
+	if info.IsActive() {
+		t.Error("Expected IsActive() to be false for nil info")
+	}
+}
+
+func TestAddressResponse_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name         string
+		body         string
+		wantStreet   string
+		wantWarnings int
+		wantErr      bool
+	}{
+		{
+			name:       "object",
+			body:       `{"address": {"streetAddress": "123 MAIN ST"}}`,
+			wantStreet: "123 MAIN ST",
+		},
+		{
+			name:       "single element array",
+			body:       ` [{"address": {"streetAddress": "123 MAIN ST"}}]`,
+			wantStreet: "123 MAIN ST",
+		},
+		{
+			name:         "multi element array",
+			body:         `[{"address": {"streetAddress": "123 MAIN ST"}, "warnings": ["w"]}, {"address": {"streetAddress": "456 OAK AVE"}}]`,
+			wantStreet:   "123 MAIN ST",
+			wantWarnings: 2,
+		},
+		{
+			name:    "empty array",
+			body:    `[]`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var resp AddressResponse
+			err := json.Unmarshal([]byte(tt.body), &resp)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("Unmarshal() error = nil, want error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unmarshal() error = %v", err)
+			}
+			if resp.Address == nil || resp.Address.StreetAddress != tt.wantStreet {
+				t.Errorf("Address = %+v, want street %q", resp.Address, tt.wantStreet)
+			}
+			if len(resp.Warnings) != tt.wantWarnings {
+				t.Errorf("Warnings = %v, want %d entries", resp.Warnings, tt.wantWarnings)
+			}
+		})
+	}
+}
+
+func TestAddressResponse_SecondaryRequired(t *testing.T) {
+	missingCorrection := AddressCorrection{
+		Code: "32",
+		Text: "Default address: The address you entered was found but more information is needed (such as an apartment, suite, or box number) to match to a specific address.",
+	}
+
+	tests := []struct {
+		name         string
+		resp         *AddressResponse
+		wantRequired bool
+		wantMissing  bool
+	}{
+		{
+			name: "confirmed",
+			resp: &AddressResponse{
+				AdditionalInfo: &AddressAdditionalInfo{DPVConfirmation: "Y"},
+				Matches:        []AddressMatch{{Code: "31", Text: "Single Response - exact match"}},
+			},
+		},
+		{
+			name:         "DPV D secondary missing",
+			resp:         &AddressResponse{AdditionalInfo: &AddressAdditionalInfo{DPVConfirmation: "D"}},
+			wantRequired: true,
+			wantMissing:  true,
+		},
+		{
+			name:         "DPV S secondary not confirmed",
+			resp:         &AddressResponse{AdditionalInfo: &AddressAdditionalInfo{DPVConfirmation: "S"}},
+			wantRequired: true,
+		},
+		{
+			name: "correction 32 default address",
+			resp: &AddressResponse{
+				AdditionalInfo: &AddressAdditionalInfo{DPVConfirmation: "Y"},
+				Corrections:    []AddressCorrection{missingCorrection},
+			},
+			wantRequired: true,
+			wantMissing:  true,
+		},
+		{
+			name: "unrelated correction",
+			resp: &AddressResponse{
+				AdditionalInfo: &AddressAdditionalInfo{DPVConfirmation: "N"},
+				Corrections:    []AddressCorrection{{Code: "22", Text: "Multiple addresses were found"}},
+			},
+		},
+		{"no additional info", &AddressResponse{}, false, false},
+		{"nil", nil, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.resp.SecondaryRequired(); got != tt.wantRequired {
+				t.Errorf("SecondaryRequired() = %v, want %v", got, tt.wantRequired)
+			}
+			if got := tt.resp.SecondaryMissing(); got != tt.wantMissing {
+				t.Errorf("SecondaryMissing() = %v, want %v", got, tt.wantMissing)
+			}
+		})
+	}
+}
+
+func TestAddressResponse_IsDeliverable(t *testing.T) {
+	tests := []struct {
+		dpv  string
+		want bool
+	}{
+		{"Y", true},
+		{"D", true},
+		{"S", true},
+		{"N", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		resp := &AddressResponse{AdditionalInfo: &AddressAdditionalInfo{DPVConfirmation: tt.dpv}}
+		if got := resp.IsDeliverable(); got != tt.want {
+			t.Errorf("IsDeliverable() with DPV %q = %v, want %v", tt.dpv, got, tt.want)
+		}
+	}
+
+	var nilResp *AddressResponse
+	if nilResp.IsDeliverable() {
+		t.Error("IsDeliverable() on nil response = true, want false")
+	}
+	if (&AddressResponse{}).IsDeliverable() {
+		t.Error("IsDeliverable() without additional info = true, want false")
+	}
+}
+
+func TestAddressResponse_HasZIPPlus4(t *testing.T) {
+	zip4 := func(s string) *string { return &s }
+
+	tests := []struct {
+		name     string
+		zipPlus4 *string
+		want     bool
+	}{
+		{"with ZIP+4", zip4("1234"), true},
+		{"ZIP5 only", nil, false},
+		{"empty ZIP+4", zip4(""), false},
+		{"malformed ZIP+4", zip4("12A4"), false},
+	}
+
+	for _, tt := range tests {
+		resp := &AddressResponse{Address: &DomesticAddress{ZIPCode: "10001", ZIPPlus4: tt.zipPlus4}}
+		if got := resp.HasZIPPlus4(); got != tt.want {
+			t.Errorf("HasZIPPlus4() %s = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	var nilResp *AddressResponse
+	if nilResp.HasZIPPlus4() {
+		t.Error("HasZIPPlus4() on nil response = true, want false")
+	}
+	if (&AddressResponse{}).HasZIPPlus4() {
+		t.Error("HasZIPPlus4() without address = true, want false")
+	}
+}
@@ -31,6 +31,28 @@ type AuthorizationCodeCredentials struct {
 	Code         string `json:"code" url:"code"`
 	RedirectURI  string `json:"redirect_uri" url:"redirect_uri"`
 	Scope        string `json:"scope,omitempty" url:"scope,omitempty"`
+	CodeVerifier string `json:"code_verifier,omitempty" url:"code_verifier,omitempty"` // PKCE verifier, for public clients
+}
+
+// AuthorizationRequest holds the query parameters of the OAuth authorization
+// endpoint that starts the Authorization Code grant
+type AuthorizationRequest struct {
+	ResponseType        string `json:"response_type" url:"response_type"` // Defaults to "code"
+	ClientID            string `json:"client_id" url:"client_id"`
+	RedirectURI         string `json:"redirect_uri" url:"redirect_uri"`
+	Scope               string `json:"scope,omitempty" url:"scope,omitempty"`
+	State               string `json:"state,omitempty" url:"state,omitempty"`
+	CodeChallenge       string `json:"code_challenge,omitempty" url:"code_challenge,omitempty"`
+	CodeChallengeMethod string `json:"code_challenge_method,omitempty" url:"code_challenge_method,omitempty"`
+}
+
+// DeviceCodeCredentials represents the OAuth Device Authorization (RFC 8628)
+// token request, polled until the user approves the device
+type DeviceCodeCredentials struct {
+	GrantType    string `json:"grant_type" url:"grant_type"`
+	ClientID     string `json:"client_id" url:"client_id"`
+	ClientSecret string `json:"client_secret,omitempty" url:"client_secret,omitempty"`
+	DeviceCode   string `json:"device_code" url:"device_code"`
 }
 
 // TokenRevokeRequest represents the token revocation request
@@ -53,6 +75,7 @@ type ProviderAccessTokenResponse struct {
 	ExpiresIn       int    `json:"expires_in"`
 	TokenType       string `json:"token_type"`
 	Scope           string `json:"scope,omitempty"`
+	ExpiresAt       int64  `json:"expires_at,omitempty"` // Absolute expiry (Unix seconds), if provided
 	IssuedAt        int64  `json:"issued_at,omitempty"`
 	Status          string `json:"status,omitempty"`
 	Issuer          string `json:"issuer,omitempty"`
@@ -82,6 +105,7 @@ type ProviderTokensResponse struct {
 	RefreshTokenExpiresIn int    `json:"refresh_token_expires_in,omitempty"`
 	RefreshCount          int    `json:"refresh_count,omitempty"`
 	RefreshTokenStatus    string `json:"refresh_token_status,omitempty"`
+	ExpiresAt             int64  `json:"expires_at,omitempty"` // Absolute expiry (Unix seconds), if provided
 	IssuedAt              int64  `json:"issued_at,omitempty"`
 	Status                string `json:"status,omitempty"`
 	Issuer                string `json:"issuer,omitempty"`
@@ -91,6 +115,17 @@ type ProviderTokensResponse struct {
 	PublicKey             string `json:"public_key,omitempty"`
 }
 
+// DeviceCodeResponse is the OAuth Device Authorization (RFC 8628) response
+// with the code the user enters at VerificationURI
+type DeviceCodeResponse struct {
+	DeviceCode              string `json:"device_code"`
+	UserCode                string `json:"user_code"`
+	VerificationURI         string `json:"verification_uri"`
+	VerificationURIComplete string `json:"verification_uri_complete,omitempty"` // VerificationURI with UserCode included
+	ExpiresIn               int    `json:"expires_in"`                          // Seconds until DeviceCode expires
+	Interval                int    `json:"interval,omitempty"`                  // Minimum seconds between token polls
+}
+
 // StandardErrorResponse represents the OAuth standard error response
 type StandardErrorResponse struct {
 	Error            string `json:"error,omitempty"`
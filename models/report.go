@@ -0,0 +1,114 @@
+package models
+
+// matchExact is the USPS match code for a single, exact match.
+const matchExact = "31"
+
+// VerificationReport is a flat summary of one address verification, suitable
+// for storing in a data warehouse. It holds the standardized address, the
+// deliverability flags from AdditionalInfo, the match and correction codes,
+// and the fields USPS changed relative to the input.
+//
+// Every field is always present in its JSON encoding, in declaration order,
+// and slices encode as [] rather than null, so reports marshal to a stable
+// schema.
+type VerificationReport struct {
+	// Standardized address
+	Firm             string `json:"firm"`
+	StreetAddress    string `json:"streetAddress"`
+	SecondaryAddress string `json:"secondaryAddress"`
+	City             string `json:"city"`
+	State            string `json:"state"`
+	ZIPCode          string `json:"ZIPCode"`
+	ZIPPlus4         string `json:"ZIPPlus4"`
+
+	// Deliverability
+	Deliverable          bool   `json:"deliverable"`          // See AddressResponse.IsDeliverable
+	DPVConfirmation      string `json:"DPVConfirmation"`      // Y, D, S, N, or empty when not returned
+	HasZIPPlus4          bool   `json:"hasZIPPlus4"`          // See AddressResponse.HasZIPPlus4
+	SecondaryRequired    bool   `json:"secondaryRequired"`    // See AddressResponse.SecondaryRequired
+	SecondaryMissing     bool   `json:"secondaryMissing"`     // See AddressResponse.SecondaryMissing
+	Business             bool   `json:"business"`             // AdditionalInfo.Business is Y
+	Vacant               bool   `json:"vacant"`               // AdditionalInfo.Vacant is Y
+	CMRA                 bool   `json:"CMRA"`                 // AdditionalInfo.DPVCMRA is Y
+	CentralDeliveryPoint bool   `json:"centralDeliveryPoint"` // See AddressAdditionalInfo.IsCentralDeliveryPoint
+	NoStat               bool   `json:"noStat"`               // See AddressAdditionalInfo.IsNoStat
+	Active               bool   `json:"active"`               // See AddressAdditionalInfo.IsActive
+
+	// Match quality
+	ExactMatch  bool                `json:"exactMatch"` // USPS returned match code 31
+	Matches     []AddressMatch      `json:"matches"`
+	Corrections []AddressCorrection `json:"corrections"`
+	Warnings    []string            `json:"warnings"`
+
+	// Changes versus the input
+	Corrected bool        `json:"corrected"` // USPS changed at least one field
+	Changes   []FieldDiff `json:"changes"`   // See AddressRequest.DiffStandardized
+}
+
+// BuildVerificationReport assembles a VerificationReport from the request
+// sent to GetAddress and its response. A nil response, as after an error,
+// yields a report with an empty address, all flags false, and no changes. A
+// nil input yields no changes and Corrected false, since there is nothing to
+// compare the standardized address with.
+//
+// Example:
+//
+//	resp, err := client.GetAddress(ctx, req)
+//	if err != nil {
+//	    return err
+//	}
+//	report := models.BuildVerificationReport(req, resp)
+//	row, err := json.Marshal(report)
+func BuildVerificationReport(input *AddressRequest, resp *AddressResponse) VerificationReport {
+	report := VerificationReport{
+		Matches:     []AddressMatch{},
+		Corrections: []AddressCorrection{},
+		Warnings:    []string{},
+		Changes:     []FieldDiff{},
+	}
+	if resp == nil {
+		return report
+	}
+
+	report.Firm = resp.Firm
+	if addr := resp.Address; addr != nil {
+		report.StreetAddress = addr.StreetAddress
+		report.SecondaryAddress = addr.SecondaryAddress
+		report.City = addr.City
+		report.State = addr.State
+		report.ZIPCode = addr.ZIPCode
+		if addr.ZIPPlus4 != nil {
+			report.ZIPPlus4 = *addr.ZIPPlus4
+		}
+		if input != nil {
+			report.Changes = append(report.Changes, input.DiffStandardized(addr)...)
+		}
+	}
+
+	info := resp.AdditionalInfo
+	report.Deliverable = resp.IsDeliverable()
+	report.HasZIPPlus4 = resp.HasZIPPlus4()
+	report.SecondaryRequired = resp.SecondaryRequired()
+	report.SecondaryMissing = resp.SecondaryMissing()
+	report.CentralDeliveryPoint = info.IsCentralDeliveryPoint()
+	report.NoStat = info.IsNoStat()
+	report.Active = info.IsActive()
+	if info != nil {
+		report.DPVConfirmation = info.DPVConfirmation
+		report.Business = info.Business == "Y"
+		report.Vacant = info.Vacant == "Y"
+		report.CMRA = info.DPVCMRA == "Y"
+	}
+
+	report.Matches = append(report.Matches, resp.Matches...)
+	report.Corrections = append(report.Corrections, resp.Corrections...)
+	report.Warnings = append(report.Warnings, resp.Warnings...)
+	for _, m := range resp.Matches {
+		if m.Code == matchExact {
+			report.ExactMatch = true
+		}
+	}
+
+	report.Corrected = len(report.Changes) > 0
+	return report
+}
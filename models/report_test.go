@@ -0,0 +1,144 @@
+package models
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestBuildVerificationReport_CorrectedDeliverable(t *testing.T) {
+	input := &AddressRequest{
+		StreetAddress: "123 main street",
+		City:          "springfield",
+		State:         "IL",
+		ZIPCode:       "62704",
+	}
+	zipPlus4 := "1234"
+	resp := &AddressResponse{
+		Address: &DomesticAddress{
+			Address:  Address{StreetAddress: "123 MAIN ST"},
+			City:     "SPRINGFIELD",
+			State:    "IL",
+			ZIPCode:  "62704",
+			ZIPPlus4: &zipPlus4,
+		},
+		AdditionalInfo: &AddressAdditionalInfo{
+			DeliveryPoint:   "23",
+			DPVConfirmation: "Y",
+			Business:        "N",
+			Vacant:          "N",
+			ActiveFlag:      "Y",
+		},
+		Matches: []AddressMatch{{Code: "31", Text: "Single Response - exact match"}},
+	}
+
+	report := BuildVerificationReport(input, resp)
+
+	want := VerificationReport{
+		StreetAddress:   "123 MAIN ST",
+		City:            "SPRINGFIELD",
+		State:           "IL",
+		ZIPCode:         "62704",
+		ZIPPlus4:        "1234",
+		Deliverable:     true,
+		DPVConfirmation: "Y",
+		HasZIPPlus4:     true,
+		Active:          true,
+		ExactMatch:      true,
+		Matches:         []AddressMatch{{Code: "31", Text: "Single Response - exact match"}},
+		Corrections:     []AddressCorrection{},
+		Warnings:        []string{},
+		Corrected:       true,
+		Changes: []FieldDiff{
+			{Field: "streetAddress", Before: "123 main street", After: "123 MAIN ST"},
+			{Field: "ZIPPlus4", Before: "", After: "1234"},
+		},
+	}
+	if !reflect.DeepEqual(report, want) {
+		t.Errorf("BuildVerificationReport() = %+v, want %+v", report, want)
+	}
+
+	got, err := json.Marshal(report)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	wantJSON := `{"firm":"","streetAddress":"123 MAIN ST","secondaryAddress":"","city":"SPRINGFIELD","state":"IL","ZIPCode":"62704","ZIPPlus4":"1234",` +
+		`"deliverable":true,"DPVConfirmation":"Y","hasZIPPlus4":true,"secondaryRequired":false,"secondaryMissing":false,` +
+		`"business":false,"vacant":false,"CMRA":false,"centralDeliveryPoint":false,"noStat":false,"active":true,` +
+		`"exactMatch":true,"matches":[{"code":"31","text":"Single Response - exact match"}],"corrections":[],"warnings":[],` +
+		`"corrected":true,"changes":[{"field":"streetAddress","before":"123 main street","after":"123 MAIN ST"},{"field":"ZIPPlus4","before":"","after":"1234"}]}`
+	if string(got) != wantJSON {
+		t.Errorf("json.Marshal() =\n%s\nwant\n%s", got, wantJSON)
+	}
+}
+
+func TestBuildVerificationReport_SecondaryMissing(t *testing.T) {
+	input := &AddressRequest{StreetAddress: "500 MARKET ST", City: "SAN FRANCISCO", State: "CA", ZIPCode: "94105"}
+	resp := &AddressResponse{
+		Address: &DomesticAddress{
+			Address: Address{StreetAddress: "500 MARKET ST"},
+			City:    "SAN FRANCISCO",
+			State:   "CA",
+			ZIPCode: "94105",
+		},
+		AdditionalInfo: &AddressAdditionalInfo{DPVConfirmation: "D", Business: "Y", DPVCMRA: "Y"},
+		Corrections:    []AddressCorrection{{Code: "32", Text: "Default address"}},
+	}
+
+	report := BuildVerificationReport(input, resp)
+
+	if !report.Deliverable || !report.SecondaryMissing || !report.SecondaryRequired {
+		t.Errorf("Expected deliverable with missing secondary, got %+v", report)
+	}
+	if !report.Business || !report.CMRA || report.Vacant {
+		t.Errorf("Expected business CMRA flags, got business=%v CMRA=%v vacant=%v", report.Business, report.CMRA, report.Vacant)
+	}
+	if report.ExactMatch || report.HasZIPPlus4 {
+		t.Errorf("Expected no exact match or ZIP+4, got exactMatch=%v hasZIPPlus4=%v", report.ExactMatch, report.HasZIPPlus4)
+	}
+	if report.Corrected || len(report.Changes) != 0 {
+		t.Errorf("Expected no changes, got %+v", report.Changes)
+	}
+	if len(report.Corrections) != 1 || report.Corrections[0].Code != "32" {
+		t.Errorf("Expected correction 32, got %+v", report.Corrections)
+	}
+}
+
+func TestBuildVerificationReport_NilResponse(t *testing.T) {
+	report := BuildVerificationReport(&AddressRequest{StreetAddress: "123 Main St"}, nil)
+
+	got, err := json.Marshal(report)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	wantJSON := `{"firm":"","streetAddress":"","secondaryAddress":"","city":"","state":"","ZIPCode":"","ZIPPlus4":"",` +
+		`"deliverable":false,"DPVConfirmation":"","hasZIPPlus4":false,"secondaryRequired":false,"secondaryMissing":false,` +
+		`"business":false,"vacant":false,"CMRA":false,"centralDeliveryPoint":false,"noStat":false,"active":false,` +
+		`"exactMatch":false,"matches":[],"corrections":[],"warnings":[],"corrected":false,"changes":[]}`
+	if string(got) != wantJSON {
+		t.Errorf("json.Marshal() =\n%s\nwant\n%s", got, wantJSON)
+	}
+}
+
+func TestBuildVerificationReport_NilInput(t *testing.T) {
+	resp := &AddressResponse{
+		Address: &DomesticAddress{
+			Address: Address{StreetAddress: "123 MAIN ST"},
+			City:    "SPRINGFIELD",
+			State:   "IL",
+			ZIPCode: "62704",
+		},
+	}
+
+	report := BuildVerificationReport(nil, resp)
+
+	if report.StreetAddress != "123 MAIN ST" || report.City != "SPRINGFIELD" {
+		t.Errorf("address = %q, %q, want %q, %q", report.StreetAddress, report.City, "123 MAIN ST", "SPRINGFIELD")
+	}
+	if report.Corrected {
+		t.Error("Corrected = true, want false")
+	}
+	if report.Changes == nil || len(report.Changes) != 0 {
+		t.Errorf("Changes = %#v, want empty", report.Changes)
+	}
+}
@@ -4,14 +4,55 @@ import "strings"
 
 // AddressRequest represents the parameters for the address standardization endpoint.
 type AddressRequest struct {
-	Firm             string `url:"firm,omitempty"`
-	StreetAddress    string `url:"streetAddress"`
-	SecondaryAddress string `url:"secondaryAddress,omitempty"`
-	City             string `url:"city,omitempty"`
-	State            string `url:"state"`
-	Urbanization     string `url:"urbanization,omitempty"`
-	ZIPCode          string `url:"ZIPCode,omitempty"`
-	ZIPPlus4         string `url:"ZIPPlus4,omitempty"`
+	Firm             string `json:"firm,omitempty" url:"firm,omitempty"`
+	StreetAddress    string `json:"streetAddress" url:"streetAddress"`
+	SecondaryAddress string `json:"secondaryAddress,omitempty" url:"secondaryAddress,omitempty"`
+	City             string `json:"city,omitempty" url:"city,omitempty"`
+	State            string `json:"state" url:"state"`
+	Urbanization     string `json:"urbanization,omitempty" url:"urbanization,omitempty"`
+	ZIPCode          string `json:"ZIPCode,omitempty" url:"ZIPCode,omitempty"`
+	ZIPPlus4         string `json:"ZIPPlus4,omitempty" url:"ZIPPlus4,omitempty"`
+	// Country is carried through the pipeline, including its JSON encoding,
+	// but never sent to USPS. Empty or "US" addresses are domestic; the client
+	// rejects any other value because the address endpoints only support US
+	// addresses.
+	Country string `json:"country,omitempty" url:"-"`
+}
+
+// AddressRequestFromDomestic builds a request from a standardized address,
+// such as resp.Address from GetAddress, so it can be queried again. A nil
+// ZIPPlus4 leaves the request's ZIPPlus4 empty. It returns nil for a nil
+// address. DomesticAddress has no firm; use AddressResponse.ToAddressRequest
+// to keep it.
+func AddressRequestFromDomestic(addr *DomesticAddress) *AddressRequest {
+	if addr == nil {
+		return nil
+	}
+	req := &AddressRequest{
+		StreetAddress:    addr.StreetAddress,
+		SecondaryAddress: addr.SecondaryAddress,
+		City:             addr.City,
+		State:            addr.State,
+		Urbanization:     addr.Urbanization,
+		ZIPCode:          addr.ZIPCode,
+	}
+	if addr.ZIPPlus4 != nil {
+		req.ZIPPlus4 = *addr.ZIPPlus4
+	}
+	return req
+}
+
+// IsDomestic reports whether the request is for a US address, meaning Country
+// is empty or a United States code ("US" or "USA", ignoring case and spaces).
+func (a *AddressRequest) IsDomestic() bool {
+	if a == nil {
+		return true
+	}
+	switch strings.ToUpper(strings.TrimSpace(a.Country)) {
+	case "", "US", "USA":
+		return true
+	}
+	return false
 }
 
 // DeliveryLine returns the delivery line of the address (street + secondary or firm if no street).
@@ -128,16 +169,16 @@ func (a *AddressRequest) Lines() []string {
 
 // CityStateRequest represents the parameters for the city-state lookup endpoint.
 type CityStateRequest struct {
-	ZIPCode string `url:"ZIPCode"`
+	ZIPCode string `json:"ZIPCode" url:"ZIPCode"`
 }
 
 // ZIPCodeRequest represents the parameters for the ZIP code lookup endpoint.
 type ZIPCodeRequest struct {
-	Firm             string `url:"firm,omitempty"`
-	StreetAddress    string `url:"streetAddress"`
-	SecondaryAddress string `url:"secondaryAddress,omitempty"`
-	City             string `url:"city"`
-	State            string `url:"state"`
-	ZIPCode          string `url:"ZIPCode,omitempty"`
-	ZIPPlus4         string `url:"ZIPPlus4,omitempty"`
+	Firm             string `json:"firm,omitempty" url:"firm,omitempty"`
+	StreetAddress    string `json:"streetAddress" url:"streetAddress"`
+	SecondaryAddress string `json:"secondaryAddress,omitempty" url:"secondaryAddress,omitempty"`
+	City             string `json:"city" url:"city"`
+	State            string `json:"state" url:"state"`
+	ZIPCode          string `json:"ZIPCode,omitempty" url:"ZIPCode,omitempty"`
+	ZIPPlus4         string `json:"ZIPPlus4,omitempty" url:"ZIPPlus4,omitempty"`
 }
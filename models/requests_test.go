@@ -653,3 +653,108 @@ func BenchmarkAddressRequest_Lines(b *testing.B) {
 		_ = addr.Lines()
 	}
 }
+
+func TestAddressRequest_IsDomestic(t *testing.T) {
+	tests := []struct {
+		country string
+		want    bool
+	}{
+		{"", true},
+		{"US", true},
+		{" usa ", true},
+		{"CA", false},
+		{"MX", false},
+	}
+
+	for _, tt := range tests {
+		addr := &AddressRequest{Country: tt.country}
+		if got := addr.IsDomestic(); got != tt.want {
+			t.Errorf("IsDomestic() with Country %q = %v, want %v", tt.country, got, tt.want)
+		}
+	}
+
+	var nilAddr *AddressRequest
+	if !nilAddr.IsDomestic() {
+		t.Error("IsDomestic() on nil = false, want true")
+	}
+}
+
+func TestAddressRequestFromDomestic(t *testing.T) {
+	plus4 := "1234"
+
+	tests := []struct {
+		name string
+		addr *DomesticAddress
+		want *AddressRequest
+	}{
+		{
+			name: "nil address",
+			addr: nil,
+			want: nil,
+		},
+		{
+			name: "fully populated",
+			addr: &DomesticAddress{
+				Address: Address{
+					StreetAddress:             "1 CALLE LUNA",
+					StreetAddressAbbreviation: "1 CALLE LUNA",
+					SecondaryAddress:          "APT 4B",
+					CityAbbreviation:          "SAN JUAN",
+				},
+				City:         "SAN JUAN",
+				State:        "PR",
+				ZIPCode:      "00926",
+				ZIPPlus4:     &plus4,
+				Urbanization: "URB LAS GLADIOLAS",
+			},
+			want: &AddressRequest{
+				StreetAddress:    "1 CALLE LUNA",
+				SecondaryAddress: "APT 4B",
+				City:             "SAN JUAN",
+				State:            "PR",
+				Urbanization:     "URB LAS GLADIOLAS",
+				ZIPCode:          "00926",
+				ZIPPlus4:         "1234",
+			},
+		},
+		{
+			name: "nil ZIP+4",
+			addr: &DomesticAddress{
+				Address: Address{StreetAddress: "123 MAIN ST"},
+				City:    "SPRINGFIELD",
+				State:   "IL",
+				ZIPCode: "62704",
+			},
+			want: &AddressRequest{
+				StreetAddress: "123 MAIN ST",
+				City:          "SPRINGFIELD",
+				State:         "IL",
+				ZIPCode:       "62704",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := AddressRequestFromDomestic(tt.addr)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("AddressRequestFromDomestic() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAddressResponse_ToAddressRequest(t *testing.T) {
+	resp := &AddressResponse{
+		Firm:    "ACME INC",
+		Address: &DomesticAddress{Address: Address{StreetAddress: "123 MAIN ST"}, State: "IL"},
+	}
+
+	want := &AddressRequest{Firm: "ACME INC", StreetAddress: "123 MAIN ST", State: "IL"}
+	if got := resp.ToAddressRequest(); !reflect.DeepEqual(got, want) {
+		t.Errorf("ToAddressRequest() = %+v, want %+v", got, want)
+	}
+	if got := (&AddressResponse{Firm: "ACME INC"}).ToAddressRequest(); got != nil {
+		t.Errorf("ToAddressRequest() without address = %+v, want nil", got)
+	}
+}
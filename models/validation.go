@@ -0,0 +1,102 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FieldError describes a single request field that failed local validation.
+type FieldError struct {
+	Field   string // Request field name, matching its query parameter (e.g. "state")
+	Message string
+}
+
+// Error implements the error interface
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Field, e.Message)
+}
+
+// ValidationErrors is the list of field errors returned by Validate.
+type ValidationErrors []*FieldError
+
+// Error implements the error interface
+func (v ValidationErrors) Error() string {
+	msgs := make([]string, len(v))
+	for i, e := range v {
+		msgs[i] = e.Error()
+	}
+	return "invalid request: " + strings.Join(msgs, "; ")
+}
+
+// Validate checks the request for problems that USPS would reject, without
+// making an API call. It returns nil when the request is valid, or a
+// ValidationErrors listing every invalid field.
+func (a *AddressRequest) Validate() error {
+	if a == nil {
+		return ValidationErrors{{Field: "streetAddress", Message: "request is nil"}}
+	}
+
+	var errs ValidationErrors
+
+	if strings.TrimSpace(a.StreetAddress) == "" {
+		errs = append(errs, &FieldError{Field: "streetAddress", Message: "is required"})
+	}
+
+	state := strings.TrimSpace(a.State)
+	switch {
+	case state == "":
+		errs = append(errs, &FieldError{Field: "state", Message: "is required"})
+	case !isLetters(state, 2):
+		errs = append(errs, &FieldError{Field: "state", Message: "must be a 2-letter state code"})
+	}
+
+	zip := strings.TrimSpace(a.ZIPCode)
+	if zip != "" && !isDigits(zip, 5) {
+		errs = append(errs, &FieldError{Field: "ZIPCode", Message: "must be 5 digits"})
+	}
+
+	plus4 := strings.TrimSpace(a.ZIPPlus4)
+	if plus4 != "" && !isDigits(plus4, 4) {
+		errs = append(errs, &FieldError{Field: "ZIPPlus4", Message: "must be 4 digits"})
+	}
+
+	if strings.TrimSpace(a.City) == "" && zip == "" {
+		errs = append(errs, &FieldError{Field: "city", Message: "city or ZIPCode is required"})
+	}
+
+	if !a.IsDomestic() {
+		errs = append(errs, &FieldError{Field: "country", Message: "only US addresses are supported"})
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+// isDigits reports whether s consists of exactly n ASCII digits.
+func isDigits(s string, n int) bool {
+	if len(s) != n {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// isLetters reports whether s consists of exactly n ASCII letters.
+func isLetters(s string, n int) bool {
+	if len(s) != n {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i] | 0x20
+		if c < 'a' || c > 'z' {
+			return false
+		}
+	}
+	return true
+}
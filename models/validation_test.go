@@ -0,0 +1,77 @@
+package models
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestAddressRequest_Validate(t *testing.T) {
+	tests := []struct {
+		name       string
+		addr       *AddressRequest
+		wantFields []string
+	}{
+		{
+			name: "valid with ZIP",
+			addr: &AddressRequest{StreetAddress: "123 MAIN ST", State: "IL", ZIPCode: "62704"},
+		},
+		{
+			name: "valid with city",
+			addr: &AddressRequest{StreetAddress: "123 MAIN ST", City: "SPRINGFIELD", State: "il"},
+		},
+		{
+			name:       "missing street and state",
+			addr:       &AddressRequest{ZIPCode: "62704"},
+			wantFields: []string{"streetAddress", "state"},
+		},
+		{
+			name:       "bad state and ZIP",
+			addr:       &AddressRequest{StreetAddress: "123 MAIN ST", State: "ILL", ZIPCode: "6270", ZIPPlus4: "12a4"},
+			wantFields: []string{"state", "ZIPCode", "ZIPPlus4"},
+		},
+		{
+			name:       "missing city and ZIP",
+			addr:       &AddressRequest{StreetAddress: "123 MAIN ST", State: "IL"},
+			wantFields: []string{"city"},
+		},
+		{
+			name: "US country",
+			addr: &AddressRequest{StreetAddress: "123 MAIN ST", State: "IL", ZIPCode: "62704", Country: "usa"},
+		},
+		{
+			name:       "non-US country",
+			addr:       &AddressRequest{StreetAddress: "123 MAIN ST", State: "IL", ZIPCode: "62704", Country: "CA"},
+			wantFields: []string{"country"},
+		},
+		{
+			name:       "nil request",
+			addr:       nil,
+			wantFields: []string{"streetAddress"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.addr.Validate()
+			if len(tt.wantFields) == 0 {
+				if err != nil {
+					t.Fatalf("Validate() = %v, want nil", err)
+				}
+				return
+			}
+
+			var verrs ValidationErrors
+			if !errors.As(err, &verrs) {
+				t.Fatalf("Validate() = %v, want ValidationErrors", err)
+			}
+			if len(verrs) != len(tt.wantFields) {
+				t.Fatalf("got %d field errors, want %d: %v", len(verrs), len(tt.wantFields), err)
+			}
+			for i, field := range tt.wantFields {
+				if verrs[i].Field != field {
+					t.Errorf("error %d field = %q, want %q", i, verrs[i].Field, field)
+				}
+			}
+		})
+	}
+}
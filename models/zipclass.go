@@ -0,0 +1,81 @@
+package models
+
+// ZIP code classifications returned by CityStateResponse.ZIPClassification.
+// ZIPClassStandard is never returned by the built-in tables, which cannot
+// tell a standard ZIP from an unlisted unique or PO-Box-only one; it is
+// provided for callers that classify ZIPs from their own data.
+const (
+	ZIPClassStandard  = "Standard"
+	ZIPClassPOBoxOnly = "POBoxOnly"
+	ZIPClassUnique    = "Unique"
+	ZIPClassMilitary  = "Military"
+	ZIPClassUnknown   = "Unknown"
+)
+
+// The USPS city-state endpoint does not report a ZIP classification, so the
+// tables below provide a best-effort fallback. Military ZIPs are identified
+// by their reserved 3-digit prefixes and are complete; the unique and
+// PO-Box-only tables list only well-known ZIPs and are not exhaustive.
+var (
+	// militaryZIPPrefixes are the 3-digit prefixes reserved for APO/FPO/DPO
+	// addresses: 090-098 (AE), 340 (AA), and 962-966 (AP).
+	militaryZIPPrefixes = map[string]bool{
+		"090": true, "091": true, "092": true, "093": true, "094": true,
+		"095": true, "096": true, "097": true, "098": true,
+		"340": true,
+		"962": true, "963": true, "964": true, "965": true, "966": true,
+	}
+
+	// uniqueZIPs are assigned to a single high-volume organization.
+	uniqueZIPs = map[string]bool{
+		"12345": true, // General Electric, Schenectady, NY
+		"20252": true, // Smokey Bear, Washington, DC
+		"20505": true, // Central Intelligence Agency, Washington, DC
+	}
+
+	// poBoxOnlyZIPs serve post office boxes only, with no street delivery.
+	poBoxOnlyZIPs = map[string]bool{
+		"10008": true, // New York, NY (Church Street Station)
+		"20013": true, // Washington, DC
+		"30301": true, // Atlanta, GA
+		"60690": true, // Chicago, IL
+	}
+)
+
+// ZIPClassification returns the classification of the response's ZIP code:
+// ZIPClassMilitary, ZIPClassUnique, or ZIPClassPOBoxOnly. The classification
+// is computed from built-in tables because the API does not return one, so
+// only military ZIPs and the listed unique and PO-Box-only ZIPs are known;
+// any other ZIP is reported as ZIPClassUnknown rather than assumed standard.
+// Returns an empty string if the response is nil or has no 5-digit ZIP code.
+func (r *CityStateResponse) ZIPClassification() string {
+	if r == nil || !isDigits(r.ZIPCode, 5) {
+		return ""
+	}
+
+	switch {
+	case militaryZIPPrefixes[r.ZIPCode[:3]] || r.State == "AA" || r.State == "AE" || r.State == "AP":
+		return ZIPClassMilitary
+	case uniqueZIPs[r.ZIPCode]:
+		return ZIPClassUnique
+	case poBoxOnlyZIPs[r.ZIPCode]:
+		return ZIPClassPOBoxOnly
+	default:
+		return ZIPClassUnknown
+	}
+}
+
+// IsPOBoxOnly reports whether the ZIP code only serves post office boxes.
+func (r *CityStateResponse) IsPOBoxOnly() bool {
+	return r.ZIPClassification() == ZIPClassPOBoxOnly
+}
+
+// IsUniqueZIP reports whether the ZIP code is assigned to a single organization.
+func (r *CityStateResponse) IsUniqueZIP() bool {
+	return r.ZIPClassification() == ZIPClassUnique
+}
+
+// IsMilitary reports whether the ZIP code is an APO/FPO/DPO military ZIP.
+func (r *CityStateResponse) IsMilitary() bool {
+	return r.ZIPClassification() == ZIPClassMilitary
+}
@@ -0,0 +1,52 @@
+package models
+
+import "testing"
+
+func TestCityStateResponse_ZIPClassification(t *testing.T) {
+	tests := []struct {
+		name string
+		resp *CityStateResponse
+		want string
+	}{
+		{"unlisted", &CityStateResponse{City: "SPRINGFIELD", State: "IL", ZIPCode: "62704"}, ZIPClassUnknown},
+		{"unlisted PO box only", &CityStateResponse{City: "BOSTON", State: "MA", ZIPCode: "02205"}, ZIPClassUnknown},
+		{"PO box only", &CityStateResponse{City: "NEW YORK", State: "NY", ZIPCode: "10008"}, ZIPClassPOBoxOnly},
+		{"unique", &CityStateResponse{City: "SCHENECTADY", State: "NY", ZIPCode: "12345"}, ZIPClassUnique},
+		{"military AE", &CityStateResponse{City: "APO", State: "AE", ZIPCode: "09001"}, ZIPClassMilitary},
+		{"military AA", &CityStateResponse{City: "FPO", State: "AA", ZIPCode: "34001"}, ZIPClassMilitary},
+		{"military AP", &CityStateResponse{City: "APO", State: "AP", ZIPCode: "96201"}, ZIPClassMilitary},
+		{"missing ZIP", &CityStateResponse{City: "SPRINGFIELD", State: "IL"}, ""},
+		{"nil", nil, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.resp.ZIPClassification(); got != tt.want {
+				t.Errorf("ZIPClassification() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCityStateResponse_ZIPClassAccessors(t *testing.T) {
+	poBox := &CityStateResponse{ZIPCode: "20013"}
+	if !poBox.IsPOBoxOnly() || poBox.IsUniqueZIP() || poBox.IsMilitary() {
+		t.Errorf("20013: IsPOBoxOnly=%v IsUniqueZIP=%v IsMilitary=%v, want only IsPOBoxOnly",
+			poBox.IsPOBoxOnly(), poBox.IsUniqueZIP(), poBox.IsMilitary())
+	}
+
+	unique := &CityStateResponse{ZIPCode: "20252"}
+	if !unique.IsUniqueZIP() || unique.IsPOBoxOnly() {
+		t.Errorf("20252: IsUniqueZIP=%v IsPOBoxOnly=%v, want only IsUniqueZIP", unique.IsUniqueZIP(), unique.IsPOBoxOnly())
+	}
+
+	military := &CityStateResponse{ZIPCode: "96601"}
+	if !military.IsMilitary() {
+		t.Error("96601: IsMilitary() = false, want true")
+	}
+
+	var nilResp *CityStateResponse
+	if nilResp.IsPOBoxOnly() || nilResp.IsUniqueZIP() || nilResp.IsMilitary() {
+		t.Error("nil response accessors should return false")
+	}
+}
@@ -3,6 +3,8 @@ package usps
 import (
 	"bytes"
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
@@ -10,6 +12,8 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/my-eq/go-usps/models"
 )
@@ -22,10 +26,16 @@ const (
 )
 
 // OAuthClient is the USPS OAuth API client for managing OAuth 2.0 tokens.
-// It supports Client Credentials, Refresh Token, and Authorization Code grant types.
+// It supports Client Credentials, Refresh Token, Authorization Code, and Device Code grant types.
 type OAuthClient struct {
-	baseURL    string
-	httpClient *http.Client
+	baseURL        string
+	httpClient     *http.Client
+	resultObserver func(ctx context.Context, endpoint string, result interface{}, err error)
+	logger         Logger
+	userAgent      string
+
+	// slowDownIncrement overrides deviceSlowDownIncrement when positive
+	slowDownIncrement time.Duration
 }
 
 // NewOAuthClient creates a new USPS OAuth API client configured for the production environment.
@@ -45,12 +55,16 @@ func NewOAuthClient(opts ...Option) *OAuthClient {
 	tempClient := &Client{
 		baseURL:    c.baseURL,
 		httpClient: c.httpClient,
+		userAgent:  DefaultUserAgent,
 	}
 	for _, opt := range opts {
 		opt(tempClient)
 	}
 	c.baseURL = tempClient.baseURL
 	c.httpClient = tempClient.httpClient
+	c.resultObserver = tempClient.resultObserver
+	c.logger = tempClient.logger
+	c.userAgent = tempClient.userAgent
 
 	return c
 }
@@ -67,10 +81,12 @@ func NewOAuthTestClient(opts ...Option) *OAuthClient {
 }
 
 // PostToken generates OAuth tokens based on the grant type.
-// It supports three grant types:
+// It supports four grant types:
 //   - Client Credentials: Pass *models.ClientCredentials to get an access token
 //   - Refresh Token: Pass *models.RefreshTokenCredentials to refresh an access token
 //   - Authorization Code: Pass *models.AuthorizationCodeCredentials to exchange an auth code
+//   - Device Code: Pass *models.DeviceCodeCredentials to poll once for a device grant
+//     (PollDeviceToken handles the polling loop)
 //
 // The method returns either *models.ProviderAccessTokenResponse (for client credentials)
 // or *models.ProviderTokensResponse (for grants that include a refresh token).
@@ -104,7 +120,13 @@ func NewOAuthTestClient(opts ...Option) *OAuthClient {
 //	    return err
 //	}
 //	tokensResp := result.(*models.ProviderTokensResponse)
-func (c *OAuthClient) PostToken(ctx context.Context, req interface{}) (interface{}, error) {
+//
+// For public clients using PKCE, set CodeVerifier on
+// *models.AuthorizationCodeCredentials to the verifier from GeneratePKCE; it is
+// sent as code_verifier.
+func (c *OAuthClient) PostToken(ctx context.Context, req interface{}) (out interface{}, err error) {
+	defer func() { notifyObserver(ctx, c.resultObserver, EndpointOAuthToken, out, err) }()
+
 	var contentType string
 	var body io.Reader
 
@@ -114,22 +136,42 @@ func (c *OAuthClient) PostToken(ctx context.Context, req interface{}) (interface
 		contentType = "application/x-www-form-urlencoded"
 		values := url.Values{}
 		values.Set("grant_type", r.GrantType)
-		values.Set("client_id", r.ClientID)
-		values.Set("client_secret", r.ClientSecret)
-		if r.Scope != "" {
-			values.Set("scope", r.Scope)
+		values.Set("client_id", strings.TrimSpace(r.ClientID))
+		values.Set("client_secret", strings.TrimSpace(r.ClientSecret))
+		if scope := normalizeScopes(r.Scope); scope != "" {
+			values.Set("scope", scope)
+		}
+		body = strings.NewReader(values.Encode())
+	case *models.DeviceCodeCredentials:
+		contentType = "application/x-www-form-urlencoded"
+		values := url.Values{}
+		values.Set("grant_type", r.GrantType)
+		values.Set("client_id", strings.TrimSpace(r.ClientID))
+		if secret := strings.TrimSpace(r.ClientSecret); secret != "" {
+			values.Set("client_secret", secret)
 		}
+		values.Set("device_code", r.DeviceCode)
 		body = strings.NewReader(values.Encode())
 	case *models.RefreshTokenCredentials:
 		contentType = "application/json"
-		jsonData, err := json.Marshal(r)
+		creds := *r
+		creds.ClientID = strings.TrimSpace(creds.ClientID)
+		creds.ClientSecret = strings.TrimSpace(creds.ClientSecret)
+		creds.RefreshToken = strings.TrimSpace(creds.RefreshToken)
+		creds.Scope = normalizeScopes(creds.Scope)
+		jsonData, err := json.Marshal(&creds)
 		if err != nil {
 			return nil, fmt.Errorf("failed to marshal request: %w", err)
 		}
 		body = bytes.NewReader(jsonData)
 	case *models.AuthorizationCodeCredentials:
 		contentType = "application/json"
-		jsonData, err := json.Marshal(r)
+		creds := *r
+		creds.ClientID = strings.TrimSpace(creds.ClientID)
+		creds.ClientSecret = strings.TrimSpace(creds.ClientSecret)
+		creds.Scope = normalizeScopes(creds.Scope)
+		creds.CodeVerifier = strings.TrimSpace(creds.CodeVerifier)
+		jsonData, err := json.Marshal(&creds)
 		if err != nil {
 			return nil, fmt.Errorf("failed to marshal request: %w", err)
 		}
@@ -147,12 +189,16 @@ func (c *OAuthClient) PostToken(ctx context.Context, req interface{}) (interface
 
 	httpReq.Header.Set("Content-Type", contentType)
 	httpReq.Header.Set("Accept", "application/json")
+	httpReq.Header.Set("User-Agent", c.userAgent)
 
 	// Execute request
+	log := beginRequestLog(ctx, c.logger, EndpointOAuthToken, http.MethodPost, fullURL)
 	resp, err := c.httpClient.Do(httpReq)
 	if err != nil {
+		log.end(0)
 		return nil, fmt.Errorf("failed to execute request: %w", err)
 	}
+	log.end(resp.StatusCode)
 	defer func() { _ = resp.Body.Close() }()
 
 	// Read response body
@@ -203,7 +249,9 @@ func (c *OAuthClient) PostToken(ctx context.Context, req interface{}) (interface
 //	    TokenTypeHint: "refresh_token",
 //	}
 //	err := client.PostRevoke(ctx, "client-id", "client-secret", req)
-func (c *OAuthClient) PostRevoke(ctx context.Context, clientID, clientSecret string, req *models.TokenRevokeRequest) error {
+func (c *OAuthClient) PostRevoke(ctx context.Context, clientID, clientSecret string, req *models.TokenRevokeRequest) (err error) {
+	defer func() { notifyObserver(ctx, c.resultObserver, EndpointOAuthRevoke, nil, err) }()
+
 	// Encode request body
 	values := url.Values{}
 	values.Set("token", req.Token)
@@ -219,16 +267,19 @@ func (c *OAuthClient) PostRevoke(ctx context.Context, clientID, clientSecret str
 	}
 
 	// Set Basic Authentication
-	auth := base64.StdEncoding.EncodeToString([]byte(clientID + ":" + clientSecret))
-	httpReq.Header.Set("Authorization", "Basic "+auth)
+	httpReq.Header.Set("Authorization", BasicAuthHeader(strings.TrimSpace(clientID), strings.TrimSpace(clientSecret)))
 	httpReq.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 	httpReq.Header.Set("Accept", "application/json")
+	httpReq.Header.Set("User-Agent", c.userAgent)
 
 	// Execute request
+	log := beginRequestLog(ctx, c.logger, EndpointOAuthRevoke, http.MethodPost, fullURL)
 	resp, err := c.httpClient.Do(httpReq)
 	if err != nil {
+		log.end(0)
 		return fmt.Errorf("failed to execute request: %w", err)
 	}
+	log.end(resp.StatusCode)
 	defer func() { _ = resp.Body.Close() }()
 
 	// Handle error responses
@@ -252,6 +303,137 @@ func (c *OAuthClient) PostRevoke(ctx context.Context, clientID, clientSecret str
 	return nil
 }
 
+// RevokeAll revokes each refresh token in tokens, for example during a
+// credential rotation. Tokens are revoked concurrently, at most
+// DefaultBulkConfig().MaxConcurrency at a time, and the returned errors are
+// aligned with tokens: errs[i] is nil when tokens[i] was revoked. Tokens not
+// yet started when ctx is canceled get ctx.Err().
+//
+// Example:
+//
+//	errs := client.RevokeAll(ctx, "client-id", "client-secret", refreshTokens)
+//	for i, err := range errs {
+//	    if err != nil {
+//	        log.Printf("failed to revoke token %d: %v", i, err)
+//	    }
+//	}
+func (c *OAuthClient) RevokeAll(ctx context.Context, clientID, clientSecret string, tokens []string) []error {
+	errs := make([]error, len(tokens))
+
+	sem := make(chan struct{}, DefaultBulkConfig().MaxConcurrency)
+	var wg sync.WaitGroup
+
+	for i, token := range tokens {
+		wg.Add(1)
+		go func(idx int, token string) {
+			defer wg.Done()
+
+			// Acquire worker slot
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				errs[idx] = ctx.Err()
+				return
+			}
+
+			errs[idx] = c.PostRevoke(ctx, clientID, clientSecret, &models.TokenRevokeRequest{
+				Token:         token,
+				TokenTypeHint: "refresh_token",
+			})
+		}(i, token)
+	}
+
+	wg.Wait()
+	return errs
+}
+
+// AuthorizationURL returns the URL of the USPS authorization endpoint to
+// redirect the user to for the Authorization Code grant, against the client's
+// base URL. scope and state are omitted when empty. It returns an error if
+// redirectURI is not an absolute URL. Use AuthorizationRequestURL for PKCE.
+//
+// Example:
+//
+//	authURL, err := client.AuthorizationURL("your-client-id", "https://yourapp.com/callback", "addresses", state)
+func (c *OAuthClient) AuthorizationURL(clientID, redirectURI, scope, state string) (string, error) {
+	return c.AuthorizationRequestURL(&models.AuthorizationRequest{
+		ClientID:    clientID,
+		RedirectURI: redirectURI,
+		Scope:       scope,
+		State:       state,
+	})
+}
+
+// AuthorizationRequestURL is like AuthorizationURL but takes every
+// authorization parameter, including the PKCE code challenge. ResponseType
+// defaults to "code". For PKCE, set CodeChallenge and CodeChallengeMethod from
+// GeneratePKCE and keep the verifier for the PostToken exchange. A nil req
+// returns an error.
+//
+// Example:
+//
+//	verifier, challenge, method := usps.GeneratePKCE()
+//	authURL, err := client.AuthorizationRequestURL(&models.AuthorizationRequest{
+//	    ClientID:            "your-client-id",
+//	    RedirectURI:         "https://yourapp.com/callback",
+//	    Scope:               "addresses",
+//	    State:               state,
+//	    CodeChallenge:       challenge,
+//	    CodeChallengeMethod: method,
+//	})
+func (c *OAuthClient) AuthorizationRequestURL(req *models.AuthorizationRequest) (string, error) {
+	if req == nil {
+		return "", fmt.Errorf("authorization request is nil")
+	}
+	r := *req
+	if r.ResponseType == "" {
+		r.ResponseType = "code"
+	}
+	r.ClientID = strings.TrimSpace(r.ClientID)
+	r.Scope = normalizeScopes(r.Scope)
+
+	redirect, err := url.Parse(r.RedirectURI)
+	if err != nil || !redirect.IsAbs() || redirect.Host == "" {
+		return "", fmt.Errorf("invalid redirect URI %q: must be an absolute URL", r.RedirectURI)
+	}
+
+	// structToURLValues only fails for non-struct input
+	values, _ := structToURLValues(&r)
+	return c.baseURL + "/authorize?" + values.Encode(), nil
+}
+
+// PKCEMethodS256 is the PKCE code challenge method used by GeneratePKCE
+const PKCEMethodS256 = "S256"
+
+// GeneratePKCE returns a new PKCE (RFC 7636) verifier, its challenge, and the
+// challenge method. The verifier is 32 random bytes, base64url-encoded without
+// padding (43 characters), and the challenge is the base64url-encoded SHA-256
+// hash of the verifier. Send the challenge and method with the authorization
+// request and the verifier as CodeVerifier when exchanging the code.
+func GeneratePKCE() (verifier, challenge, method string) {
+	buf := make([]byte, 32)
+	// crypto/rand.Read never returns an error; it aborts the program if the
+	// system random source fails.
+	_, _ = rand.Read(buf)
+	verifier = base64.RawURLEncoding.EncodeToString(buf)
+	return verifier, pkceChallenge(verifier), PKCEMethodS256
+}
+
+// pkceChallenge returns the S256 code challenge for verifier
+func pkceChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// BasicAuthHeader returns the value of an HTTP Basic Authorization header for
+// the given client credentials, in the form "Basic <base64(id:secret)>".
+// PostRevoke uses it, and it can be reused for other USPS endpoints that
+// require Basic authentication.
+func BasicAuthHeader(clientID, clientSecret string) string {
+	return "Basic " + base64.StdEncoding.EncodeToString([]byte(clientID+":"+clientSecret))
+}
+
 // OAuthError represents an error returned by the USPS OAuth API
 type OAuthError struct {
 	StatusCode   int
@@ -269,3 +451,9 @@ func (e *OAuthError) Error() string {
 	}
 	return fmt.Sprintf("OAuth error (status %d)", e.StatusCode)
 }
+
+// normalizeScopes trims a space-separated scope list and collapses runs of
+// whitespace between scopes to a single space.
+func normalizeScopes(scopes string) string {
+	return strings.Join(strings.Fields(scopes), " ")
+}
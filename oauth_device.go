@@ -0,0 +1,180 @@
+package usps
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/my-eq/go-usps/models"
+)
+
+// DeviceCodeGrantType is the grant_type of the OAuth Device Authorization
+// grant (RFC 8628) token request
+const DeviceCodeGrantType = "urn:ietf:params:oauth:grant-type:device_code"
+
+// DefaultDevicePollInterval is the polling interval PollDeviceToken uses when
+// none is given, as specified by RFC 8628
+const DefaultDevicePollInterval = 5 * time.Second
+
+// deviceSlowDownIncrement is added to the polling interval on each slow_down
+// response, as specified by RFC 8628
+const deviceSlowDownIncrement = 5 * time.Second
+
+// RequestDeviceCode starts the OAuth Device Authorization grant (RFC 8628)
+// for clients that cannot receive a redirect, such as CLI tools. Show the
+// user the returned UserCode and VerificationURI, then call PollDeviceToken
+// with the DeviceCode.
+//
+// The request is sent to the /device_authorization endpoint under the
+// client's base URL. USPS does not currently document a device authorization
+// endpoint, so the default base URLs may reject it; use WithBaseURL to point
+// the client at an authorization server that supports the grant.
+//
+// Example:
+//
+//	device, err := client.RequestDeviceCode(ctx, "your-client-id", "addresses")
+//	if err != nil {
+//	    return err
+//	}
+//	fmt.Printf("Visit %s and enter %s\n", device.VerificationURI, device.UserCode)
+//	interval := time.Duration(device.Interval) * time.Second
+//	tokens, err := client.PollDeviceToken(ctx, "your-client-id", "", device.DeviceCode, interval)
+func (c *OAuthClient) RequestDeviceCode(ctx context.Context, clientID, scope string) (out *models.DeviceCodeResponse, err error) {
+	defer func() { notifyObserver(ctx, c.resultObserver, EndpointOAuthDeviceCode, out, err) }()
+
+	values := url.Values{}
+	values.Set("client_id", strings.TrimSpace(clientID))
+	if scope := normalizeScopes(scope); scope != "" {
+		values.Set("scope", scope)
+	}
+
+	// Create request
+	fullURL := c.baseURL + "/device_authorization"
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, fullURL, strings.NewReader(values.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	httpReq.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	httpReq.Header.Set("Accept", "application/json")
+	httpReq.Header.Set("User-Agent", c.userAgent)
+
+	// Execute request
+	log := beginRequestLog(ctx, c.logger, EndpointOAuthDeviceCode, http.MethodPost, fullURL)
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		log.end(0)
+		return nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+	log.end(resp.StatusCode)
+	defer func() { _ = resp.Body.Close() }()
+
+	// Read response body
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	// Handle error responses
+	if resp.StatusCode >= 400 {
+		var errResp models.StandardErrorResponse
+		if err := json.Unmarshal(respBody, &errResp); err != nil {
+			return nil, fmt.Errorf("OAuth error (status %d): %s", resp.StatusCode, string(respBody))
+		}
+		return nil, &OAuthError{
+			StatusCode:   resp.StatusCode,
+			ErrorMessage: errResp,
+		}
+	}
+
+	var deviceResp models.DeviceCodeResponse
+	if err := json.Unmarshal(respBody, &deviceResp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	return &deviceResp, nil
+}
+
+// PollDeviceToken polls the token endpoint with deviceCode from
+// RequestDeviceCode until the user approves or denies the device. It waits
+// interval (DefaultDevicePollInterval if zero or negative) before each poll,
+// keeps polling on authorization_pending, and adds 5 seconds to the interval
+// on slow_down. Any other error, such as access_denied or expired_token, is
+// returned as an *OAuthError. clientSecret may be empty for public clients.
+//
+// Polling stops when ctx is done; give ctx a deadline matching the device
+// code's ExpiresIn to bound the wait.
+func (c *OAuthClient) PollDeviceToken(ctx context.Context, clientID, clientSecret, deviceCode string, interval time.Duration) (*models.ProviderTokensResponse, error) {
+	if interval <= 0 {
+		interval = DefaultDevicePollInterval
+	}
+	slowDown := c.slowDownIncrement
+	if slowDown <= 0 {
+		slowDown = deviceSlowDownIncrement
+	}
+
+	req := &models.DeviceCodeCredentials{
+		GrantType:    DeviceCodeGrantType,
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		DeviceCode:   deviceCode,
+	}
+
+	for {
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+
+		result, err := c.PostToken(ctx, req)
+		if err == nil {
+			return deviceTokens(result)
+		}
+
+		var oauthErr *OAuthError
+		if !errors.As(err, &oauthErr) {
+			return nil, err
+		}
+		switch oauthErr.ErrorMessage.Error {
+		case "authorization_pending":
+		case "slow_down":
+			interval += slowDown
+		default:
+			return nil, err
+		}
+	}
+}
+
+// deviceTokens converts a PostToken result to *models.ProviderTokensResponse;
+// a device grant may or may not issue a refresh token.
+func deviceTokens(result interface{}) (*models.ProviderTokensResponse, error) {
+	switch r := result.(type) {
+	case *models.ProviderTokensResponse:
+		return r, nil
+	case *models.ProviderAccessTokenResponse:
+		return &models.ProviderTokensResponse{
+			AccessToken:     r.AccessToken,
+			ExpiresIn:       r.ExpiresIn,
+			TokenType:       r.TokenType,
+			Scope:           r.Scope,
+			ExpiresAt:       r.ExpiresAt,
+			IssuedAt:        r.IssuedAt,
+			Status:          r.Status,
+			Issuer:          r.Issuer,
+			ClientID:        r.ClientID,
+			ApplicationName: r.ApplicationName,
+			APIProducts:     r.APIProducts,
+			PublicKey:       r.PublicKey,
+		}, nil
+	}
+	return nil, fmt.Errorf("unexpected token response type: %T", result)
+}
@@ -0,0 +1,224 @@
+package usps
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/my-eq/go-usps/models"
+)
+
+func TestRequestDeviceCode(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/device_authorization" {
+			t.Errorf("Expected path '/device_authorization', got '%s'", r.URL.Path)
+		}
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("Failed to parse form: %v", err)
+		}
+		if got := r.PostForm.Get("client_id"); got != "test-client-id" {
+			t.Errorf("Expected client_id 'test-client-id', got '%s'", got)
+		}
+		if got := r.PostForm.Get("scope"); got != "addresses tracking" {
+			t.Errorf("Expected scope 'addresses tracking', got '%s'", got)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(models.DeviceCodeResponse{
+			DeviceCode:      "device-code",
+			UserCode:        "WDJB-MJHT",
+			VerificationURI: "https://example.com/device",
+			ExpiresIn:       1800,
+			Interval:        5,
+		})
+	}))
+	defer server.Close()
+
+	client := NewOAuthClient(WithBaseURL(server.URL))
+	device, err := client.RequestDeviceCode(context.Background(), " test-client-id ", "addresses  tracking")
+	if err != nil {
+		t.Fatalf("RequestDeviceCode failed: %v", err)
+	}
+
+	if device.DeviceCode != "device-code" {
+		t.Errorf("Expected DeviceCode 'device-code', got '%s'", device.DeviceCode)
+	}
+	if device.UserCode != "WDJB-MJHT" {
+		t.Errorf("Expected UserCode 'WDJB-MJHT', got '%s'", device.UserCode)
+	}
+	if device.Interval != 5 {
+		t.Errorf("Expected Interval 5, got %d", device.Interval)
+	}
+}
+
+func TestRequestDeviceCode_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(models.StandardErrorResponse{Error: "invalid_client"})
+	}))
+	defer server.Close()
+
+	client := NewOAuthClient(WithBaseURL(server.URL))
+	_, err := client.RequestDeviceCode(context.Background(), "bad-client", "")
+
+	var oauthErr *OAuthError
+	if !errors.As(err, &oauthErr) {
+		t.Fatalf("Expected *OAuthError, got %v", err)
+	}
+	if oauthErr.ErrorMessage.Error != "invalid_client" {
+		t.Errorf("Expected error 'invalid_client', got '%s'", oauthErr.ErrorMessage.Error)
+	}
+}
+
+// newDevicePollServer answers token polls with the given OAuth error codes in
+// order, then with tokens.
+func newDevicePollServer(t *testing.T, calls *int32, pending ...string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("Failed to parse form: %v", err)
+		}
+		if got := r.PostForm.Get("grant_type"); got != DeviceCodeGrantType {
+			t.Errorf("Expected grant_type '%s', got '%s'", DeviceCodeGrantType, got)
+		}
+		if got := r.PostForm.Get("device_code"); got != "device-code" {
+			t.Errorf("Expected device_code 'device-code', got '%s'", got)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		n := int(atomic.AddInt32(calls, 1))
+		if n <= len(pending) {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(models.StandardErrorResponse{Error: pending[n-1]})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(models.ProviderTokensResponse{
+			AccessToken:  "device-access-token",
+			ExpiresIn:    28800,
+			TokenType:    "Bearer",
+			RefreshToken: "device-refresh-token",
+		})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestPollDeviceToken_PendingThenSuccess(t *testing.T) {
+	var calls int32
+	server := newDevicePollServer(t, &calls, "authorization_pending", "authorization_pending")
+
+	client := NewOAuthClient(WithBaseURL(server.URL))
+	tokens, err := client.PollDeviceToken(context.Background(), "test-client-id", "", "device-code", time.Millisecond)
+	if err != nil {
+		t.Fatalf("PollDeviceToken failed: %v", err)
+	}
+
+	if tokens.AccessToken != "device-access-token" {
+		t.Errorf("Expected access token 'device-access-token', got '%s'", tokens.AccessToken)
+	}
+	if tokens.RefreshToken != "device-refresh-token" {
+		t.Errorf("Expected refresh token 'device-refresh-token', got '%s'", tokens.RefreshToken)
+	}
+	if got := atomic.LoadInt32(&calls); got != 3 {
+		t.Errorf("Expected 3 polls, got %d", got)
+	}
+}
+
+func TestPollDeviceToken_SlowDown(t *testing.T) {
+	var calls int32
+	server := newDevicePollServer(t, &calls, "slow_down", "authorization_pending")
+
+	client := NewOAuthClient(WithBaseURL(server.URL))
+	client.slowDownIncrement = 20 * time.Millisecond
+	start := time.Now()
+	if _, err := client.PollDeviceToken(context.Background(), "test-client-id", "", "device-code", time.Millisecond); err != nil {
+		t.Fatalf("PollDeviceToken failed: %v", err)
+	}
+
+	// One 1ms wait, then two waits of 1ms + 20ms after slow_down
+	if elapsed := time.Since(start); elapsed < 40*time.Millisecond {
+		t.Errorf("Expected slow_down to lengthen the interval, finished in %v", elapsed)
+	}
+	if got := atomic.LoadInt32(&calls); got != 3 {
+		t.Errorf("Expected 3 polls, got %d", got)
+	}
+}
+
+func TestPollDeviceToken_Denied(t *testing.T) {
+	var calls int32
+	server := newDevicePollServer(t, &calls, "authorization_pending", "access_denied")
+
+	client := NewOAuthClient(WithBaseURL(server.URL))
+	_, err := client.PollDeviceToken(context.Background(), "test-client-id", "", "device-code", time.Millisecond)
+
+	var oauthErr *OAuthError
+	if !errors.As(err, &oauthErr) || oauthErr.ErrorMessage.Error != "access_denied" {
+		t.Fatalf("Expected access_denied OAuthError, got %v", err)
+	}
+	if got := atomic.LoadInt32(&calls); got != 2 {
+		t.Errorf("Expected 2 polls, got %d", got)
+	}
+}
+
+func TestPollDeviceToken_ContextCanceled(t *testing.T) {
+	var calls int32
+	pending := make([]string, 1000)
+	for i := range pending {
+		pending[i] = "authorization_pending"
+	}
+	server := newDevicePollServer(t, &calls, pending...)
+
+	client := NewOAuthClient(WithBaseURL(server.URL))
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Millisecond)
+	defer cancel()
+
+	_, err := client.PollDeviceToken(ctx, "test-client-id", "", "device-code", 5*time.Millisecond)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected context.DeadlineExceeded, got %v", err)
+	}
+}
+
+func TestPostToken_DeviceCode_AccessTokenOnly(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("Failed to parse form: %v", err)
+		}
+		if got := r.PostForm.Get("client_secret"); got != "test-client-secret" {
+			t.Errorf("Expected client_secret 'test-client-secret', got '%s'", got)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(models.ProviderAccessTokenResponse{AccessToken: "device-access-token", ExpiresIn: 28800})
+	}))
+	defer server.Close()
+
+	client := NewOAuthClient(WithBaseURL(server.URL))
+	tokens, err := client.PollDeviceToken(context.Background(), "test-client-id", "test-client-secret", "device-code", time.Millisecond)
+	if err != nil {
+		t.Fatalf("PollDeviceToken failed: %v", err)
+	}
+	if tokens.AccessToken != "device-access-token" || tokens.ExpiresIn != 28800 {
+		t.Errorf("Expected access token 'device-access-token' for 28800s, got '%s' for %ds", tokens.AccessToken, tokens.ExpiresIn)
+	}
+	if tokens.RefreshToken != "" {
+		t.Errorf("Expected no refresh token, got '%s'", tokens.RefreshToken)
+	}
+}
+
+func TestDeviceTokens_UnexpectedType(t *testing.T) {
+	tokens, err := deviceTokens(&models.ErrorMessage{})
+	if err == nil {
+		t.Fatal("Expected error for unexpected response type, got nil")
+	}
+	if tokens != nil {
+		t.Errorf("Expected nil tokens, got %+v", tokens)
+	}
+	if want := "unexpected token response type: *models.ErrorMessage"; err.Error() != want {
+		t.Errorf("Expected error '%s', got '%s'", want, err.Error())
+	}
+}
@@ -2,11 +2,17 @@ package usps
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 
 	"github.com/my-eq/go-usps/models"
@@ -207,6 +213,173 @@ func TestPostToken_AuthorizationCode_Success(t *testing.T) {
 	}
 }
 
+func TestPostToken_AuthorizationCode_PKCE(t *testing.T) {
+	tests := []struct {
+		name         string
+		codeVerifier string
+		wantVerifier string
+		wantField    bool
+	}{
+		{name: "with verifier", codeVerifier: " test-verifier ", wantVerifier: "test-verifier", wantField: true},
+		{name: "without verifier", wantField: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body map[string]string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_ = json.NewDecoder(r.Body).Decode(&body)
+				w.Header().Set("Content-Type", "application/json")
+				_ = json.NewEncoder(w).Encode(models.ProviderAccessTokenResponse{AccessToken: "pkce-token"})
+			}))
+			defer server.Close()
+
+			client := NewOAuthClient(WithBaseURL(server.URL))
+			_, err := client.PostToken(context.Background(), &models.AuthorizationCodeCredentials{
+				GrantType:    "authorization_code",
+				ClientID:     "test-client-id",
+				Code:         "test-auth-code",
+				RedirectURI:  "https://example.com/callback",
+				CodeVerifier: tt.codeVerifier,
+			})
+			if err != nil {
+				t.Fatalf("PostToken failed: %v", err)
+			}
+
+			verifier, ok := body["code_verifier"]
+			if ok != tt.wantField {
+				t.Fatalf("Expected code_verifier present = %v, got body %v", tt.wantField, body)
+			}
+			if verifier != tt.wantVerifier {
+				t.Errorf("Expected code_verifier '%s', got '%s'", tt.wantVerifier, verifier)
+			}
+		})
+	}
+}
+
+func TestGeneratePKCE(t *testing.T) {
+	verifier, challenge, method := GeneratePKCE()
+
+	if method != "S256" {
+		t.Errorf("Expected method 'S256', got '%s'", method)
+	}
+	// RFC 7636 requires 43-128 characters from the unreserved set
+	if len(verifier) != 43 {
+		t.Errorf("Expected 43-character verifier, got %d: %s", len(verifier), verifier)
+	}
+	if strings.Trim(verifier, "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789-._~") != "" {
+		t.Errorf("Expected verifier of unreserved characters, got %s", verifier)
+	}
+
+	sum := sha256.Sum256([]byte(verifier))
+	if want := base64.RawURLEncoding.EncodeToString(sum[:]); challenge != want {
+		t.Errorf("Expected challenge '%s', got '%s'", want, challenge)
+	}
+
+	if next, _, _ := GeneratePKCE(); next == verifier {
+		t.Error("Expected a new verifier on each call")
+	}
+}
+
+func TestPKCEChallenge_RFC7636Example(t *testing.T) {
+	// Example from RFC 7636, Appendix B
+	got := pkceChallenge("dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk")
+	if want := "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM"; got != want {
+		t.Errorf("pkceChallenge() = %q, want %q", got, want)
+	}
+}
+
+func TestOAuthClient_AuthorizationURL(t *testing.T) {
+	client := NewOAuthClient(WithBaseURL("https://auth.example.com/oauth2/v3"))
+	got, err := client.AuthorizationURL("client-id", "https://example.com/callback?app=a b", "addresses tracking", "xyz&state=1")
+	if err != nil {
+		t.Fatalf("AuthorizationURL failed: %v", err)
+	}
+
+	u, err := url.Parse(got)
+	if err != nil {
+		t.Fatalf("Failed to parse URL %s: %v", got, err)
+	}
+	if u.Host != "auth.example.com" {
+		t.Errorf("Expected host 'auth.example.com', got '%s'", u.Host)
+	}
+	if u.Path != "/oauth2/v3/authorize" {
+		t.Errorf("Expected path '/oauth2/v3/authorize', got '%s'", u.Path)
+	}
+	if strings.Contains(u.RawQuery, " ") || strings.Contains(u.RawQuery, "&state=1") {
+		t.Errorf("Expected encoded query, got %s", u.RawQuery)
+	}
+
+	query := u.Query()
+	want := map[string]string{
+		"response_type": "code",
+		"client_id":     "client-id",
+		"redirect_uri":  "https://example.com/callback?app=a b",
+		"scope":         "addresses tracking",
+		"state":         "xyz&state=1",
+	}
+	for name, value := range want {
+		if got := query.Get(name); got != value {
+			t.Errorf("Expected %s '%s', got '%s'", name, value, got)
+		}
+	}
+	if len(query) != len(want) {
+		t.Errorf("Expected %d query parameters, got %v", len(want), query)
+	}
+}
+
+func TestOAuthClient_AuthorizationURL_InvalidRedirectURI(t *testing.T) {
+	client := NewOAuthClient()
+	for _, redirectURI := range []string{"", "/callback", "example.com/callback", "https://", "://bad"} {
+		if got, err := client.AuthorizationURL("client-id", redirectURI, "addresses", "xyz"); err == nil {
+			t.Errorf("Expected error for redirect URI %q, got %s", redirectURI, got)
+		}
+	}
+}
+
+func TestOAuthClient_AuthorizationRequestURL_PKCE(t *testing.T) {
+	client := NewOAuthClient()
+	got, err := client.AuthorizationRequestURL(&models.AuthorizationRequest{
+		ClientID:            " client-id ",
+		RedirectURI:         "https://example.com/callback",
+		Scope:               "addresses  tracking",
+		State:               "xyz",
+		CodeChallenge:       "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM",
+		CodeChallengeMethod: "S256",
+	})
+	if err != nil {
+		t.Fatalf("AuthorizationRequestURL failed: %v", err)
+	}
+
+	u, err := url.Parse(got)
+	if err != nil {
+		t.Fatalf("Failed to parse URL %s: %v", got, err)
+	}
+	if base := u.Scheme + "://" + u.Host + u.Path; base != OAuthProductionBaseURL+"/authorize" {
+		t.Errorf("Expected endpoint '%s/authorize', got '%s'", OAuthProductionBaseURL, base)
+	}
+
+	want := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {"client-id"},
+		"redirect_uri":          {"https://example.com/callback"},
+		"scope":                 {"addresses tracking"},
+		"state":                 {"xyz"},
+		"code_challenge":        {"E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM"},
+		"code_challenge_method": {"S256"},
+	}
+	if u.Query().Encode() != want.Encode() {
+		t.Errorf("Expected query %s, got %s", want.Encode(), u.Query().Encode())
+	}
+}
+
+func TestOAuthClient_AuthorizationRequestURL_Nil(t *testing.T) {
+	client := NewOAuthClient()
+	if got, err := client.AuthorizationRequestURL(nil); err == nil {
+		t.Errorf("Expected error for nil request, got %s", got)
+	}
+}
+
 func TestPostToken_Error(t *testing.T) {
 	// Mock server
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -647,3 +820,225 @@ func (r *failingOAuthReader) Read(p []byte) (n int, err error) {
 func (r *failingOAuthReader) Close() error {
 	return nil
 }
+
+func TestBasicAuthHeader(t *testing.T) {
+	tests := []struct {
+		clientID     string
+		clientSecret string
+		want         string
+	}{
+		{"client-id", "client-secret", "Basic Y2xpZW50LWlkOmNsaWVudC1zZWNyZXQ="},
+		{"Aladdin", "open sesame", "Basic QWxhZGRpbjpvcGVuIHNlc2FtZQ=="},
+		{"", "", "Basic Og=="},
+	}
+
+	for _, tt := range tests {
+		if got := BasicAuthHeader(tt.clientID, tt.clientSecret); got != tt.want {
+			t.Errorf("BasicAuthHeader(%q, %q) = %q, want %q", tt.clientID, tt.clientSecret, got, tt.want)
+		}
+	}
+}
+
+func TestOAuthClient_ResultObserver(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/token":
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"access_token": "test-access-token", "token_type": "Bearer", "expires_in": 28800}`))
+		case "/revoke":
+			w.WriteHeader(http.StatusOK)
+		}
+	}))
+	defer server.Close()
+
+	type observation struct {
+		endpoint string
+		result   interface{}
+		err      error
+	}
+	var observed []observation
+
+	client := NewOAuthClient(
+		WithBaseURL(server.URL),
+		WithResultObserver(func(endpoint string, result interface{}, err error) {
+			observed = append(observed, observation{endpoint, result, err})
+		}),
+	)
+
+	ctx := context.Background()
+	tokenResp, err := client.PostToken(ctx, &models.ClientCredentials{
+		GrantType:    "client_credentials",
+		ClientID:     "test-client-id",
+		ClientSecret: "test-client-secret",
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := client.PostRevoke(ctx, "test-client-id", "test-client-secret", &models.TokenRevokeRequest{Token: "test-refresh-token"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(observed) != 2 {
+		t.Fatalf("Expected 2 observations, got %d", len(observed))
+	}
+	if observed[0].endpoint != "oauth-token" {
+		t.Errorf("Expected endpoint 'oauth-token', got '%s'", observed[0].endpoint)
+	}
+	if observed[0].result != tokenResp {
+		t.Errorf("Expected token response %#v, got %#v", tokenResp, observed[0].result)
+	}
+	if observed[1].endpoint != "oauth-revoke" {
+		t.Errorf("Expected endpoint 'oauth-revoke', got '%s'", observed[1].endpoint)
+	}
+	if observed[1].result != nil || observed[1].err != nil {
+		t.Errorf("Expected nil result and error, got %#v and %v", observed[1].result, observed[1].err)
+	}
+}
+
+func TestPostRevoke_TrimsCredentialWhitespace(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got, want := r.Header.Get("Authorization"), BasicAuthHeader("client-id", "client-secret"); got != want {
+			t.Errorf("Expected Authorization %q, got %q", want, got)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := NewOAuthClient(WithBaseURL(server.URL))
+	err := client.PostRevoke(context.Background(), " client-id ", "client-secret\n", &models.TokenRevokeRequest{Token: "refresh-token"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+}
+
+func TestOAuthClient_WithUserAgent(t *testing.T) {
+	var userAgents []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userAgents = append(userAgents, r.Header.Get("User-Agent"))
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/token" {
+			_ = json.NewEncoder(w).Encode(models.ProviderAccessTokenResponse{AccessToken: "token", ExpiresIn: 3600})
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := NewOAuthClient(WithBaseURL(server.URL), WithUserAgent("acme-checkout/2.3"))
+
+	ctx := context.Background()
+	_, err := client.PostToken(ctx, &models.ClientCredentials{
+		GrantType:    "client_credentials",
+		ClientID:     "client-id",
+		ClientSecret: "client-secret",
+	})
+	if err != nil {
+		t.Fatalf("PostToken failed: %v", err)
+	}
+	if err := client.PostRevoke(ctx, "client-id", "client-secret", &models.TokenRevokeRequest{Token: "token"}); err != nil {
+		t.Fatalf("PostRevoke failed: %v", err)
+	}
+
+	if len(userAgents) != 2 {
+		t.Fatalf("Expected 2 requests, got %d", len(userAgents))
+	}
+	for i, ua := range userAgents {
+		if ua != "acme-checkout/2.3" {
+			t.Errorf("Request %d: expected User-Agent 'acme-checkout/2.3', got '%s'", i, ua)
+		}
+	}
+}
+
+func TestOAuthClient_DefaultUserAgent(t *testing.T) {
+	var userAgent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userAgent = r.Header.Get("User-Agent")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := NewOAuthClient(WithBaseURL(server.URL))
+
+	if err := client.PostRevoke(context.Background(), "client-id", "client-secret", &models.TokenRevokeRequest{Token: "token"}); err != nil {
+		t.Fatalf("PostRevoke failed: %v", err)
+	}
+	if userAgent != DefaultUserAgent {
+		t.Errorf("Expected User-Agent '%s', got '%s'", DefaultUserAgent, userAgent)
+	}
+}
+
+func TestOAuthClient_RevokeAll(t *testing.T) {
+	var mu sync.Mutex
+	revoked := make(map[string]bool)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		token := r.PostForm.Get("token")
+		if hint := r.PostForm.Get("token_type_hint"); hint != "refresh_token" {
+			t.Errorf("Expected token_type_hint 'refresh_token', got '%s'", hint)
+		}
+
+		mu.Lock()
+		revoked[token] = true
+		mu.Unlock()
+
+		if strings.HasPrefix(token, "bad") {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(models.StandardErrorResponse{
+				Error:            "invalid_request",
+				ErrorDescription: "Token is invalid or has expired",
+			})
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := NewOAuthClient(WithBaseURL(server.URL))
+	tokens := []string{"token-1", "bad-token-2", "token-3", "token-4", "bad-token-5"}
+
+	errs := client.RevokeAll(context.Background(), "client-id", "client-secret", tokens)
+
+	if len(errs) != len(tokens) {
+		t.Fatalf("Expected %d errors, got %d", len(tokens), len(errs))
+	}
+	for i, token := range tokens {
+		if !revoked[token] {
+			t.Errorf("Expected %s to be revoked", token)
+		}
+		wantErr := strings.HasPrefix(token, "bad")
+		if (errs[i] != nil) != wantErr {
+			t.Errorf("Token %d (%s): expected error %v, got %v", i, token, wantErr, errs[i])
+		}
+		if wantErr {
+			var oauthErr *OAuthError
+			if !errors.As(errs[i], &oauthErr) || oauthErr.StatusCode != http.StatusBadRequest {
+				t.Errorf("Token %d: expected 400 OAuthError, got %v", i, errs[i])
+			}
+		}
+	}
+}
+
+func TestOAuthClient_RevokeAll_Canceled(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := NewOAuthClient(WithBaseURL(server.URL))
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	errs := client.RevokeAll(ctx, "client-id", "client-secret", []string{"token-1", "token-2", "token-3"})
+
+	for i, err := range errs {
+		if err == nil {
+			t.Errorf("Token %d: expected an error after cancellation", i)
+		}
+	}
+	if got := atomic.LoadInt32(&calls); got != 0 {
+		t.Errorf("Expected no revoke requests after cancellation, got %d", got)
+	}
+}
@@ -3,8 +3,10 @@ package usps
 import (
 	"context"
 	"fmt"
+	"strings"
 	"sync"
 	"time"
+	"unicode"
 
 	"github.com/my-eq/go-usps/models"
 )
@@ -27,14 +29,35 @@ type OAuthTokenProvider struct {
 	clientID                  string
 	clientSecret              string
 	scopes                    string
+	scopeErr                  error
 	refreshBuffer             time.Duration
+	clockSkew                 time.Duration
 	oauthClient               *OAuthClient
 	mutex                     sync.RWMutex
 	cachedToken               string
 	tokenExpiration           time.Time
 	refreshToken              string
 	useRefreshTokens          bool
+	storeRefreshTokens        bool
 	invalidExpirationAttempts int
+	onMissingRefreshToken     func()
+	tokenRequestTimeout       time.Duration
+	tokenExpiresAt            time.Time
+	onTokenRefresh            func(expiresIn int, err error)
+	pendingRefreshEvents      []tokenRefreshEvent
+	pendingMissingRefresh     bool
+	tokenStore                TokenStore
+	tokenStoreLoaded          bool
+	pendingSave               *pendingToken
+	saveMutex                 sync.Mutex
+	savedExpiry               time.Time
+}
+
+// tokenRefreshEvent is a token request outcome waiting to be reported to the
+// WithTokenRefreshCallback function once the lock is released
+type tokenRefreshEvent struct {
+	expiresIn int
+	err       error
 }
 
 // OAuthTokenOption is a functional option for configuring OAuthTokenProvider.
@@ -42,9 +65,40 @@ type OAuthTokenOption func(*OAuthTokenProvider)
 
 // WithOAuthScopes sets the OAuth scopes for token requests.
 // Multiple scopes should be space-separated (e.g., "addresses tracking labels").
+// Leading and trailing whitespace is removed and runs of whitespace, including
+// tabs and newlines, are collapsed to a single space.
 func WithOAuthScopes(scopes string) OAuthTokenOption {
 	return func(p *OAuthTokenProvider) {
-		p.scopes = scopes
+		p.scopes = normalizeScopes(scopes)
+		p.scopeErr = nil
+	}
+}
+
+// WithOAuthScopeList sets the OAuth scopes for token requests from a list,
+// joining them with spaces for the wire format. Empty entries are skipped. A
+// scope containing whitespace is a mistake such as passing
+// "addresses tracking" as one entry; the provider then makes no token
+// requests and GetToken returns an error wrapping ErrInvalidScope.
+//
+// Example:
+//
+//	provider := usps.NewOAuthTokenProvider(clientID, clientSecret,
+//	    usps.WithOAuthScopeList("addresses", "tracking", "labels"),
+//	)
+func WithOAuthScopeList(scopes ...string) OAuthTokenOption {
+	return func(p *OAuthTokenProvider) {
+		p.scopeErr = nil
+		list := make([]string, 0, len(scopes))
+		for _, scope := range scopes {
+			if strings.TrimSpace(scope) == "" {
+				continue
+			}
+			if strings.ContainsFunc(scope, unicode.IsSpace) {
+				p.scopeErr = fmt.Errorf("%w %q: scopes must not contain whitespace", ErrInvalidScope, scope)
+			}
+			list = append(list, scope)
+		}
+		p.scopes = strings.Join(list, " ")
 	}
 }
 
@@ -57,6 +111,20 @@ func WithTokenRefreshBuffer(duration time.Duration) OAuthTokenOption {
 	}
 }
 
+// WithClockSkewTolerance widens the refresh window by the given duration to
+// account for a local clock that runs ahead of or behind the OAuth server.
+// The tolerance is added to the refresh buffer, so with the default 5 minute
+// buffer and a 2 minute tolerance the token is refreshed 7 minutes before it
+// expires. Default is 0 (no skew tolerance).
+func WithClockSkewTolerance(skew time.Duration) OAuthTokenOption {
+	return func(p *OAuthTokenProvider) {
+		if skew < 0 {
+			skew = -skew
+		}
+		p.clockSkew = skew
+	}
+}
+
 // WithOAuthEnvironment configures the OAuth environment.
 // Use "production" (default) or "testing" to set the OAuth base URL.
 func WithOAuthEnvironment(env string) OAuthTokenOption {
@@ -80,6 +148,55 @@ func WithRefreshTokens(enabled bool) OAuthTokenOption {
 	}
 }
 
+// WithRefreshTokenStorage controls whether the provider keeps refresh tokens
+// returned by the OAuth server. Passing false hardens the provider for
+// environments where long-lived credentials must not be held in memory: any
+// refresh token in a response is discarded, WithRefreshTokens has no effect,
+// and every new access token is obtained with client credentials.
+// Default is true.
+func WithRefreshTokenStorage(enabled bool) OAuthTokenOption {
+	return func(p *OAuthTokenProvider) {
+		p.storeRefreshTokens = enabled
+	}
+}
+
+// WithTokenRequestTimeout bounds each call to the OAuth token endpoint with its
+// own deadline, independent of the HTTP client timeout used for API calls.
+// Token requests run while the provider's lock is held, so a hung OAuth
+// endpoint blocks every concurrent GetToken; a short timeout lets them fail
+// fast instead. Default is 0 (only the caller's context and the OAuth client's
+// HTTP timeout apply).
+func WithTokenRequestTimeout(timeout time.Duration) OAuthTokenOption {
+	return func(p *OAuthTokenProvider) {
+		p.tokenRequestTimeout = timeout
+	}
+}
+
+// WithMissingRefreshTokenHandler sets a function called when refresh tokens are
+// enabled (see WithRefreshTokens) but the server issues an access token without
+// a refresh token. The provider keeps working by falling back to client
+// credentials when the access token expires; the handler lets callers log or
+// alert on the misconfiguration. It runs after the provider's lock is
+// released, on the goroutine that called GetToken, so it may call GetToken.
+func WithMissingRefreshTokenHandler(handler func()) OAuthTokenOption {
+	return func(p *OAuthTokenProvider) {
+		p.onMissingRefreshToken = handler
+	}
+}
+
+// WithTokenRefreshCallback sets a function called after every attempt to
+// acquire or refresh a token, with the token lifetime in seconds reported by
+// the server on success, or 0 and the error on failure. Monitoring can use it
+// to alert when refreshes fail or stop happening. A failed refresh that falls
+// back to client credentials reports both attempts. The callback runs after
+// the provider's lock is released, on the goroutine that called GetToken, so
+// it may call TokenExpiresAt or GetToken.
+func WithTokenRefreshCallback(callback func(expiresIn int, err error)) OAuthTokenOption {
+	return func(p *OAuthTokenProvider) {
+		p.onTokenRefresh = callback
+	}
+}
+
 // NewOAuthTokenProvider creates a new OAuthTokenProvider that automatically manages
 // OAuth 2.0 tokens using the client credentials flow.
 //
@@ -92,6 +209,9 @@ func WithRefreshTokens(enabled bool) OAuthTokenOption {
 // Access tokens from USPS are valid for 8 hours. The provider will automatically
 // refresh the token 5 minutes before expiration (configurable via WithTokenRefreshBuffer).
 //
+// Leading and trailing whitespace, often picked up from copy-paste or
+// environment variables, is trimmed from clientID and clientSecret.
+//
 // Example:
 //
 //	provider := usps.NewOAuthTokenProvider("client-id", "client-secret")
@@ -108,10 +228,11 @@ func WithRefreshTokens(enabled bool) OAuthTokenOption {
 //	)
 func NewOAuthTokenProvider(clientID, clientSecret string, opts ...OAuthTokenOption) *OAuthTokenProvider {
 	p := &OAuthTokenProvider{
-		clientID:      clientID,
-		clientSecret:  clientSecret,
-		refreshBuffer: DefaultTokenRefreshBuffer,
-		oauthClient:   NewOAuthClient(),
+		clientID:           strings.TrimSpace(clientID),
+		clientSecret:       strings.TrimSpace(clientSecret),
+		refreshBuffer:      DefaultTokenRefreshBuffer,
+		oauthClient:        NewOAuthClient(),
+		storeRefreshTokens: true,
 	}
 
 	for _, opt := range opts {
@@ -143,13 +264,47 @@ func (p *OAuthTokenProvider) GetToken(ctx context.Context) (string, error) {
 		p.mutex.RUnlock()
 		return token, nil
 	}
-	refreshToken := p.refreshToken
-	useRefresh := p.useRefreshTokens && refreshToken != ""
 	p.mutex.RUnlock()
 
 	// Need to acquire or refresh token
-	p.mutex.Lock()
-	defer p.mutex.Unlock()
+	var events []tokenRefreshEvent
+	var missingRefresh bool
+	var save *pendingToken
+	token, err := func() (string, error) {
+		p.mutex.Lock()
+		defer p.mutex.Unlock()
+		defer func() {
+			events = p.pendingRefreshEvents
+			p.pendingRefreshEvents = nil
+			missingRefresh = p.pendingMissingRefresh
+			p.pendingMissingRefresh = false
+			save = p.pendingSave
+			p.pendingSave = nil
+		}()
+		return p.getTokenLocked(ctx)
+	}()
+
+	if save != nil {
+		p.saveToStore(ctx, save)
+	}
+
+	// Report token requests without holding the lock
+	for _, event := range events {
+		p.onTokenRefresh(event.expiresIn, event.err)
+	}
+	if missingRefresh {
+		p.onMissingRefreshToken()
+	}
+	return token, err
+}
+
+// getTokenLocked returns the cached token, acquiring or refreshing it if it
+// has expired. Caller must hold the write lock.
+func (p *OAuthTokenProvider) getTokenLocked(ctx context.Context) (string, error) {
+	if p.scopeErr != nil {
+		return "", p.scopeErr
+	}
+	p.loadFromStoreLocked(ctx)
 
 	// Double-check after acquiring write lock (another goroutine may have refreshed)
 	if p.cachedToken != "" && time.Now().Before(p.tokenExpiration) {
@@ -157,7 +312,7 @@ func (p *OAuthTokenProvider) GetToken(ctx context.Context) (string, error) {
 	}
 
 	// Refresh token if we have one and refresh tokens are enabled
-	if useRefresh {
+	if p.keepsRefreshTokens() && p.refreshToken != "" {
 		if err := p.refreshTokenLocked(ctx); err != nil {
 			// If refresh fails, fall back to client credentials
 			if err := p.acquireTokenLocked(ctx); err != nil {
@@ -171,13 +326,47 @@ func (p *OAuthTokenProvider) GetToken(ctx context.Context) (string, error) {
 		}
 	}
 
+	p.queueSaveLocked()
 	return p.cachedToken, nil
 }
 
+// Scopes returns the OAuth scopes sent with token requests, in order, or nil
+// if none are set.
+func (p *OAuthTokenProvider) Scopes() []string {
+	if p.scopes == "" {
+		return nil
+	}
+	return strings.Fields(p.scopes)
+}
+
+// TokenExpiresAt returns when the cached access token expires, as reported by
+// the server, or the zero time if no token has been acquired. The provider
+// refreshes the token earlier, by the refresh buffer and clock skew
+// tolerance, so a value in the past means refreshes are failing.
+func (p *OAuthTokenProvider) TokenExpiresAt() time.Time {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+	return p.tokenExpiresAt
+}
+
+// Prewarm acquires a token ahead of the first request, for example during
+// service initialization, so that request does not wait for the OAuth round
+// trip. It returns an error if the token cannot be acquired. Prewarm shares
+// GetToken's cache and locking, so it is safe to call concurrently with
+// GetToken and makes no request if a valid token is already cached.
+func (p *OAuthTokenProvider) Prewarm(ctx context.Context) error {
+	_, err := p.GetToken(ctx)
+	return err
+}
+
 // calculateExpiration calculates the token expiration time with the configured refresh buffer.
+// When the server provides an absolute expiresAt (Unix seconds) it takes precedence over
+// the relative expiresIn.
 // Returns an error if the server repeatedly returns invalid expiration values (<=0).
-func (p *OAuthTokenProvider) calculateExpiration(expiresIn int) (time.Time, error) {
-	if expiresIn <= 0 {
+func (p *OAuthTokenProvider) calculateExpiration(expiresIn int, expiresAt int64) (time.Time, error) {
+	expiresInDuration := tokenLifetime(expiresIn, expiresAt)
+
+	if expiresInDuration <= 0 {
 		// Track consecutive invalid expiration responses
 		p.invalidExpirationAttempts++
 
@@ -197,24 +386,58 @@ func (p *OAuthTokenProvider) calculateExpiration(expiresIn int) (time.Time, erro
 	// Reset the counter on successful expiration
 	p.invalidExpirationAttempts = 0
 
-	expiresInDuration := time.Duration(expiresIn) * time.Second
+	return p.refreshTime(expiresInDuration), nil
+}
 
-	buffer := p.refreshBuffer
-	if buffer >= expiresInDuration {
+// refreshTime returns when a token valid for lifetime should be refreshed:
+// the refresh buffer plus clock skew tolerance before it expires.
+func (p *OAuthTokenProvider) refreshTime(lifetime time.Duration) time.Time {
+	buffer := p.refreshBuffer + p.clockSkew
+	if buffer >= lifetime {
 		// If the buffer exceeds the token lifetime, clamp it to (token lifetime minus one second).
-		if expiresInDuration > time.Second {
-			buffer = expiresInDuration - time.Second
+		if lifetime > time.Second {
+			buffer = lifetime - time.Second
 		} else {
 			buffer = 0
 		}
 	}
 
-	return time.Now().Add(expiresInDuration - buffer), nil
+	return time.Now().Add(lifetime - buffer)
+}
+
+// tokenLifetime returns how long a token remains valid, preferring the
+// absolute expiresAt (Unix seconds) over the relative expiresIn.
+func tokenLifetime(expiresIn int, expiresAt int64) time.Duration {
+	if expiresAt > 0 {
+		return time.Until(time.Unix(expiresAt, 0))
+	}
+	return time.Duration(expiresIn) * time.Second
+}
+
+// recordTokenRequestLocked records the outcome of a token request: on success
+// it updates TokenExpiresAt, and with WithTokenRefreshCallback it queues the
+// outcome to be reported once the lock is released. Caller must hold the
+// write lock.
+func (p *OAuthTokenProvider) recordTokenRequestLocked(lifetime time.Duration, err error) {
+	if err != nil {
+		lifetime = 0
+	} else {
+		p.tokenExpiresAt = time.Now().Add(lifetime)
+	}
+	if p.onTokenRefresh != nil {
+		p.pendingRefreshEvents = append(p.pendingRefreshEvents, tokenRefreshEvent{
+			expiresIn: int(lifetime / time.Second),
+			err:       err,
+		})
+	}
 }
 
 // acquireTokenLocked acquires a new token using client credentials.
 // Caller must hold the write lock.
-func (p *OAuthTokenProvider) acquireTokenLocked(ctx context.Context) error {
+func (p *OAuthTokenProvider) acquireTokenLocked(ctx context.Context) (err error) {
+	var lifetime time.Duration
+	defer func() { p.recordTokenRequestLocked(lifetime, err) }()
+
 	req := &models.ClientCredentials{
 		GrantType:    "client_credentials",
 		ClientID:     p.clientID,
@@ -222,6 +445,9 @@ func (p *OAuthTokenProvider) acquireTokenLocked(ctx context.Context) error {
 		Scope:        p.scopes,
 	}
 
+	ctx, cancel := p.tokenRequestContext(ctx)
+	defer cancel()
+
 	result, err := p.oauthClient.PostToken(ctx, req)
 	if err != nil {
 		return fmt.Errorf("failed to acquire OAuth token: %w", err)
@@ -231,23 +457,32 @@ func (p *OAuthTokenProvider) acquireTokenLocked(ctx context.Context) error {
 	switch resp := result.(type) {
 	case *models.ProviderAccessTokenResponse:
 		p.cachedToken = resp.AccessToken
-		expiration, err := p.calculateExpiration(resp.ExpiresIn)
+		lifetime = tokenLifetime(resp.ExpiresIn, resp.ExpiresAt)
+		expiration, err := p.calculateExpiration(resp.ExpiresIn, resp.ExpiresAt)
 		if err != nil {
 			return err
 		}
 		p.tokenExpiration = expiration
 		// Clear refresh token since client credentials don't return one
 		p.refreshToken = ""
+		p.notifyMissingRefreshToken()
 	case *models.ProviderTokensResponse:
 		p.cachedToken = resp.AccessToken
-		expiration, err := p.calculateExpiration(resp.ExpiresIn)
+		lifetime = tokenLifetime(resp.ExpiresIn, resp.ExpiresAt)
+		expiration, err := p.calculateExpiration(resp.ExpiresIn, resp.ExpiresAt)
 		if err != nil {
 			return err
 		}
 		p.tokenExpiration = expiration
-		// Store refresh token if refresh tokens are enabled
-		if p.useRefreshTokens {
-			p.refreshToken = resp.RefreshToken
+		// Store refresh token only if refresh tokens are enabled and may be
+		// retained; otherwise make sure none is left behind
+		if !p.keepsRefreshTokens() {
+			p.refreshToken = ""
+			break
+		}
+		p.refreshToken = resp.RefreshToken
+		if p.refreshToken == "" {
+			p.notifyMissingRefreshToken()
 		}
 	default:
 		return fmt.Errorf("unexpected token response type: %T", result)
@@ -258,11 +493,14 @@ func (p *OAuthTokenProvider) acquireTokenLocked(ctx context.Context) error {
 
 // refreshTokenLocked refreshes the token using a refresh token.
 // Caller must hold the write lock.
-func (p *OAuthTokenProvider) refreshTokenLocked(ctx context.Context) error {
+func (p *OAuthTokenProvider) refreshTokenLocked(ctx context.Context) (err error) {
 	if p.refreshToken == "" {
 		return fmt.Errorf("no refresh token available")
 	}
 
+	var lifetime time.Duration
+	defer func() { p.recordTokenRequestLocked(lifetime, err) }()
+
 	req := &models.RefreshTokenCredentials{
 		GrantType:    "refresh_token",
 		ClientID:     p.clientID,
@@ -271,25 +509,70 @@ func (p *OAuthTokenProvider) refreshTokenLocked(ctx context.Context) error {
 		Scope:        p.scopes,
 	}
 
+	ctx, cancel := p.tokenRequestContext(ctx)
+	defer cancel()
+
 	result, err := p.oauthClient.PostToken(ctx, req)
 	if err != nil {
 		return fmt.Errorf("failed to refresh OAuth token: %w", err)
 	}
 
-	// Refresh token always returns ProviderTokensResponse
+	// Refresh token normally returns ProviderTokensResponse. A response without
+	// a new refresh token is decoded as ProviderAccessTokenResponse; keep its
+	// access token and fall back to client credentials when it expires.
 	tokensResp, ok := result.(*models.ProviderTokensResponse)
 	if !ok {
-		return fmt.Errorf("unexpected token response type: %T", result)
+		accessResp, ok := result.(*models.ProviderAccessTokenResponse)
+		if !ok {
+			return fmt.Errorf("unexpected token response type: %T", result)
+		}
+		lifetime = tokenLifetime(accessResp.ExpiresIn, accessResp.ExpiresAt)
+		expiration, err := p.calculateExpiration(accessResp.ExpiresIn, accessResp.ExpiresAt)
+		if err != nil {
+			return err
+		}
+		p.cachedToken = accessResp.AccessToken
+		p.tokenExpiration = expiration
+		p.refreshToken = ""
+		p.notifyMissingRefreshToken()
+		return nil
 	}
 
 	p.cachedToken = tokensResp.AccessToken
-	expiration, err := p.calculateExpiration(tokensResp.ExpiresIn)
+	lifetime = tokenLifetime(tokensResp.ExpiresIn, tokensResp.ExpiresAt)
+	expiration, err := p.calculateExpiration(tokensResp.ExpiresIn, tokensResp.ExpiresAt)
 	if err != nil {
 		return err
 	}
 	p.tokenExpiration = expiration
 	// Update refresh token
 	p.refreshToken = tokensResp.RefreshToken
+	if !p.keepsRefreshTokens() {
+		p.refreshToken = ""
+	}
 
 	return nil
 }
+
+// tokenRequestContext applies the WithTokenRequestTimeout deadline, if any, to ctx.
+func (p *OAuthTokenProvider) tokenRequestContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if p.tokenRequestTimeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, p.tokenRequestTimeout)
+}
+
+// keepsRefreshTokens reports whether refresh tokens are both enabled and allowed
+// to be stored.
+func (p *OAuthTokenProvider) keepsRefreshTokens() bool {
+	return p.useRefreshTokens && p.storeRefreshTokens
+}
+
+// notifyMissingRefreshToken queues a call to the missing refresh token handler,
+// made once the lock is released, when refresh tokens are enabled. Caller must
+// hold the write lock.
+func (p *OAuthTokenProvider) notifyMissingRefreshToken() {
+	if p.keepsRefreshTokens() && p.onMissingRefreshToken != nil {
+		p.pendingMissingRefresh = true
+	}
+}
@@ -3,11 +3,13 @@ package usps
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -217,6 +219,178 @@ func TestOAuthTokenProvider_GetToken_Error(t *testing.T) {
 	}
 }
 
+func TestOAuthTokenProvider_Prewarm(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		resp := models.ProviderAccessTokenResponse{
+			AccessToken: "prewarmed-token",
+			ExpiresIn:   28800,
+			TokenType:   "Bearer",
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+
+	provider := NewOAuthTokenProvider("client-id", "client-secret")
+	provider.oauthClient = NewOAuthClient(WithBaseURL(server.URL))
+
+	if err := provider.Prewarm(context.Background()); err != nil {
+		t.Fatalf("Prewarm failed: %v", err)
+	}
+	if provider.cachedToken != "prewarmed-token" {
+		t.Errorf("Expected cachedToken 'prewarmed-token', got '%s'", provider.cachedToken)
+	}
+
+	// Concurrent GetToken calls are served from the prewarmed cache
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			token, err := provider.GetToken(context.Background())
+			if err != nil {
+				t.Errorf("GetToken failed: %v", err)
+				return
+			}
+			if token != "prewarmed-token" {
+				t.Errorf("Expected token 'prewarmed-token', got '%s'", token)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		t.Errorf("Expected 1 server call, got %d", got)
+	}
+}
+
+func TestOAuthTokenProvider_Prewarm_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnauthorized)
+		_ = json.NewEncoder(w).Encode(models.StandardErrorResponse{
+			Error:            "invalid_client",
+			ErrorDescription: "Client authentication failed",
+		})
+	}))
+	defer server.Close()
+
+	provider := NewOAuthTokenProvider("invalid-client", "invalid-secret")
+	provider.oauthClient = NewOAuthClient(WithBaseURL(server.URL))
+
+	if err := provider.Prewarm(context.Background()); err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+	if provider.cachedToken != "" {
+		t.Errorf("Expected no cached token, got '%s'", provider.cachedToken)
+	}
+}
+
+func TestOAuthTokenProvider_WithTokenRefreshCallback(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		resp := models.ProviderAccessTokenResponse{
+			AccessToken: "test-access-token",
+			ExpiresIn:   28800,
+			TokenType:   "Bearer",
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+
+	var provider *OAuthTokenProvider
+	var gotExpiresIn []int
+	var gotErrs []error
+	var gotExpiresAt time.Time
+	provider = NewOAuthTokenProvider("client-id", "client-secret",
+		WithTokenRefreshCallback(func(expiresIn int, err error) {
+			gotExpiresIn = append(gotExpiresIn, expiresIn)
+			gotErrs = append(gotErrs, err)
+			// The lock is released, so the provider can be queried
+			gotExpiresAt = provider.TokenExpiresAt()
+		}),
+	)
+	provider.oauthClient = NewOAuthClient(WithBaseURL(server.URL))
+
+	if !provider.TokenExpiresAt().IsZero() {
+		t.Errorf("Expected zero TokenExpiresAt before the first token, got %v", provider.TokenExpiresAt())
+	}
+
+	if _, err := provider.GetToken(context.Background()); err != nil {
+		t.Fatalf("GetToken failed: %v", err)
+	}
+	// A cached token makes no request and fires no callback
+	if _, err := provider.GetToken(context.Background()); err != nil {
+		t.Fatalf("Second GetToken failed: %v", err)
+	}
+
+	if len(gotErrs) != 1 {
+		t.Fatalf("Expected 1 callback, got %d", len(gotErrs))
+	}
+	if gotErrs[0] != nil {
+		t.Errorf("Expected nil error, got %v", gotErrs[0])
+	}
+	if gotExpiresIn[0] != 28800 {
+		t.Errorf("Expected expiresIn 28800, got %d", gotExpiresIn[0])
+	}
+
+	expected := time.Now().Add(8 * time.Hour)
+	if got := provider.TokenExpiresAt(); got.Before(expected.Add(-2*time.Second)) || got.After(expected.Add(2*time.Second)) {
+		t.Errorf("Expected TokenExpiresAt around %v, got %v", expected, got)
+	}
+	if !gotExpiresAt.Equal(provider.TokenExpiresAt()) {
+		t.Errorf("Expected callback to see TokenExpiresAt %v, got %v", provider.TokenExpiresAt(), gotExpiresAt)
+	}
+}
+
+func TestOAuthTokenProvider_WithTokenRefreshCallback_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnauthorized)
+		_ = json.NewEncoder(w).Encode(models.StandardErrorResponse{
+			Error:            "invalid_client",
+			ErrorDescription: "Client authentication failed",
+		})
+	}))
+	defer server.Close()
+
+	var calls int
+	var gotExpiresIn int
+	var gotErr error
+	provider := NewOAuthTokenProvider("invalid-client", "invalid-secret",
+		WithTokenRefreshCallback(func(expiresIn int, err error) {
+			calls++
+			gotExpiresIn = expiresIn
+			gotErr = err
+		}),
+	)
+	provider.oauthClient = NewOAuthClient(WithBaseURL(server.URL))
+
+	_, err := provider.GetToken(context.Background())
+	if err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+
+	if calls != 1 {
+		t.Fatalf("Expected 1 callback, got %d", calls)
+	}
+	if gotErr == nil || gotErr.Error() != err.Error() {
+		t.Errorf("Expected callback error %v, got %v", err, gotErr)
+	}
+	var oauthErr *OAuthError
+	if !errors.As(gotErr, &oauthErr) || oauthErr.StatusCode != http.StatusUnauthorized {
+		t.Errorf("Expected OAuthError with status 401, got %v", gotErr)
+	}
+	if gotExpiresIn != 0 {
+		t.Errorf("Expected expiresIn 0 on failure, got %d", gotExpiresIn)
+	}
+	if !provider.TokenExpiresAt().IsZero() {
+		t.Errorf("Expected zero TokenExpiresAt after failure, got %v", provider.TokenExpiresAt())
+	}
+}
+
 func TestOAuthTokenProvider_GetToken_WithScopes(t *testing.T) {
 	var receivedScope string
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -250,6 +424,137 @@ func TestOAuthTokenProvider_GetToken_WithScopes(t *testing.T) {
 	}
 }
 
+func TestOAuthTokenProvider_TrimsCredentialWhitespace(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		if r.FormValue("client_id") != "client-id" || r.FormValue("client_secret") != "client-secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"error": "invalid_client", "error_description": "bad credentials"}`))
+			return
+		}
+		if scope := r.FormValue("scope"); scope != "addresses tracking" {
+			t.Errorf("Expected scope 'addresses tracking', got '%s'", scope)
+		}
+
+		resp := models.ProviderAccessTokenResponse{
+			AccessToken: "test-access-token",
+			ExpiresIn:   28800,
+			TokenType:   "Bearer",
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+
+	provider := NewOAuthTokenProvider(
+		"  client-id\n",
+		"\tclient-secret  ",
+		WithOAuthScopes("  addresses \t\n tracking "),
+	)
+	provider.oauthClient = NewOAuthClient(WithBaseURL(server.URL))
+
+	token, err := provider.GetToken(context.Background())
+	if err != nil {
+		t.Fatalf("GetToken failed: %v", err)
+	}
+	if token != "test-access-token" {
+		t.Errorf("Expected token 'test-access-token', got '%s'", token)
+	}
+}
+
+func TestOAuthTokenProvider_WithOAuthScopeList(t *testing.T) {
+	var formScope, jsonScope string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Content-Type") == "application/json" {
+			var creds models.RefreshTokenCredentials
+			_ = json.NewDecoder(r.Body).Decode(&creds)
+			jsonScope = creds.Scope
+		} else {
+			_ = r.ParseForm()
+			formScope = r.FormValue("scope")
+		}
+
+		resp := models.ProviderTokensResponse{
+			AccessToken:  "test-access-token",
+			ExpiresIn:    28800,
+			TokenType:    "Bearer",
+			RefreshToken: "test-refresh-token",
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+
+	provider := NewOAuthTokenProvider(
+		"client-id",
+		"client-secret",
+		WithOAuthScopeList("addresses", "tracking", "", "labels"),
+		WithRefreshTokens(true),
+	)
+	provider.oauthClient = NewOAuthClient(WithBaseURL(server.URL))
+
+	if got := strings.Join(provider.Scopes(), ","); got != "addresses,tracking,labels" {
+		t.Errorf("Expected scopes [addresses tracking labels], got %v", provider.Scopes())
+	}
+
+	if _, err := provider.GetToken(context.Background()); err != nil {
+		t.Fatalf("GetToken failed: %v", err)
+	}
+	if formScope != "addresses tracking labels" {
+		t.Errorf("Expected scope 'addresses tracking labels', got '%s'", formScope)
+	}
+
+	// Force a refresh, which sends the scopes as JSON
+	provider.tokenExpiration = time.Now().Add(-time.Minute)
+	if _, err := provider.GetToken(context.Background()); err != nil {
+		t.Fatalf("GetToken failed: %v", err)
+	}
+	if jsonScope != "addresses tracking labels" {
+		t.Errorf("Expected refresh scope 'addresses tracking labels', got '%s'", jsonScope)
+	}
+}
+
+func TestOAuthTokenProvider_WithOAuthScopeList_Invalid(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+	}))
+	defer server.Close()
+
+	provider := NewOAuthTokenProvider("client-id", "client-secret",
+		WithOAuthScopeList("addresses", "tracking labels"))
+	provider.oauthClient = NewOAuthClient(WithBaseURL(server.URL))
+
+	_, err := provider.GetToken(context.Background())
+	if !errors.Is(err, ErrInvalidScope) {
+		t.Fatalf("Expected ErrInvalidScope, got %v", err)
+	}
+	if !strings.Contains(err.Error(), `"tracking labels"`) {
+		t.Errorf("Expected error to name the scope, got %v", err)
+	}
+	if calls != 0 {
+		t.Errorf("Expected no token requests, got %d", calls)
+	}
+
+	// A later scope option replaces the invalid list
+	provider = NewOAuthTokenProvider("client-id", "client-secret",
+		WithOAuthScopeList("tracking labels"), WithOAuthScopes("addresses"))
+	if provider.scopeErr != nil {
+		t.Errorf("Expected no scope error, got %v", provider.scopeErr)
+	}
+}
+
+func TestOAuthTokenProvider_Scopes(t *testing.T) {
+	if scopes := NewOAuthTokenProvider("client-id", "client-secret").Scopes(); scopes != nil {
+		t.Errorf("Expected nil scopes, got %v", scopes)
+	}
+
+	provider := NewOAuthTokenProvider("client-id", "client-secret", WithOAuthScopes(" addresses  tracking "))
+	if got := strings.Join(provider.Scopes(), ","); got != "addresses,tracking" {
+		t.Errorf("Expected scopes [addresses tracking], got %v", provider.Scopes())
+	}
+}
+
 func TestOAuthTokenProvider_ConcurrentAccess(t *testing.T) {
 	callCount := 0
 	var mu sync.Mutex
@@ -555,6 +860,55 @@ func TestOAuthTokenProvider_NoRefreshTokenWhenDisabled(t *testing.T) {
 	}
 }
 
+func TestOAuthTokenProvider_WithRefreshTokenStorageDisabled(t *testing.T) {
+	callCount := 0
+	var grantTypes []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		callCount++
+		_ = r.ParseForm()
+		grantTypes = append(grantTypes, r.PostForm.Get("grant_type"))
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(models.ProviderTokensResponse{
+			AccessToken:  fmt.Sprintf("access-token-%d", callCount),
+			RefreshToken: "refresh-token",
+			ExpiresIn:    28800,
+			TokenType:    "Bearer",
+		})
+	}))
+	defer server.Close()
+
+	missing := 0
+	provider := NewOAuthTokenProvider(
+		"client-id",
+		"client-secret",
+		WithRefreshTokens(true),
+		WithRefreshTokenStorage(false),
+		WithMissingRefreshTokenHandler(func() { missing++ }),
+	)
+	provider.oauthClient = NewOAuthClient(WithBaseURL(server.URL))
+
+	for i := 0; i < 2; i++ {
+		if _, err := provider.GetToken(context.Background()); err != nil {
+			t.Fatalf("GetToken failed: %v", err)
+		}
+		if provider.refreshToken != "" {
+			t.Errorf("Expected no refresh token when storage is disabled, got '%s'", provider.refreshToken)
+		}
+		provider.mutex.Lock()
+		provider.tokenExpiration = time.Now().Add(-1 * time.Minute)
+		provider.mutex.Unlock()
+	}
+
+	for i, grantType := range grantTypes {
+		if grantType != "client_credentials" {
+			t.Errorf("Request %d: expected grant_type 'client_credentials', got '%s'", i, grantType)
+		}
+	}
+	if missing != 0 {
+		t.Errorf("Expected missing refresh token handler not to be called, got %d calls", missing)
+	}
+}
+
 func TestOAuthTokenProvider_InvalidJSON(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// Return invalid JSON
@@ -744,3 +1098,357 @@ func TestOAuthTokenProvider_InvalidExpirationResetOnSuccess(t *testing.T) {
 		t.Errorf("Token should have valid expiration time, got %v", provider.tokenExpiration)
 	}
 }
+
+func TestOAuthTokenProvider_ExpiresAtOnly(t *testing.T) {
+	expiresAt := time.Now().Add(2 * time.Hour).Unix()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w, `{"access_token":"absolute-token","token_type":"Bearer","expires_at":%d}`, expiresAt)
+	}))
+	defer server.Close()
+
+	refreshBuffer := 10 * time.Minute
+	provider := NewOAuthTokenProvider(
+		"client-id",
+		"client-secret",
+		WithTokenRefreshBuffer(refreshBuffer),
+	)
+	provider.oauthClient = NewOAuthClient(WithBaseURL(server.URL))
+
+	token, err := provider.GetToken(context.Background())
+	if err != nil {
+		t.Fatalf("GetToken failed: %v", err)
+	}
+	if token != "absolute-token" {
+		t.Errorf("Expected token 'absolute-token', got '%s'", token)
+	}
+
+	expectedExpiration := time.Unix(expiresAt, 0).Add(-refreshBuffer)
+	if provider.tokenExpiration.Before(expectedExpiration.Add(-2*time.Second)) ||
+		provider.tokenExpiration.After(expectedExpiration.Add(2*time.Second)) {
+		t.Errorf("Token expiration not set correctly. Expected around %v, got %v",
+			expectedExpiration, provider.tokenExpiration)
+	}
+	if provider.invalidExpirationAttempts != 0 {
+		t.Errorf("Expected no invalid expiration attempts, got %d", provider.invalidExpirationAttempts)
+	}
+}
+
+func TestOAuthTokenProvider_ExpiresAtPreferredOverExpiresIn(t *testing.T) {
+	expiresAt := time.Now().Add(1 * time.Hour).Unix()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		resp := models.ProviderAccessTokenResponse{
+			AccessToken: "test-access-token",
+			ExpiresIn:   28800,
+			ExpiresAt:   expiresAt,
+			TokenType:   "Bearer",
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+
+	provider := NewOAuthTokenProvider("client-id", "client-secret")
+	provider.oauthClient = NewOAuthClient(WithBaseURL(server.URL))
+
+	if _, err := provider.GetToken(context.Background()); err != nil {
+		t.Fatalf("GetToken failed: %v", err)
+	}
+
+	expectedExpiration := time.Unix(expiresAt, 0).Add(-DefaultTokenRefreshBuffer)
+	if provider.tokenExpiration.Before(expectedExpiration.Add(-2*time.Second)) ||
+		provider.tokenExpiration.After(expectedExpiration.Add(2*time.Second)) {
+		t.Errorf("Expected expires_at to take precedence. Expected around %v, got %v",
+			expectedExpiration, provider.tokenExpiration)
+	}
+}
+
+func TestOAuthTokenProvider_ExpiresAtInPast(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		resp := models.ProviderAccessTokenResponse{
+			AccessToken: "stale-token",
+			ExpiresAt:   time.Now().Add(-time.Minute).Unix(),
+			TokenType:   "Bearer",
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+
+	provider := NewOAuthTokenProvider("client-id", "client-secret")
+	provider.oauthClient = NewOAuthClient(WithBaseURL(server.URL))
+
+	if _, err := provider.GetToken(context.Background()); err != nil {
+		t.Fatalf("GetToken failed: %v", err)
+	}
+	if provider.invalidExpirationAttempts != 1 {
+		t.Errorf("Expected expired expires_at to count as invalid, got %d attempts", provider.invalidExpirationAttempts)
+	}
+}
+
+func TestOAuthTokenProvider_WithClockSkewTolerance(t *testing.T) {
+	now := time.Now()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		resp := models.ProviderAccessTokenResponse{
+			AccessToken: "test-access-token",
+			ExpiresIn:   3600,
+			TokenType:   "Bearer",
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+
+	refreshBuffer := 5 * time.Minute
+	skew := 3 * time.Minute
+	provider := NewOAuthTokenProvider(
+		"client-id",
+		"client-secret",
+		WithTokenRefreshBuffer(refreshBuffer),
+		WithClockSkewTolerance(skew),
+	)
+	provider.oauthClient = NewOAuthClient(WithBaseURL(server.URL))
+
+	if provider.clockSkew != skew {
+		t.Errorf("Expected clockSkew %v, got %v", skew, provider.clockSkew)
+	}
+
+	if _, err := provider.GetToken(context.Background()); err != nil {
+		t.Fatalf("GetToken failed: %v", err)
+	}
+
+	// The refresh window is widened by the skew tolerance
+	expectedExpiration := now.Add(3600*time.Second - refreshBuffer - skew)
+	if provider.tokenExpiration.Before(expectedExpiration.Add(-2*time.Second)) ||
+		provider.tokenExpiration.After(expectedExpiration.Add(2*time.Second)) {
+		t.Errorf("Token expiration not adjusted for skew. Expected around %v, got %v",
+			expectedExpiration, provider.tokenExpiration)
+	}
+}
+
+func TestOAuthTokenProvider_ClockSkewTriggersEarlyRefresh(t *testing.T) {
+	var callCount int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&callCount, 1)
+		resp := models.ProviderAccessTokenResponse{
+			AccessToken: fmt.Sprintf("token-%d", n),
+			ExpiresIn:   600, // 10 minutes
+			TokenType:   "Bearer",
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+
+	provider := NewOAuthTokenProvider(
+		"client-id",
+		"client-secret",
+		WithTokenRefreshBuffer(time.Minute),
+		WithClockSkewTolerance(-2*time.Minute), // negative skew is treated as its magnitude
+	)
+	provider.oauthClient = NewOAuthClient(WithBaseURL(server.URL))
+
+	if _, err := provider.GetToken(context.Background()); err != nil {
+		t.Fatalf("GetToken failed: %v", err)
+	}
+
+	// Simulate the local clock advancing 8 minutes: the token still has 2 minutes
+	// of real lifetime, but with a 1 minute buffer and 2 minute skew tolerance it
+	// must already be treated as due for refresh.
+	provider.mutex.Lock()
+	provider.tokenExpiration = provider.tokenExpiration.Add(-8 * time.Minute)
+	provider.mutex.Unlock()
+
+	token, err := provider.GetToken(context.Background())
+	if err != nil {
+		t.Fatalf("GetToken failed: %v", err)
+	}
+	if token != "token-2" {
+		t.Errorf("Expected refreshed token 'token-2', got '%s'", token)
+	}
+	if atomic.LoadInt32(&callCount) != 2 {
+		t.Errorf("Expected 2 token requests, got %d", callCount)
+	}
+}
+
+func TestOAuthTokenProvider_EmptyRefreshToken(t *testing.T) {
+	var grantTypes []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		grantTypes = append(grantTypes, r.PostForm.Get("grant_type"))
+
+		// Tokens-style response, but the server omits the refresh token
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w, `{"access_token": "access-token-%d", "token_type": "Bearer", "expires_in": 28800, "refresh_token": ""}`, len(grantTypes))
+	}))
+	defer server.Close()
+
+	missing := 0
+	provider := NewOAuthTokenProvider(
+		"client-id",
+		"client-secret",
+		WithRefreshTokens(true),
+		WithMissingRefreshTokenHandler(func() { missing++ }),
+	)
+	provider.oauthClient = NewOAuthClient(WithBaseURL(server.URL))
+
+	token1, err := provider.GetToken(context.Background())
+	if err != nil {
+		t.Fatalf("First GetToken failed: %v", err)
+	}
+	if token1 != "access-token-1" {
+		t.Errorf("Expected token 'access-token-1', got '%s'", token1)
+	}
+	if missing != 1 {
+		t.Errorf("Expected handler to be called once, got %d", missing)
+	}
+
+	// Manually expire the token; the provider must fall back to client credentials
+	provider.mutex.Lock()
+	provider.tokenExpiration = time.Now().Add(-1 * time.Minute)
+	provider.mutex.Unlock()
+
+	token2, err := provider.GetToken(context.Background())
+	if err != nil {
+		t.Fatalf("Second GetToken failed: %v", err)
+	}
+	if token2 != "access-token-2" {
+		t.Errorf("Expected token 'access-token-2', got '%s'", token2)
+	}
+
+	want := []string{"client_credentials", "client_credentials"}
+	if len(grantTypes) != len(want) || grantTypes[0] != want[0] || grantTypes[1] != want[1] {
+		t.Errorf("Expected grant types %v, got %v", want, grantTypes)
+	}
+}
+
+func TestOAuthTokenProvider_MissingRefreshTokenHandlerUnlocked(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"access_token": "access-token", "token_type": "Bearer", "expires_in": 28800}`))
+	}))
+	defer server.Close()
+
+	var provider *OAuthTokenProvider
+	var handlerToken string
+	provider = NewOAuthTokenProvider(
+		"client-id",
+		"client-secret",
+		WithRefreshTokens(true),
+		WithMissingRefreshTokenHandler(func() {
+			// The lock is released, so the handler can use the provider
+			handlerToken, _ = provider.GetToken(context.Background())
+		}),
+	)
+	provider.oauthClient = NewOAuthClient(WithBaseURL(server.URL))
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := provider.GetToken(context.Background())
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("GetToken failed: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("GetToken deadlocked calling the missing refresh token handler")
+	}
+	if handlerToken != "access-token" {
+		t.Errorf("Expected handler to get token 'access-token', got '%s'", handlerToken)
+	}
+}
+
+func TestOAuthTokenProvider_RefreshWithoutNewRefreshToken(t *testing.T) {
+	callCount := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		callCount++
+		w.Header().Set("Content-Type", "application/json")
+		if callCount == 1 {
+			_ = json.NewEncoder(w).Encode(models.ProviderTokensResponse{
+				AccessToken:  "initial-access-token",
+				RefreshToken: "refresh-token",
+				ExpiresIn:    28800,
+				TokenType:    "Bearer",
+			})
+			return
+		}
+		// Refresh grant succeeds but does not rotate the refresh token
+		_ = json.NewEncoder(w).Encode(models.ProviderAccessTokenResponse{
+			AccessToken: "refreshed-access-token",
+			ExpiresIn:   28800,
+			TokenType:   "Bearer",
+		})
+	}))
+	defer server.Close()
+
+	missing := 0
+	provider := NewOAuthTokenProvider(
+		"client-id",
+		"client-secret",
+		WithRefreshTokens(true),
+		WithMissingRefreshTokenHandler(func() { missing++ }),
+	)
+	provider.oauthClient = NewOAuthClient(WithBaseURL(server.URL))
+
+	if _, err := provider.GetToken(context.Background()); err != nil {
+		t.Fatalf("First GetToken failed: %v", err)
+	}
+
+	provider.mutex.Lock()
+	provider.tokenExpiration = time.Now().Add(-1 * time.Minute)
+	provider.mutex.Unlock()
+
+	token, err := provider.GetToken(context.Background())
+	if err != nil {
+		t.Fatalf("Second GetToken failed: %v", err)
+	}
+	if token != "refreshed-access-token" {
+		t.Errorf("Expected token 'refreshed-access-token', got '%s'", token)
+	}
+	if callCount != 2 {
+		t.Errorf("Expected 2 server calls, got %d", callCount)
+	}
+	if missing != 1 {
+		t.Errorf("Expected handler to be called once, got %d", missing)
+	}
+	if provider.refreshToken != "" {
+		t.Errorf("Expected refresh token to be cleared, got '%s'", provider.refreshToken)
+	}
+}
+
+func TestOAuthTokenProvider_WithTokenRequestTimeout(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Hang until the client gives up or the test ends
+		select {
+		case <-r.Context().Done():
+		case <-release:
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	provider := NewOAuthTokenProvider(
+		"client-id",
+		"client-secret",
+		WithTokenRequestTimeout(50*time.Millisecond),
+	)
+	provider.oauthClient = NewOAuthClient(WithBaseURL(server.URL))
+
+	start := time.Now()
+	_, err := provider.GetToken(context.Background())
+	elapsed := time.Since(start)
+
+	if err == nil {
+		t.Fatal("Expected error from hanging OAuth server")
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected context.DeadlineExceeded, got %v", err)
+	}
+	if elapsed > time.Second {
+		t.Errorf("Expected GetToken to return within the token timeout, took %v", elapsed)
+	}
+}
@@ -0,0 +1,175 @@
+package parser
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// maxStreamLineSize is the longest line ParseStream accepts.
+const maxStreamLineSize = 1024 * 1024
+
+// ParseResult bundles a single parsed input with its diagnostics.
+type ParseResult struct {
+	Index       int // Position of the input in the original slice or stream
+	Input       string
+	Parsed      *ParsedAddress
+	Diagnostics []Diagnostic
+}
+
+// TriagedInput is a ParseResult that has been bucketed by Triage.
+type TriagedInput = ParseResult
+
+// ParseMany parses each input in order using the default Parser.
+func ParseMany(inputs []string) []ParseResult {
+	return defaultParser.ParseMany(inputs)
+}
+
+// ParseMany parses each input in order using this parser instance.
+// The returned slice is aligned with inputs.
+func (p *Parser) ParseMany(inputs []string) []ParseResult {
+	results := make([]ParseResult, len(inputs))
+	for i, input := range inputs {
+		parsed, diagnostics := p.Parse(input)
+		results[i] = ParseResult{
+			Index:       i,
+			Input:       input,
+			Parsed:      parsed,
+			Diagnostics: diagnostics,
+		}
+	}
+	return results
+}
+
+// ParseBatch parses inputs concurrently across workers goroutines using a
+// single Parser instance. See (*Parser).ParseBatch.
+func ParseBatch(inputs []string, workers int) []ParseResult {
+	return defaultParser.ParseBatch(inputs, workers)
+}
+
+// ParseBatch parses inputs concurrently across workers goroutines that share
+// this parser instance and its lexicon, which are read-only while parsing.
+// The returned slice is aligned with inputs, as with ParseMany. A workers
+// value of zero or less uses runtime.GOMAXPROCS(0).
+//
+// A CityStateLookup configured with WithStateInference may be called from
+// several goroutines at once.
+func (p *Parser) ParseBatch(inputs []string, workers int) []ParseResult {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if workers > len(inputs) {
+		workers = len(inputs)
+	}
+
+	results := make([]ParseResult, len(inputs))
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				parsed, diagnostics := p.Parse(inputs[i])
+				results[i] = ParseResult{
+					Index:       i,
+					Input:       inputs[i],
+					Parsed:      parsed,
+					Diagnostics: diagnostics,
+				}
+			}
+		}()
+	}
+
+	for i := range inputs {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	return results
+}
+
+// ParseStream parses newline-delimited addresses read from r using a single
+// Parser instance. See (*Parser).ParseStream.
+func ParseStream(ctx context.Context, r io.Reader, out chan<- ParseResult) error {
+	return defaultParser.ParseStream(ctx, r, out)
+}
+
+// ParseStream reads r line by line, parses each line, and sends the result on
+// out in input order, with Index set to the zero-based line number. Lines are
+// read only as results are received, so memory use does not grow with the
+// size of the input. Blank lines are skipped.
+//
+// ParseStream closes out when it returns. It returns nil at the end of input,
+// the context's error if ctx is canceled, or the error from reading r.
+func (p *Parser) ParseStream(ctx context.Context, r io.Reader, out chan<- ParseResult) error {
+	defer close(out)
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxStreamLineSize)
+
+	for line := 0; scanner.Scan(); line++ {
+		input := strings.TrimSpace(scanner.Text())
+		if input == "" {
+			continue
+		}
+
+		parsed, diagnostics := p.Parse(input)
+		result := ParseResult{
+			Index:       line,
+			Input:       input,
+			Parsed:      parsed,
+			Diagnostics: diagnostics,
+		}
+
+		select {
+		case out <- result:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	return scanner.Err()
+}
+
+// Triage parses each input and buckets the results by their most severe diagnostic.
+// Inputs with no diagnostics, or only informational ones, are clean. Inputs whose
+// worst diagnostic is a warning go to warnings, and any input with an error goes
+// to errors. Each bucket preserves the relative order of the inputs.
+func Triage(inputs []string) (clean, warnings, errors []TriagedInput) {
+	return defaultParser.Triage(inputs)
+}
+
+// Triage parses and buckets inputs using this parser instance. See Triage.
+func (p *Parser) Triage(inputs []string) (clean, warnings, errors []TriagedInput) {
+	for _, result := range p.ParseMany(inputs) {
+		severity, ok := maxSeverity(result.Diagnostics)
+		switch {
+		case ok && severity >= SeverityError:
+			errors = append(errors, result)
+		case ok && severity == SeverityWarning:
+			warnings = append(warnings, result)
+		default:
+			clean = append(clean, result)
+		}
+	}
+	return clean, warnings, errors
+}
+
+// maxSeverity returns the highest severity among diagnostics and whether any were present.
+func maxSeverity(diagnostics []Diagnostic) (DiagnosticSeverity, bool) {
+	if len(diagnostics) == 0 {
+		return SeverityInfo, false
+	}
+	highest := diagnostics[0].Severity
+	for _, d := range diagnostics[1:] {
+		if d.Severity > highest {
+			highest = d.Severity
+		}
+	}
+	return highest, true
+}
@@ -0,0 +1,207 @@
+package parser
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestParseMany(t *testing.T) {
+	inputs := []string{
+		"123 Main St, New York, NY 10001",
+		"456 Oak Ave, Boston, MA 02101",
+	}
+
+	results := ParseMany(inputs)
+	if len(results) != len(inputs) {
+		t.Fatalf("got %d results, want %d", len(results), len(inputs))
+	}
+
+	for i, result := range results {
+		if result.Index != i {
+			t.Errorf("results[%d].Index = %d, want %d", i, result.Index, i)
+		}
+		if result.Input != inputs[i] {
+			t.Errorf("results[%d].Input = %q, want %q", i, result.Input, inputs[i])
+		}
+		if result.Parsed == nil {
+			t.Errorf("results[%d].Parsed is nil", i)
+		}
+	}
+
+	if results[1].Parsed.City != "BOSTON" {
+		t.Errorf("results[1].Parsed.City = %q, want %q", results[1].Parsed.City, "BOSTON")
+	}
+}
+
+func TestParseBatch(t *testing.T) {
+	templates := []string{
+		"%d Main St, New York, NY 10001",
+		"%d N Oak Ave Apt 4B, Boston, MA 02101",
+		"%d Calle Luna, San Juan, PR 00901",
+		"Acme Widgets Inc, %d 5th Ave Suite 3300, New York, NY 10118",
+		"%d elm street springfield il 62704",
+		"%d Main St",
+		"PO Box %d, Springfield, IL",
+		"%d 1/2 Pine Rd, Waukesha, WI 53188",
+		"%d Main St Flat 4, Springfield, IL 62704",
+	}
+	inputs := make([]string, 1000)
+	for i := range inputs {
+		inputs[i] = fmt.Sprintf(templates[i%len(templates)], i+1)
+	}
+
+	p := New()
+	for _, workers := range []int{0, 1, 8, 2000} {
+		t.Run(fmt.Sprintf("%d workers", workers), func(t *testing.T) {
+			results := p.ParseBatch(inputs, workers)
+			if len(results) != len(inputs) {
+				t.Fatalf("got %d results, want %d", len(results), len(inputs))
+			}
+
+			for i, result := range results {
+				if result.Index != i {
+					t.Errorf("results[%d].Index = %d, want %d", i, result.Index, i)
+				}
+				if result.Input != inputs[i] {
+					t.Errorf("results[%d].Input = %q, want %q", i, result.Input, inputs[i])
+				}
+				wantParsed, wantDiagnostics := p.Parse(inputs[i])
+				if !reflect.DeepEqual(result.Parsed, wantParsed) {
+					t.Errorf("results[%d].Parsed = %+v, want %+v", i, result.Parsed, wantParsed)
+				}
+				if !reflect.DeepEqual(result.Diagnostics, wantDiagnostics) {
+					t.Errorf("results[%d].Diagnostics = %+v, want %+v", i, result.Diagnostics, wantDiagnostics)
+				}
+			}
+		})
+	}
+}
+
+func TestParse_ConcurrentDefaultParser(t *testing.T) {
+	const input = "Acme Inc, 123 N Main St Apt 4B, New York, NY 10001"
+	wantParsed, wantDiagnostics := New().Parse(input)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				parsed, diagnostics := Parse(input)
+				if !reflect.DeepEqual(parsed, wantParsed) || !reflect.DeepEqual(diagnostics, wantDiagnostics) {
+					t.Errorf("Parse = %+v, %v, want %+v, %v", parsed, diagnostics, wantParsed, wantDiagnostics)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestParseBatch_Empty(t *testing.T) {
+	if results := ParseBatch(nil, 4); len(results) != 0 {
+		t.Errorf("got %d results, want 0", len(results))
+	}
+}
+
+func TestTriage(t *testing.T) {
+	inputs := []string{
+		"123 Main St, New York, NY 10001",  // clean
+		"123 Main St, New York, NY",        // missing ZIP warning
+		"123 Main St, New York",            // missing state error
+		"123 MAIN ST SPRINGFIELD IL 62704", // info only, still clean
+		"456 Oak Ave, Boston, MA",          // missing ZIP warning
+		"Main Street",                      // missing state and street errors
+	}
+
+	clean, warnings, errors := Triage(inputs)
+
+	assertIndices := func(name string, got []TriagedInput, want []int) {
+		t.Helper()
+		if len(got) != len(want) {
+			t.Fatalf("%s: got %d results, want %d", name, len(got), len(want))
+		}
+		for i, result := range got {
+			if result.Index != want[i] {
+				t.Errorf("%s[%d].Index = %d, want %d", name, i, result.Index, want[i])
+			}
+			if result.Input != inputs[want[i]] {
+				t.Errorf("%s[%d].Input = %q, want %q", name, i, result.Input, inputs[want[i]])
+			}
+		}
+	}
+
+	assertIndices("clean", clean, []int{0, 3})
+	assertIndices("warnings", warnings, []int{1, 4})
+	assertIndices("errors", errors, []int{2, 5})
+}
+
+func TestTriage_Empty(t *testing.T) {
+	clean, warnings, errors := Triage(nil)
+	if clean != nil || warnings != nil || errors != nil {
+		t.Errorf("expected all buckets to be nil for empty input")
+	}
+}
+
+func TestParseStream(t *testing.T) {
+	input := "123 Main St, New York, NY 10001\n" +
+		"456 Oak Ave, Boston, MA 02101\r\n" +
+		"\n" +
+		"789 Elm St, Springfield, IL 62704\n"
+
+	out := make(chan ParseResult)
+	errc := make(chan error, 1)
+	go func() { errc <- ParseStream(context.Background(), strings.NewReader(input), out) }()
+
+	var results []ParseResult
+	for result := range out {
+		results = append(results, result)
+	}
+	if err := <-errc; err != nil {
+		t.Fatalf("ParseStream() error = %v", err)
+	}
+
+	want := []struct {
+		index int
+		input string
+		city  string
+	}{
+		{0, "123 Main St, New York, NY 10001", "NEW YORK"},
+		{1, "456 Oak Ave, Boston, MA 02101", "BOSTON"},
+		{3, "789 Elm St, Springfield, IL 62704", "SPRINGFIELD"},
+	}
+	if len(results) != len(want) {
+		t.Fatalf("got %d results, want %d", len(results), len(want))
+	}
+	for i, w := range want {
+		if results[i].Index != w.index {
+			t.Errorf("results[%d].Index = %d, want %d", i, results[i].Index, w.index)
+		}
+		if results[i].Input != w.input {
+			t.Errorf("results[%d].Input = %q, want %q", i, results[i].Input, w.input)
+		}
+		if results[i].Parsed.City != w.city {
+			t.Errorf("results[%d].Parsed.City = %q, want %q", i, results[i].Parsed.City, w.city)
+		}
+	}
+}
+
+func TestParseStream_Canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// Nobody receives, so the first send can only be abandoned by cancellation
+	out := make(chan ParseResult)
+	err := ParseStream(ctx, strings.NewReader("123 Main St, New York, NY 10001\n"), out)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("ParseStream() error = %v, want context.Canceled", err)
+	}
+	if _, ok := <-out; ok {
+		t.Error("expected out to be closed")
+	}
+}
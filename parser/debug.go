@@ -0,0 +1,64 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Debug returns a multi-line, human-readable dump of every component of the
+// parsed address with aligned labels, followed by the given diagnostics. It is
+// intended for troubleshooting parser behavior; the format may change and
+// should not be parsed.
+//
+//	parsed, diags := parser.Parse(input)
+//	fmt.Println(parsed.Debug(diags...))
+func (p *ParsedAddress) Debug(diagnostics ...Diagnostic) string {
+	if p == nil {
+		p = &ParsedAddress{}
+	}
+
+	secondary := strings.Join(strings.Fields(p.SecondaryUnit+" "+p.SecondaryNumber+" "+p.SecondaryExtra), " ")
+	fields := []struct {
+		label string
+		value string
+	}{
+		{"Firm", p.Firm},
+		{"House Number", p.HouseNumber},
+		{"Pre-Directional", p.PreDirectional},
+		{"Street Name", p.StreetName},
+		{"Street Suffix", p.StreetSuffix},
+		{"Post-Directional", p.PostDirectional},
+		{"Secondary", secondary},
+		{"City", p.City},
+		{"State", p.State},
+		{"ZIP", p.ZIPCode},
+		{"ZIP+4", p.ZIPPlus4},
+		{"Country", p.Country},
+		{"Phone", p.Phone},
+	}
+
+	width := 0
+	for _, f := range fields {
+		if len(f.label) > width {
+			width = len(f.label)
+		}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Input: %q\n", p.OriginalInput)
+	for _, f := range fields {
+		line := fmt.Sprintf("  %-*s  %s", width+1, f.label+":", f.value)
+		b.WriteString(strings.TrimRight(line, " "))
+		b.WriteByte('\n')
+	}
+
+	if len(diagnostics) == 0 {
+		b.WriteString("Diagnostics: none\n")
+		return b.String()
+	}
+	b.WriteString("Diagnostics:\n")
+	for _, d := range diagnostics {
+		fmt.Fprintf(&b, "  %-7s %s [%d:%d] %s\n", d.Severity, d.Code, d.Start, d.End, d.Message)
+	}
+	return b.String()
+}
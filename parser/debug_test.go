@@ -0,0 +1,46 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParsedAddress_Debug(t *testing.T) {
+	parsed, diags := Parse("123 N Main St Apt 4B, Springfield, IL")
+	out := parsed.Debug(diags...)
+
+	for _, want := range []string{
+		`Input: "123 N Main St Apt 4B, Springfield, IL"`,
+		"House Number:      123",
+		"Pre-Directional:   N",
+		"Street Name:       MAIN",
+		"Street Suffix:     ST",
+		"Secondary:         APT 4B",
+		"City:              SPRINGFIELD",
+		"State:             IL",
+		"ZIP+4:",
+		"Diagnostics:",
+		"Warning MISSING_ZIP",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Debug() missing %q in:\n%s", want, out)
+		}
+	}
+}
+
+func TestParsedAddress_DebugNoDiagnostics(t *testing.T) {
+	parsed, _ := Parse("123 Main St, Springfield, IL 62704")
+	out := parsed.Debug()
+
+	if !strings.Contains(out, "ZIP:               62704") {
+		t.Errorf("Debug() missing ZIP line in:\n%s", out)
+	}
+	if !strings.Contains(out, "Diagnostics: none") {
+		t.Errorf("Debug() missing empty diagnostics marker in:\n%s", out)
+	}
+
+	var nilAddr *ParsedAddress
+	if out := nilAddr.Debug(); !strings.Contains(out, "House Number:") {
+		t.Errorf("Debug() on nil missing labels:\n%s", out)
+	}
+}
@@ -0,0 +1,70 @@
+package parser
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// zipWordPattern matches a whole word that is a 5-digit ZIP or ZIP+4 code.
+var zipWordPattern = regexp.MustCompile(`^\d{5}(?:-\d{4})?$`)
+
+// LooksLikeAddress reports whether input plausibly contains a US address,
+// using cheap heuristics rather than a full parse. It is meant as a gate in
+// front of Parse for free-form input, so it favors speed over accuracy.
+//
+// The input looks like an address when it has a ZIP code after the first
+// word, or at least two of: a leading house number (or PO BOX), a street
+// suffix, and a state code or name. Input containing "@" or "://" is treated
+// as an email address or URL and rejected.
+func LooksLikeAddress(input string) bool {
+	if strings.Contains(input, "@") || strings.Contains(input, "://") {
+		return false
+	}
+
+	words := strings.FieldsFunc(input, func(r rune) bool {
+		return unicode.IsSpace(r) || r == ',' || r == ';'
+	})
+	for i, word := range words {
+		words[i] = strings.Trim(word, ".#")
+	}
+	if len(words) < 2 {
+		return false
+	}
+
+	var houseNumber, suffix, state bool
+	first := strings.ToUpper(words[0])
+	if first != "" && unicode.IsDigit(rune(first[0])) || first == "PO" || first == "P.O" {
+		houseNumber = true
+	}
+
+	for i, word := range words[1:] {
+		if zipWordPattern.MatchString(word) {
+			return true
+		}
+		upper := strings.ToUpper(word)
+		if _, ok := defaultLexicon.NormalizeStreetSuffix(upper); ok {
+			suffix = true
+		}
+		// Two-letter codes must be written in capitals so words like "in",
+		// "or", and "me" do not count; full names may be any case.
+		if len(word) == 2 && word != upper {
+			continue
+		}
+		if _, ok := defaultLexicon.NormalizeState(upper); ok {
+			state = true
+		} else if i+2 < len(words) {
+			if _, ok := defaultLexicon.NormalizeState(upper + " " + strings.ToUpper(words[i+2])); ok {
+				state = true
+			}
+		}
+	}
+
+	signals := 0
+	for _, found := range []bool{houseNumber, suffix, state} {
+		if found {
+			signals++
+		}
+	}
+	return signals >= 2
+}
@@ -0,0 +1,35 @@
+package parser
+
+import "testing"
+
+func TestLooksLikeAddress(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  bool
+	}{
+		{"full address", "123 Main St, Springfield, IL 62704", true},
+		{"street only", "456 Oak Avenue", true},
+		{"city state zip", "New York, NY 10001", true},
+		{"zip plus four", "Springfield IL 62704-1234", true},
+		{"po box", "PO Box 123, Anchorage, AK", true},
+		{"multiline", "1600 Pennsylvania Ave NW\nWashington, DC 20500", true},
+		{"full state name", "742 Evergreen Terrace Oregon", true},
+		{"random sentence", "The quick brown fox jumps over the lazy dog", false},
+		{"sentence with lowercase state words", "I live in a park or me", false},
+		{"sentence with number", "3 cats are in the house", false},
+		{"email", "john.doe@example.com", false},
+		{"email with address words", "123main.st@example.com 10001", false},
+		{"url", "https://example.com/123 Main St", false},
+		{"empty", "", false},
+		{"single word", "Springfield", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := LooksLikeAddress(tt.input); got != tt.want {
+				t.Errorf("LooksLikeAddress(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
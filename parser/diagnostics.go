@@ -0,0 +1,66 @@
+package parser
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/my-eq/go-usps/models"
+)
+
+// RequestDiagnostics runs the local AddressRequest.Validate check and reports
+// each invalid field as an error Diagnostic with code INVALID_FIELD. Request
+// fields have no position in the original input, so Start and End are zero.
+// Returns nil when the request is valid.
+func RequestDiagnostics(req *models.AddressRequest) []Diagnostic {
+	return defaultParser.RequestDiagnostics(req)
+}
+
+// RequestDiagnostics is like the package-level RequestDiagnostics, with
+// messages in the language set by WithDiagnosticLocale.
+func (p *Parser) RequestDiagnostics(req *models.AddressRequest) []Diagnostic {
+	err := req.Validate()
+	if err == nil {
+		return nil
+	}
+
+	var fieldErrs models.ValidationErrors
+	if !errors.As(err, &fieldErrs) {
+		return []Diagnostic{{
+			Severity: SeverityError,
+			Message:  err.Error(),
+			Code:     "INVALID_FIELD",
+		}}
+	}
+
+	diagnostics := make([]Diagnostic, 0, len(fieldErrs))
+	for _, fe := range fieldErrs {
+		diagnostics = append(diagnostics, Diagnostic{
+			Severity:    SeverityError,
+			Message:     fe.Error(),
+			Code:        "INVALID_FIELD",
+			Remediation: "Correct the " + fe.Field + " field before sending the request",
+			args:        [2]string{fe.Field, fe.Message},
+		})
+	}
+	return p.localizeDiagnostics("", diagnostics)
+}
+
+// MergeDiagnostics combines diagnostic lists, such as those returned by Parse
+// and RequestDiagnostics, into one list for display. The result is ordered by
+// severity (errors first) and then by position in the input; diagnostics that
+// compare equal keep their original relative order.
+func MergeDiagnostics(lists ...[]Diagnostic) []Diagnostic {
+	var merged []Diagnostic
+	for _, list := range lists {
+		merged = append(merged, list...)
+	}
+
+	sort.SliceStable(merged, func(i, j int) bool {
+		if merged[i].Severity != merged[j].Severity {
+			return merged[i].Severity > merged[j].Severity
+		}
+		return merged[i].Start < merged[j].Start
+	})
+
+	return merged
+}
@@ -0,0 +1,64 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/my-eq/go-usps/models"
+)
+
+func TestRequestDiagnostics(t *testing.T) {
+	if diags := RequestDiagnostics(&models.AddressRequest{
+		StreetAddress: "123 MAIN ST",
+		State:         "IL",
+		ZIPCode:       "62704",
+	}); diags != nil {
+		t.Errorf("RequestDiagnostics(valid) = %v, want nil", diags)
+	}
+
+	diags := RequestDiagnostics(&models.AddressRequest{StreetAddress: "123 MAIN ST", ZIPCode: "6270"})
+	if len(diags) != 2 {
+		t.Fatalf("got %d diagnostics, want 2: %v", len(diags), diags)
+	}
+	for _, d := range diags {
+		if d.Severity != SeverityError || d.Code != "INVALID_FIELD" {
+			t.Errorf("got %s %s, want Error INVALID_FIELD", d.Severity, d.Code)
+		}
+	}
+	if diags[0].Message != "state: is required" {
+		t.Errorf("Message = %q, want %q", diags[0].Message, "state: is required")
+	}
+}
+
+func TestMergeDiagnostics(t *testing.T) {
+	input := "123 Main St Apt, Springfield, IL"
+	parsed, parseDiags := Parse(input)
+
+	req := parsed.ToAddressRequest()
+	req.ZIPCode = "627"
+	reqDiags := RequestDiagnostics(req)
+
+	merged := MergeDiagnostics(parseDiags, reqDiags)
+
+	want := []struct {
+		severity DiagnosticSeverity
+		code     string
+	}{
+		{SeverityError, "INVALID_FIELD"},
+		{SeverityWarning, "MISSING_ZIP"},
+		{SeverityWarning, "INCOMPLETE_SECONDARY"},
+	}
+	if len(merged) != len(want) {
+		t.Fatalf("got %d diagnostics, want %d: %v", len(merged), len(want), merged)
+	}
+	for i, w := range want {
+		if merged[i].Severity != w.severity || merged[i].Code != w.code {
+			t.Errorf("merged[%d] = %s %s, want %s %s", i, merged[i].Severity, merged[i].Code, w.severity, w.code)
+		}
+	}
+}
+
+func TestMergeDiagnostics_Empty(t *testing.T) {
+	if merged := MergeDiagnostics(nil, nil); len(merged) != 0 {
+		t.Errorf("MergeDiagnostics(nil, nil) = %v, want empty", merged)
+	}
+}
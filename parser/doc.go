@@ -21,6 +21,11 @@
 //	}
 //	req := result.ToAddressRequest()
 //
+// Parse (or Parser.Parse for a configured parser) is the single entry point.
+// The returned ParsedAddress exposes each component, such as HouseNumber,
+// PreDirectional, StreetName, and StreetSuffix, and ToAddressRequest combines
+// them into the StreetAddress and other fields sent to USPS.
+//
 // The parser is designed to be extensible and follows idiomatic Go patterns with
 // strong typing and zero dependencies beyond the Go standard library.
 package parser
@@ -89,3 +89,16 @@ func ExampleParsedAddress_ToAddressRequest() {
 	//   State: IL
 	//   ZIP: 60601
 }
+
+func ExampleParsedAddress_LabelLines() {
+	parsed, _ := parser.Parse("Acme Widgets Inc, 350 5th Ave Suite 3300, New York, NY 10118")
+
+	for _, line := range parsed.LabelLines() {
+		fmt.Println(line)
+	}
+
+	// Output:
+	// ACME WIDGETS INC
+	// 350 5TH AVE STE 3300
+	// NEW YORK, NY 10118
+}
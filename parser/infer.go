@@ -0,0 +1,89 @@
+package parser
+
+import (
+	"context"
+	"strings"
+
+	"github.com/my-eq/go-usps/models"
+)
+
+// CityStateLookup returns the city and state USPS assigns to a ZIP code. It is
+// usually a thin wrapper around (*usps.Client).GetCityState:
+//
+//	lookup := func(ctx context.Context, zip string) (*models.CityStateResponse, error) {
+//	    return client.GetCityState(ctx, &models.CityStateRequest{ZIPCode: zip})
+//	}
+type CityStateLookup func(ctx context.Context, zip string) (*models.CityStateResponse, error)
+
+// WithStateInference fills in a missing state by looking up the ZIP code with
+// lookup, for input such as "123 Main St, Springfield, 62704". The city is
+// filled in too when it is missing, and compared with the USPS city when it is
+// present. Each inference is reported with a diagnostic.
+//
+// Parse has no context, so lookup is called with context.Background() and
+// should apply its own timeout. The parser makes no network calls unless this
+// option is set.
+func WithStateInference(lookup CityStateLookup) Option {
+	return func(p *Parser) {
+		p.stateLookup = lookup
+	}
+}
+
+// inferState looks up the state (and, if missing, the city) for an address
+// that has a ZIP code but no state.
+func (p *Parser) inferState(input string, addr *ParsedAddress) []Diagnostic {
+	if p.stateLookup == nil || addr.State != "" || addr.ZIPCode == "" {
+		return nil
+	}
+
+	start := strings.LastIndex(input, addr.ZIPCode)
+	end := start + len(addr.ZIPCode)
+	if start < 0 {
+		start, end = 0, len(input)
+	}
+
+	resp, err := p.stateLookup(context.Background(), addr.ZIPCode)
+	if err != nil || resp == nil || resp.State == "" {
+		message := "Could not look up the state for ZIP code " + addr.ZIPCode
+		if err != nil {
+			message += ": " + err.Error()
+		}
+		return []Diagnostic{{
+			Severity:    SeverityWarning,
+			Message:     message,
+			Start:       start,
+			End:         end,
+			Remediation: "Add a 2-letter state code",
+			Code:        "STATE_INFERENCE_FAILED",
+		}}
+	}
+
+	addr.State = strings.ToUpper(resp.State)
+	diagnostics := []Diagnostic{{
+		Severity: SeverityInfo,
+		Message:  "Inferred state " + addr.State + " from ZIP code " + addr.ZIPCode,
+		Start:    start,
+		End:      end,
+		Code:     "STATE_INFERRED",
+		args:     [2]string{addr.State},
+	}}
+
+	city := strings.ToUpper(resp.City)
+	switch {
+	case city == "":
+	case addr.City == "":
+		addr.City = city
+	case addr.City != city:
+		diagnostics = append(diagnostics, Diagnostic{
+			Severity:    SeverityWarning,
+			Message:     "City " + addr.City + " does not match " + city + ", the USPS city for ZIP code " + addr.ZIPCode,
+			Start:       start,
+			End:         end,
+			Remediation: "Check the city and ZIP code",
+			Code:        "CITY_ZIP_MISMATCH",
+			args:        [2]string{addr.City, city},
+		})
+	}
+
+	return diagnostics
+}
@@ -0,0 +1,121 @@
+package parser
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/my-eq/go-usps/models"
+)
+
+// mockCityStateLookup returns a CityStateLookup that answers from a fixed
+// table and records the ZIP codes it was asked about.
+func mockCityStateLookup(table map[string]models.CityStateResponse, calls *[]string) CityStateLookup {
+	return func(ctx context.Context, zip string) (*models.CityStateResponse, error) {
+		*calls = append(*calls, zip)
+		resp, ok := table[zip]
+		if !ok {
+			return nil, errors.New("ZIP code not found")
+		}
+		return &resp, nil
+	}
+}
+
+func TestWithStateInference(t *testing.T) {
+	table := map[string]models.CityStateResponse{
+		"62704": {City: "SPRINGFIELD", State: "IL", ZIPCode: "62704"},
+	}
+
+	tests := []struct {
+		name      string
+		input     string
+		wantCity  string
+		wantState string
+		wantCodes []string
+		wantCalls int
+	}{
+		{
+			name:      "state inferred",
+			input:     "123 Main St, Springfield, 62704",
+			wantCity:  "SPRINGFIELD",
+			wantState: "IL",
+			wantCodes: []string{"STATE_INFERRED"},
+			wantCalls: 1,
+		},
+		{
+			name:      "city and state inferred",
+			input:     "123 Main St, 62704",
+			wantCity:  "SPRINGFIELD",
+			wantState: "IL",
+			wantCodes: []string{"STATE_INFERRED"},
+			wantCalls: 1,
+		},
+		{
+			name:      "city mismatch",
+			input:     "123 Main St, Shelbyville, 62704",
+			wantCity:  "SHELBYVILLE",
+			wantState: "IL",
+			wantCodes: []string{"STATE_INFERRED", "CITY_ZIP_MISMATCH"},
+			wantCalls: 1,
+		},
+		{
+			name:      "lookup fails",
+			input:     "123 Main St, Springfield, 99999",
+			wantCity:  "SPRINGFIELD",
+			wantState: "",
+			wantCodes: []string{"STATE_INFERENCE_FAILED", "MISSING_STATE"},
+			wantCalls: 1,
+		},
+		{
+			name:      "state present",
+			input:     "123 Main St, Springfield, IL 62704",
+			wantCity:  "SPRINGFIELD",
+			wantState: "IL",
+			wantCalls: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls []string
+			p := New(WithStateInference(mockCityStateLookup(table, &calls)))
+
+			parsed, diagnostics := p.Parse(tt.input)
+
+			if parsed.City != tt.wantCity {
+				t.Errorf("City = %q, want %q", parsed.City, tt.wantCity)
+			}
+			if parsed.State != tt.wantState {
+				t.Errorf("State = %q, want %q", parsed.State, tt.wantState)
+			}
+			if len(calls) != tt.wantCalls {
+				t.Errorf("lookup calls = %d, want %d", len(calls), tt.wantCalls)
+			}
+
+			var codes []string
+			for _, d := range diagnostics {
+				codes = append(codes, d.Code)
+			}
+			if len(codes) != len(tt.wantCodes) {
+				t.Fatalf("codes = %v, want %v", codes, tt.wantCodes)
+			}
+			for i := range codes {
+				if codes[i] != tt.wantCodes[i] {
+					t.Errorf("codes = %v, want %v", codes, tt.wantCodes)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestWithStateInference_OfflineByDefault(t *testing.T) {
+	parsed, diagnostics := Parse("123 Main St, Springfield, 62704")
+
+	if parsed.State != "" {
+		t.Errorf("State = %q, want empty", parsed.State)
+	}
+	if len(diagnostics) != 1 || diagnostics[0].Code != "MISSING_STATE" {
+		t.Errorf("diagnostics = %v, want only MISSING_STATE", diagnostics)
+	}
+}
@@ -1,5 +1,10 @@
 package parser
 
+import "strings"
+
+// defaultLexicon is a shared, read-only lexicon used by package-level helpers.
+var defaultLexicon = newLexicon()
+
 // Lexicon contains USPS Publication 28 lookup tables for address components.
 // This follows USPS Pub 28 Appendix C for standard abbreviations.
 type Lexicon struct {
@@ -7,6 +12,9 @@ type Lexicon struct {
 	directionals          map[string]string
 	secondaryDesignators  map[string]string
 	states                map[string]string
+	stateAbbreviations    map[string]string
+	countries             map[string]string
+	firmMarkers           map[string]bool
 }
 
 // newLexicon creates and initializes a new Lexicon with USPS standard abbreviations.
@@ -16,7 +24,34 @@ func newLexicon() *Lexicon {
 		directionals:         initDirectionals(),
 		secondaryDesignators: initSecondaryDesignators(),
 		states:               initStates(),
+		stateAbbreviations:   initStateAbbreviations(),
+		countries:            initCountries(),
+		firmMarkers:          initFirmMarkers(),
+	}
+}
+
+// NormalizeState validates and normalizes a standalone state input to its
+// two-letter USPS code. It accepts USPS codes ("CA"), full names ("California"),
+// and traditional abbreviations ("Calif."), ignoring case, periods, and extra
+// whitespace. The second return value reports whether the input was recognized.
+//
+// Traditional abbreviations are only accepted here, not during full address
+// parsing, because many of them ("MASS", "DEL", "WASH") are also common words
+// in street and city names.
+func NormalizeState(input string) (string, bool) {
+	key := strings.Join(strings.Fields(strings.ToUpper(strings.ReplaceAll(input, ".", " "))), " ")
+	if key == "" {
+		return "", false
+	}
+	if code, ok := defaultLexicon.NormalizeState(key); ok {
+		return code, true
 	}
+	// Dotted forms like "N.Y." collapse to separate letters
+	if code, ok := defaultLexicon.NormalizeState(strings.ReplaceAll(key, " ", "")); ok && len(code) == 2 {
+		return code, true
+	}
+	code, ok := defaultLexicon.stateAbbreviations[key]
+	return code, ok
 }
 
 // NormalizeStreetSuffix returns the USPS standard abbreviation for a street suffix.
@@ -37,12 +72,37 @@ func (l *Lexicon) NormalizeSecondaryDesignator(s string) (string, bool) {
 	return normalized, ok
 }
 
+// RequiresSecondaryNumber reports whether a standardized secondary unit designator
+// must be followed by a unit number (e.g. APT 4B). Designators such as BSMT, FRNT,
+// LBBY, LOWR, OFC, PH, REAR, SIDE, and UPPR may stand alone.
+func (l *Lexicon) RequiresSecondaryNumber(designator string) bool {
+	switch designator {
+	case "BSMT", "FRNT", "LBBY", "LOWR", "OFC", "PH", "REAR", "SIDE", "UPPR":
+		return false
+	}
+	return true
+}
+
 // NormalizeState returns the two-letter state code.
 func (l *Lexicon) NormalizeState(s string) (string, bool) {
 	normalized, ok := l.states[s]
 	return normalized, ok
 }
 
+// NormalizeCountry returns the three-letter ISO country code for a country
+// name, such as CAN for Canada, which unlike CA cannot be mistaken for a state.
+// Multi-word names are matched with words separated by single spaces.
+func (l *Lexicon) NormalizeCountry(s string) (string, bool) {
+	normalized, ok := l.countries[s]
+	return normalized, ok
+}
+
+// isFirmMarker reports whether word marks a business or named building, such
+// as INC, LLC, CORP, or BUILDING.
+func (l *Lexicon) isFirmMarker(word string) bool {
+	return l.firmMarkers[strings.Trim(word, ".")]
+}
+
 // initStreetSuffixes initializes the street suffix lookup table.
 // Based on USPS Pub 28, Appendix C1.
 func initStreetSuffixes() map[string]string {
@@ -162,3 +222,45 @@ func initStates() map[string]string {
 	}
 	return states
 }
+
+// initStateAbbreviations initializes the traditional (pre-USPS) state abbreviation table.
+// These are accepted by NormalizeState but not by the tokenizer.
+func initStateAbbreviations() map[string]string {
+	return map[string]string{
+		"ALA": "AL", "ARIZ": "AZ", "ARK": "AR", "CAL": "CA", "CALIF": "CA",
+		"COLO": "CO", "CONN": "CT", "DEL": "DE", "FLA": "FL", "ILL": "IL",
+		"IND": "IN", "KAN": "KS", "KANS": "KS", "MASS": "MA", "MICH": "MI",
+		"MINN": "MN", "MISS": "MS", "MONT": "MT", "NEB": "NE", "NEBR": "NE",
+		"NEV": "NV", "N MEX": "NM", "OKLA": "OK", "ORE": "OR", "OREG": "OR",
+		"PENN": "PA", "PENNA": "PA", "TENN": "TN", "TEX": "TX", "WASH": "WA",
+		"WIS": "WI", "WISC": "WI", "WYO": "WY", "W VA": "WV", "D C": "DC",
+	}
+}
+
+// initCountries initializes the trailing country name lookup table. Besides the
+// United States, only neighboring countries are recognized so that their
+// addresses can be flagged rather than sent to the domestic USPS endpoints.
+func initCountries() map[string]string {
+	return map[string]string{
+		"US": "USA", "U S": "USA", "USA": "USA", "U S A": "USA",
+		"UNITED STATES": "USA", "UNITED STATES OF AMERICA": "USA",
+		"CANADA": "CAN", "MEXICO": "MEX",
+	}
+}
+
+// initFirmMarkers initializes the words that identify a leading segment as a
+// firm rather than a personal name: business entity designators and common
+// words for named buildings and institutions.
+func initFirmMarkers() map[string]bool {
+	markers := make(map[string]bool)
+	for _, word := range []string{
+		"INC", "INCORPORATED", "LLC", "LLP", "LP", "LTD", "LIMITED", "PC", "PLLC",
+		"CO", "COMPANY", "CORP", "CORPORATION", "GROUP", "ASSOCIATES", "PARTNERS",
+		"BUILDING", "BLDG", "TOWER", "TOWERS", "CENTER", "CENTRE", "PLAZA", "MALL",
+		"HOSPITAL", "CLINIC", "UNIVERSITY", "COLLEGE", "SCHOOL", "ACADEMY",
+		"BANK", "HOTEL", "CHURCH", "LIBRARY", "MUSEUM", "FOUNDATION",
+	} {
+		markers[word] = true
+	}
+	return markers
+}
@@ -188,3 +188,65 @@ func TestNewLexicon(t *testing.T) {
 		t.Error("states is empty")
 	}
 }
+
+func TestLexicon_RequiresSecondaryNumber(t *testing.T) {
+	lex := newLexicon()
+
+	tests := []struct {
+		designator string
+		want       bool
+	}{
+		{"APT", true},
+		{"STE", true},
+		{"FL", true},
+		{"RM", true},
+		{"#", true},
+		{"REAR", false},
+		{"BSMT", false},
+		{"PH", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.designator, func(t *testing.T) {
+			if got := lex.RequiresSecondaryNumber(tt.designator); got != tt.want {
+				t.Errorf("RequiresSecondaryNumber(%q) = %v, want %v", tt.designator, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeState(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+		found bool
+	}{
+		{"CA", "CA", true},
+		{"ca", "CA", true},
+		{" ny ", "NY", true},
+		{"California", "CA", true},
+		{"new   york", "NY", true},
+		{"District of Columbia", "DC", true},
+		{"Puerto Rico", "PR", true},
+		{"calif", "CA", true},
+		{"Calif.", "CA", true},
+		{"Mass.", "MA", true},
+		{"N.Y.", "NY", true},
+		{"W. Va.", "WV", true},
+		{"Narnia", "", false},
+		{"", "", false},
+		{"ZZ", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, found := NormalizeState(tt.input)
+			if found != tt.found {
+				t.Errorf("found = %v, want %v", found, tt.found)
+			}
+			if got != tt.want {
+				t.Errorf("got = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,135 @@
+package parser
+
+import "strings"
+
+// localizedDiagnostic is the translated text for one diagnostic code. The
+// message may contain "{text}", which is replaced with the input text the
+// diagnostic spans. The message and remediation may also contain "{0}" and
+// "{1}", which are replaced with the values the English text was formatted
+// with, such as a count or a field name.
+type localizedDiagnostic struct {
+	message     string
+	remediation string
+}
+
+// diagnosticCatalogs holds translated diagnostic text keyed by language and
+// then by diagnostic code. English is the language the pipeline produces, so
+// it has no entry here; codes missing from a catalog keep their English text.
+var diagnosticCatalogs = map[string]map[string]localizedDiagnostic{
+	"es": {
+		"MISSING_STATE": {
+			message:     "Falta el código de estado requerido",
+			remediation: "Agregue un código de estado de 2 letras (p. ej., PR, NY, CA)",
+		},
+		"MISSING_STREET": {
+			message:     "Falta la dirección postal",
+			remediation: "Agregue una dirección con número y nombre de calle",
+		},
+		"MISSING_CITY": {
+			message:     "Falta la ciudad; se requiere una ciudad o un código ZIP",
+			remediation: "Agregue el nombre de la ciudad o un código ZIP de 5 dígitos",
+		},
+		"MISSING_ZIP": {
+			message:     "Falta el código ZIP",
+			remediation: "Agregue un código ZIP de 5 dígitos para una mejor validación de la dirección",
+		},
+		"INCOMPLETE_SECONDARY": {
+			message:     "La unidad secundaria {text} no tiene número",
+			remediation: "Agregue el número de la unidad después del designador (p. ej., APT 4B) o elimine el designador",
+		},
+		"SUSPICIOUS_CHARACTERS": {
+			message:     "Se eliminaron {0} caracteres invisibles o de control de la entrada",
+			remediation: "Elimine los caracteres de ancho cero o de control, que suelen introducirse al copiar y pegar",
+		},
+		"INFERRED_SEGMENTATION": {
+			message: "Los segmentos de la dirección se dedujeron del estado y el código ZIP porque ninguna coma separa la calle de la ciudad",
+		},
+		"SPLIT_GLUED_TOKENS": {
+			message:     "Se separó {text} en unidad secundaria y código ZIP",
+			remediation: "Separe la unidad secundaria y el código ZIP con un espacio o una coma",
+		},
+		"DUPLICATE_SECONDARY": {
+			message:     "Se eliminó la unidad secundaria duplicada {text}",
+			remediation: "Ingrese la unidad secundaria una sola vez",
+		},
+		"PHONE_REMOVED": {
+			message:     "Se eliminó el número de teléfono {text} de la dirección",
+			remediation: "Ingrese el número de teléfono en un campo aparte",
+		},
+		"STATE_INFERRED": {
+			message: "Se dedujo el estado {0} a partir del código ZIP {text}",
+		},
+		"STATE_INFERENCE_FAILED": {
+			message:     "No se pudo determinar el estado para el código ZIP {text}",
+			remediation: "Agregue un código de estado de 2 letras",
+		},
+		"CITY_ZIP_MISMATCH": {
+			message:     "La ciudad {0} no coincide con {1}, la ciudad de USPS para el código ZIP {text}",
+			remediation: "Verifique la ciudad y el código ZIP",
+		},
+		"DIRECTIONAL_AS_NAME": {
+			message:     "Se interpretó la dirección cardinal {text} como nombre de la calle",
+			remediation: "Si la dirección cardinal no es el nombre de la calle, agregue el nombre de la calle después (p. ej., N MAIN ST)",
+		},
+		"UNKNOWN_SECONDARY": {
+			message:     "{text} no es un designador de unidad secundaria reconocido por USPS",
+			remediation: "Use un designador de unidad reconocido por USPS, como APT, STE o UNIT",
+		},
+		"SECONDARY_SEGMENTS_TRUNCATED": {
+			message:     "Se omitieron {0} unidades secundarias que exceden el límite de {1}: {text}",
+			remediation: "Conserve solo las unidades secundarias necesarias para la entrega",
+		},
+		"LEADING_NAME_IGNORED": {
+			message:     "Se omitió el nombre inicial {text}, que no tiene un indicador de empresa como INC o LLC",
+			remediation: "Si es una empresa, incluya su designador (p. ej., INC, LLC, CORP) o indique la empresa por separado",
+		},
+		"DIAGNOSTICS_TRUNCATED": {
+			message: "Se omitieron {0} diagnósticos adicionales",
+		},
+		"INVALID_FIELD": {
+			message:     "El campo {0} no es válido: {1}",
+			remediation: "Corrija el campo {0} antes de enviar la solicitud",
+		},
+	},
+}
+
+// WithDiagnosticLocale sets the language of diagnostic messages and
+// remediations returned by Parse. The language is a tag such as "es" or
+// "es-PR"; only the primary subtag is used. English ("en") and Spanish ("es")
+// are supported, and any other language falls back to English. Diagnostic
+// codes are never translated, so logic keyed on Code works in every locale.
+func WithDiagnosticLocale(lang string) Option {
+	return func(p *Parser) {
+		lang = strings.ToLower(strings.TrimSpace(lang))
+		if i := strings.IndexAny(lang, "-_"); i >= 0 {
+			lang = lang[:i]
+		}
+		p.diagnosticLocale = lang
+	}
+}
+
+// localizeDiagnostics rewrites diagnostic text into the configured locale.
+func (p *Parser) localizeDiagnostics(input string, diagnostics []Diagnostic) []Diagnostic {
+	catalog, ok := diagnosticCatalogs[p.diagnosticLocale]
+	if !ok {
+		return diagnostics
+	}
+
+	for i, d := range diagnostics {
+		text, ok := catalog[d.Code]
+		if !ok || (d.args[0] == "" && strings.Contains(text.message+text.remediation, "{0}")) {
+			// Keep the English text when the values it was formatted with are unknown
+			continue
+		}
+		span := ""
+		if d.Start >= 0 && d.Start < d.End && d.End <= len(input) {
+			span = input[d.Start:d.End]
+		}
+		message := strings.NewReplacer("{text}", span, "{0}", d.args[0], "{1}", d.args[1])
+		remediation := strings.NewReplacer("{0}", d.args[0], "{1}", d.args[1])
+		diagnostics[i].Message = strings.TrimSpace(message.Replace(text.message))
+		diagnostics[i].Remediation = remediation.Replace(text.remediation)
+	}
+
+	return diagnostics
+}
@@ -1,5 +1,7 @@
 package parser
 
+import "strings"
+
 // Normalizer applies USPS standardization rules to tokens.
 type Normalizer struct {
 	lexicon *Lexicon
@@ -25,6 +27,14 @@ func (n *Normalizer) normalize(tokens []Token) ([]Token, []Diagnostic) {
 			seenStreetSuffix = true
 		}
 
+		// A directional directly followed by a suffix is the street name, as
+		// in "North Ave", so it keeps its original spelling
+		if d, ok := directionalAsName(tokens, i, seenStreetSuffix); ok {
+			token.Type = TokenStreetName
+			token.Value = strings.ToUpper(token.Original)
+			diagnostics = append(diagnostics, d)
+		}
+
 		// Disambiguate directionals (pre vs post)
 		if token.Type == TokenPreDirectional {
 			switch {
@@ -77,3 +87,38 @@ func (n *Normalizer) normalize(tokens []Token) ([]Token, []Diagnostic) {
 
 	return normalized, diagnostics
 }
+
+// directionalAsName reports whether the directional at tokens[i] is the whole
+// street name: it starts the street, right after the house number and any
+// pre-directional (as in "123 W North Ave"), and is immediately followed by
+// the street suffix. It returns an informational diagnostic for the
+// reinterpretation.
+func directionalAsName(tokens []Token, i int, seenStreetSuffix bool) (Diagnostic, bool) {
+	if tokens[i].Type != TokenPreDirectional || seenStreetSuffix {
+		return Diagnostic{}, false
+	}
+	if i+1 >= len(tokens) || tokens[i+1].Type != TokenStreetSuffix {
+		return Diagnostic{}, false
+	}
+	start := i
+	if start > 0 && tokens[start-1].Type == TokenPreDirectional {
+		start--
+	}
+	if start > 0 && tokens[start-1].Type != TokenHouseNumber {
+		return Diagnostic{}, false
+	}
+	if isSuffixAsStreetName(tokens, i+1, 0) {
+		// "E AVENUE K": the suffix and letter are the name
+		return Diagnostic{}, false
+	}
+
+	name := strings.ToUpper(tokens[i].Original)
+	return Diagnostic{
+		Severity:    SeverityInfo,
+		Message:     "Treated directional " + name + " as the street name",
+		Start:       tokens[i].Start,
+		End:         tokens[i].End,
+		Remediation: "If " + name + " is a direction rather than the street name, add the street name after it (e.g., N MAIN ST)",
+		Code:        "DIRECTIONAL_AS_NAME",
+	}, true
+}
@@ -0,0 +1,442 @@
+package parser
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Option is a functional option for configuring a Parser.
+type Option func(*Parser)
+
+// HashDesignatorMode controls how the "#" secondary unit designator is emitted.
+type HashDesignatorMode int
+
+const (
+	// HashKeep preserves "#" as the designator, producing "# 12". This is the default.
+	HashKeep HashDesignatorMode = iota
+	// HashDrop removes the designator and keeps only the value, producing "12".
+	// USPS does not accept a secondary number without a designator, so this mode
+	// is only useful when the secondary is recombined by the caller.
+	HashDrop
+	// HashToUnit replaces "#" with the UNIT designator, producing "UNIT 12".
+	HashToUnit
+)
+
+// WithHashDesignator sets how the "#" secondary unit designator is handled.
+// USPS Publication 28 accepts "#" when the actual designator is unknown, so
+// HashKeep is recommended unless a downstream system requires a word designator,
+// in which case HashToUnit is the safest substitute.
+func WithHashDesignator(mode HashDesignatorMode) Option {
+	return func(p *Parser) {
+		p.hashDesignator = mode
+	}
+}
+
+// applyHashDesignator rewrites a "#" secondary unit according to the configured mode.
+func (p *Parser) applyHashDesignator(addr *ParsedAddress) {
+	if addr.SecondaryUnit != "#" {
+		return
+	}
+	switch p.hashDesignator {
+	case HashDrop:
+		addr.SecondaryUnit = ""
+	case HashToUnit:
+		addr.SecondaryUnit = "UNIT"
+	}
+}
+
+// WithTerritoryNames enables detection of U.S. territory names written out in
+// full at the end of the address, such as "San Juan, Puerto Rico 00901" or
+// "Charlotte Amalie, US Virgin Islands 00802". When the input has no explicit
+// state, a trailing territory name is replaced by its USPS code (PR, GU, VI,
+// AS, or MP) instead of being absorbed into the city.
+func WithTerritoryNames() Option {
+	return func(p *Parser) {
+		p.territoryNames = true
+	}
+}
+
+// WithMaxDiagnostics caps the number of diagnostics returned by Parse. When
+// more than n are produced, they are sorted most severe first (see
+// MergeDiagnostics), the first n are kept, and a single Info diagnostic with
+// code DIAGNOSTICS_TRUNCATED reporting how many were suppressed is appended.
+// A value of zero or less leaves diagnostics unbounded, which is the default.
+func WithMaxDiagnostics(n int) Option {
+	return func(p *Parser) {
+		p.maxDiagnostics = n
+	}
+}
+
+// limitDiagnostics applies the WithMaxDiagnostics cap.
+func (p *Parser) limitDiagnostics(diagnostics []Diagnostic) []Diagnostic {
+	if p.maxDiagnostics <= 0 || len(diagnostics) <= p.maxDiagnostics {
+		return diagnostics
+	}
+
+	sorted := MergeDiagnostics(diagnostics)
+	suppressed := len(sorted) - p.maxDiagnostics
+	return append(sorted[:p.maxDiagnostics], Diagnostic{
+		Severity: SeverityInfo,
+		Message:  fmt.Sprintf("%d additional diagnostic(s) suppressed", suppressed),
+		Code:     "DIAGNOSTICS_TRUNCATED",
+		args:     [2]string{strconv.Itoa(suppressed)},
+	})
+}
+
+// WithAggressiveSplitting enables heuristics for OCR output where tokens ran
+// together. When enabled, a word made of a secondary unit designator, a unit
+// number, and a trailing 5-digit run (e.g. "APT4B62704") is split into the
+// secondary unit and a ZIP code, and an Info diagnostic with code
+// SPLIT_GLUED_TOKENS is reported. Splitting is only attempted when the input
+// has no other ZIP code. Disabled by default.
+func WithAggressiveSplitting(enabled bool) Option {
+	return func(p *Parser) {
+		p.aggressiveSplitting = enabled
+	}
+}
+
+// splitGluedTokens applies the WithAggressiveSplitting heuristics.
+func (p *Parser) splitGluedTokens(tokens []Token) ([]Token, []Diagnostic) {
+	if !p.aggressiveSplitting {
+		return tokens, nil
+	}
+	for _, token := range tokens {
+		if token.Type == TokenZIPCode {
+			return tokens, nil
+		}
+	}
+
+	for i, token := range tokens {
+		split, ok := p.splitGluedSecondary(token)
+		if !ok {
+			continue
+		}
+
+		d := Diagnostic{
+			Severity:    SeverityInfo,
+			Message:     fmt.Sprintf("Split %s into secondary unit %s %s and ZIP code %s", token.Original, split[0].Value, split[1].Value, split[2].Value),
+			Start:       token.Start,
+			End:         token.End,
+			Remediation: "Separate the secondary unit and ZIP code with a space or comma",
+			Code:        "SPLIT_GLUED_TOKENS",
+		}
+
+		merged := append([]Token{}, tokens[:i]...)
+		merged = append(merged, split...)
+		return append(merged, tokens[i+1:]...), []Diagnostic{d}
+	}
+
+	return tokens, nil
+}
+
+// splitGluedSecondary splits a word like "APT4B62704" into designator, unit
+// number, and ZIP code tokens. The designator is the longest leading run of
+// letters (or "#") that the lexicon recognizes.
+func (p *Parser) splitGluedSecondary(token Token) ([]Token, bool) {
+	word := token.Original
+	if len(word) < 7 || !isNumeric(word[len(word)-5:]) {
+		return nil, false
+	}
+	body, zip := word[:len(word)-5], word[len(word)-5:]
+
+	letters := 0
+	for letters < len(body) && (body[letters] == '#' || (body[letters] >= 'A' && body[letters] <= 'Z')) {
+		letters++
+	}
+
+	for n := letters; n > 0; n-- {
+		designator, ok := p.tokenizer.lexicon.NormalizeSecondaryDesignator(body[:n])
+		if !ok || n == len(body) {
+			continue
+		}
+		number := body[n:]
+		if strings.IndexFunc(number, unicode.IsDigit) < 0 {
+			return nil, false
+		}
+
+		// Offsets assume the word is contiguous in the original input
+		zipStart := token.Start + len(body)
+		return []Token{
+			{Type: TokenSecondaryDesignator, Value: designator, Original: body[:n], Start: token.Start, End: token.Start + n},
+			{Type: TokenSecondaryNumber, Value: number, Original: number, Start: token.Start + n, End: zipStart},
+			{Type: TokenZIPCode, Value: zip, Original: zip, Start: zipStart, End: token.End},
+		}, true
+	}
+
+	return nil, false
+}
+
+// WithDedupeSecondary collapses a secondary unit that is repeated verbatim
+// right after itself, as in "123 Main St, Unit 1, Unit 1" from a duplicated
+// paste, into a single occurrence and reports an Info diagnostic with code
+// DUPLICATE_SECONDARY spanning the removed copy. Distinct secondaries such as
+// "Unit 1 Ste 2" are never collapsed. Disabled by default.
+func WithDedupeSecondary(enabled bool) Option {
+	return func(p *Parser) {
+		p.dedupeSecondary = enabled
+	}
+}
+
+// removeDuplicateSecondary applies the WithDedupeSecondary rule.
+func (p *Parser) removeDuplicateSecondary(tokens []Token) ([]Token, []Diagnostic) {
+	if !p.dedupeSecondary {
+		return tokens, nil
+	}
+
+	var kept []Token
+	var diagnostics []Diagnostic
+	prevStart, prevLen := -1, 0
+
+	for i := 0; i < len(tokens); {
+		if tokens[i].Type != TokenSecondaryDesignator {
+			kept = append(kept, tokens[i])
+			prevStart = -1
+			i++
+			continue
+		}
+
+		n := 1
+		if i+1 < len(tokens) && tokens[i+1].Type == TokenSecondaryNumber {
+			n = 2
+		}
+
+		if prevStart >= 0 && n == prevLen && sameTokenValues(kept[prevStart:prevStart+prevLen], tokens[i:i+n]) {
+			diagnostics = append(diagnostics, Diagnostic{
+				Severity:    SeverityInfo,
+				Message:     "Removed duplicate secondary unit " + joinTokenValues(tokens[i:i+n]),
+				Start:       tokens[i].Start,
+				End:         tokens[i+n-1].End,
+				Remediation: "Enter the secondary unit only once",
+				Code:        "DUPLICATE_SECONDARY",
+			})
+			i += n
+			continue
+		}
+
+		prevStart, prevLen = len(kept), n
+		kept = append(kept, tokens[i:i+n]...)
+		i += n
+	}
+
+	return kept, diagnostics
+}
+
+// DefaultMaxSecondarySegments is the number of chained secondary units, such
+// as the three in "BLDG 5, STE 200, RM 3", that Parse keeps by default.
+const DefaultMaxSecondarySegments = 4
+
+// WithMaxSecondarySegments caps the number of chained secondary units Parse
+// keeps. A secondary unit is a designator and its unit number, if any. Units
+// past the first n are dropped and a Warning diagnostic with code
+// SECONDARY_SEGMENTS_TRUNCATED spanning them is reported, so that input with
+// a long run of units ("Unit 1 Unit 2 Unit 3 ...") cannot grow the secondary
+// address without bound. A value of zero or less restores
+// DefaultMaxSecondarySegments.
+func WithMaxSecondarySegments(n int) Option {
+	return func(p *Parser) {
+		p.maxSecondarySegments = n
+	}
+}
+
+// limitSecondarySegments applies the WithMaxSecondarySegments cap.
+func (p *Parser) limitSecondarySegments(tokens []Token) ([]Token, []Diagnostic) {
+	limit := p.maxSecondarySegments
+	if limit <= 0 {
+		limit = DefaultMaxSecondarySegments
+	}
+
+	var kept []Token
+	var dropped []Token
+	segments := 0
+
+	for i := 0; i < len(tokens); i++ {
+		if tokens[i].Type != TokenSecondaryDesignator {
+			kept = append(kept, tokens[i])
+			continue
+		}
+
+		n := 1
+		if i+1 < len(tokens) && tokens[i+1].Type == TokenSecondaryNumber {
+			n = 2
+		}
+
+		segments++
+		if segments > limit {
+			dropped = append(dropped, tokens[i:i+n]...)
+		} else {
+			kept = append(kept, tokens[i:i+n]...)
+		}
+		i += n - 1
+	}
+
+	if len(dropped) == 0 {
+		return tokens, nil
+	}
+
+	return kept, []Diagnostic{{
+		Severity:    SeverityWarning,
+		Message:     fmt.Sprintf("Dropped %d secondary unit(s) beyond the limit of %d: %s", segments-limit, limit, joinTokenValues(dropped)),
+		Start:       dropped[0].Start,
+		End:         dropped[len(dropped)-1].End,
+		Remediation: "Keep only the secondary units needed for delivery",
+		Code:        "SECONDARY_SEGMENTS_TRUNCATED",
+		args:        [2]string{strconv.Itoa(segments - limit), strconv.Itoa(limit)},
+	}}
+}
+
+// sameTokenValues reports whether two token runs have identical values.
+func sameTokenValues(a, b []Token) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].Value != b[i].Value {
+			return false
+		}
+	}
+	return true
+}
+
+// joinTokenValues joins the values of tokens with single spaces.
+func joinTokenValues(tokens []Token) string {
+	values := make([]string, len(tokens))
+	for i, token := range tokens {
+		values[i] = token.Value
+	}
+	return strings.Join(values, " ")
+}
+
+// territoryCodes maps spelled-out territory names to USPS codes. Keys are the
+// uppercased words joined by single spaces, as produced by the tokenizer.
+var territoryCodes = map[string]string{
+	"PUERTO RICO":              "PR",
+	"GUAM":                     "GU",
+	"VIRGIN ISLANDS":           "VI",
+	"US VIRGIN ISLANDS":        "VI",
+	"U S VIRGIN ISLANDS":       "VI",
+	"AMERICAN SAMOA":           "AS",
+	"NORTHERN MARIANA ISLANDS": "MP",
+}
+
+// maxTerritoryWords is the word count of the longest key in territoryCodes.
+const maxTerritoryWords = 4
+
+// applyTerritoryNames merges a trailing territory name into a single state
+// token when no state token is present. Only the words directly before the ZIP
+// code and country (or at the end of the input) are considered.
+func (p *Parser) applyTerritoryNames(tokens []Token) []Token {
+	if !p.territoryNames {
+		return tokens
+	}
+
+	end := len(tokens)
+	for end > 0 {
+		switch tokens[end-1].Type {
+		case TokenZIPCode, TokenZIPPlus4, TokenCountry:
+			end--
+			continue
+		}
+		break
+	}
+	for _, token := range tokens[:end] {
+		if token.Type == TokenState {
+			return tokens
+		}
+	}
+
+	for n := maxTerritoryWords; n >= 1; n-- {
+		start := end - n
+		if start < 1 {
+			continue
+		}
+		words := make([]string, 0, n)
+		for _, token := range tokens[start:end] {
+			words = append(words, token.Original)
+		}
+		name := strings.Join(words, " ")
+		code, ok := territoryCodes[name]
+		if !ok {
+			continue
+		}
+
+		state := Token{
+			Type:     TokenState,
+			Value:    code,
+			Original: name,
+			Start:    tokens[start].Start,
+			End:      tokens[end-1].End,
+		}
+		merged := append([]Token{}, tokens[:start]...)
+		merged = append(merged, state)
+		return append(merged, tokens[end:]...)
+	}
+
+	return tokens
+}
+
+// WithFastPathForNormalized enables a fast path for input that is already
+// uppercase with clean separators, as commonly exported by legacy systems
+// (e.g. "123 N MAIN ST, SPRINGFIELD, IL 62701"). Such input skips the
+// uppercasing and whitespace cleanup pass of tokenization; abbreviation and
+// all other standardization rules are still applied, so the result is the
+// same as without the option. Input qualifies when it is printable ASCII with
+// no lowercase letters, periods, or semicolons, words are separated by a
+// single space or a comma optionally followed by one space, and there is no
+// leading or trailing separator. Other input takes the normal path. Disabled
+// by default.
+func WithFastPathForNormalized(enabled bool) Option {
+	return func(p *Parser) {
+		p.fastPathNormalized = enabled
+	}
+}
+
+// tokenize tokenizes input, taking the WithFastPathForNormalized fast path
+// when it is enabled and input qualifies.
+func (p *Parser) tokenize(input string) []Token {
+	if p.fastPathNormalized {
+		if normalized, positionMap, ok := normalizedInputMapping(input); ok {
+			return p.tokenizer.tokenizeNormalized(input, normalized, positionMap)
+		}
+	}
+	return p.tokenizer.tokenize(input)
+}
+
+// normalizedInputMapping returns the same result as normalizeInputWithMapping
+// for input that is already normalized apart from its commas, which become
+// spaces. It reports false, and does no further work, as soon as input is
+// found not to qualify for WithFastPathForNormalized.
+func normalizedInputMapping(input string) (string, []int, bool) {
+	n := len(input)
+	if n == 0 {
+		return "", nil, false
+	}
+
+	buf := make([]byte, 0, n)
+	positionMap := make([]int, 0, n)
+	for i := 0; i < n; i++ {
+		c := input[i]
+		switch {
+		case c == ' ':
+			if i == 0 || i == n-1 || input[i-1] == ' ' {
+				return "", nil, false
+			}
+			if input[i-1] == ',' {
+				// The comma already produced the separator
+				continue
+			}
+		case c == ',':
+			if i == 0 || i == n-1 || input[i-1] == ' ' || input[i-1] == ',' {
+				return "", nil, false
+			}
+			c = ' '
+		case c < ' ' || c >= 0x7f, c >= 'a' && c <= 'z', c == '.', c == ';':
+			return "", nil, false
+		}
+		buf = append(buf, c)
+		positionMap = append(positionMap, i)
+	}
+
+	return string(buf), positionMap, true
+}
@@ -0,0 +1,707 @@
+package parser
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/my-eq/go-usps/models"
+)
+
+func TestWithHashDesignator(t *testing.T) {
+	tests := []struct {
+		name          string
+		opts          []Option
+		input         string
+		wantUnit      string
+		wantNumber    string
+		wantSecondary string
+	}{
+		{
+			name:          "default keeps hash",
+			input:         "123 Main St #12, Springfield, IL 62704",
+			wantUnit:      "#",
+			wantNumber:    "12",
+			wantSecondary: "# 12",
+		},
+		{
+			name:          "keep",
+			opts:          []Option{WithHashDesignator(HashKeep)},
+			input:         "123 Main St #12, Springfield, IL 62704",
+			wantUnit:      "#",
+			wantNumber:    "12",
+			wantSecondary: "# 12",
+		},
+		{
+			name:          "drop",
+			opts:          []Option{WithHashDesignator(HashDrop)},
+			input:         "123 Main St #12, Springfield, IL 62704",
+			wantUnit:      "",
+			wantNumber:    "12",
+			wantSecondary: "12",
+		},
+		{
+			name:          "to unit",
+			opts:          []Option{WithHashDesignator(HashToUnit)},
+			input:         "123 Main St #12, Springfield, IL 62704",
+			wantUnit:      "UNIT",
+			wantNumber:    "12",
+			wantSecondary: "UNIT 12",
+		},
+		{
+			name:          "separated hash to unit",
+			opts:          []Option{WithHashDesignator(HashToUnit)},
+			input:         "123 Main St # 4B, Springfield, IL 62704",
+			wantUnit:      "UNIT",
+			wantNumber:    "4B",
+			wantSecondary: "UNIT 4B",
+		},
+		{
+			name:          "to unit leaves other designators alone",
+			opts:          []Option{WithHashDesignator(HashToUnit)},
+			input:         "123 Main St Apt 12, Springfield, IL 62704",
+			wantUnit:      "APT",
+			wantNumber:    "12",
+			wantSecondary: "APT 12",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed, diagnostics := New(tt.opts...).Parse(tt.input)
+
+			if parsed.SecondaryUnit != tt.wantUnit {
+				t.Errorf("SecondaryUnit = %q, want %q", parsed.SecondaryUnit, tt.wantUnit)
+			}
+			if parsed.SecondaryNumber != tt.wantNumber {
+				t.Errorf("SecondaryNumber = %q, want %q", parsed.SecondaryNumber, tt.wantNumber)
+			}
+
+			req := parsed.ToAddressRequest()
+			if req.SecondaryAddress != tt.wantSecondary {
+				t.Errorf("SecondaryAddress = %q, want %q", req.SecondaryAddress, tt.wantSecondary)
+			}
+			if req.StreetAddress != "123 MAIN ST" {
+				t.Errorf("StreetAddress = %q, want %q", req.StreetAddress, "123 MAIN ST")
+			}
+			if req.City != "SPRINGFIELD" {
+				t.Errorf("City = %q, want %q", req.City, "SPRINGFIELD")
+			}
+			if len(diagnostics) != 0 {
+				t.Errorf("got %d diagnostics, want 0: %+v", len(diagnostics), diagnostics)
+			}
+		})
+	}
+}
+
+func TestWithTerritoryNames(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		wantCity  string
+		wantState string
+		wantZIP   string
+	}{
+		{
+			name:      "puerto rico",
+			input:     "100 Calle Sol, Ponce, Puerto Rico 00716",
+			wantCity:  "PONCE",
+			wantState: "PR",
+			wantZIP:   "00716",
+		},
+		{
+			name:      "puerto rico lowercase without zip",
+			input:     "100 Calle Sol, Ponce, puerto rico",
+			wantCity:  "PONCE",
+			wantState: "PR",
+		},
+		{
+			name:      "guam",
+			input:     "100 Marine Corps Dr, Tamuning, Guam 96913",
+			wantCity:  "TAMUNING",
+			wantState: "GU",
+			wantZIP:   "96913",
+		},
+		{
+			name:      "virgin islands",
+			input:     "5 Main St, Charlotte Amalie, Virgin Islands 00802",
+			wantCity:  "CHARLOTTE AMALIE",
+			wantState: "VI",
+			wantZIP:   "00802",
+		},
+		{
+			name:      "us virgin islands",
+			input:     "5 Main St, Charlotte Amalie, US Virgin Islands 00802",
+			wantCity:  "CHARLOTTE AMALIE",
+			wantState: "VI",
+			wantZIP:   "00802",
+		},
+		{
+			name:      "dotted us virgin islands",
+			input:     "5 Main St, Charlotte Amalie, U.S. Virgin Islands 00802",
+			wantCity:  "CHARLOTTE AMALIE",
+			wantState: "VI",
+			wantZIP:   "00802",
+		},
+		{
+			name:      "explicit state wins",
+			input:     "5 Main St, Puerto Rico, TX 77001",
+			wantCity:  "PUERTO RICO",
+			wantState: "TX",
+			wantZIP:   "77001",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed, _ := New(WithTerritoryNames()).Parse(tt.input)
+			if parsed.City != tt.wantCity {
+				t.Errorf("City = %q, want %q", parsed.City, tt.wantCity)
+			}
+			if parsed.State != tt.wantState {
+				t.Errorf("State = %q, want %q", parsed.State, tt.wantState)
+			}
+			if parsed.ZIPCode != tt.wantZIP {
+				t.Errorf("ZIPCode = %q, want %q", parsed.ZIPCode, tt.wantZIP)
+			}
+		})
+	}
+}
+
+func TestWithTerritoryNames_DisabledByDefault(t *testing.T) {
+	parsed, _ := Parse("100 Calle Sol, Ponce, Puerto Rico 00716")
+	if parsed.State != "" {
+		t.Errorf("State = %q, want empty without WithTerritoryNames", parsed.State)
+	}
+}
+
+func TestWithMaxDiagnostics(t *testing.T) {
+	// A zero-width space and a bare designator with no street, city, state, or
+	// ZIP produce six diagnostics, three of them errors
+	input := "\u200bApt"
+
+	_, all := Parse(input)
+	if len(all) != 6 {
+		t.Fatalf("got %d diagnostics without a cap, want 6: %v", len(all), all)
+	}
+
+	_, diags := New(WithMaxDiagnostics(2)).Parse(input)
+	if len(diags) != 3 {
+		t.Fatalf("got %d diagnostics, want 3: %v", len(diags), diags)
+	}
+	for i := 0; i < 2; i++ {
+		if diags[i].Severity != SeverityError {
+			t.Errorf("diags[%d].Severity = %v, want Error", i, diags[i].Severity)
+		}
+	}
+
+	summary := diags[2]
+	if summary.Code != "DIAGNOSTICS_TRUNCATED" || summary.Severity != SeverityInfo {
+		t.Errorf("summary = %s %s, want Info DIAGNOSTICS_TRUNCATED", summary.Severity, summary.Code)
+	}
+	want := fmt.Sprintf("%d additional diagnostic(s) suppressed", len(all)-2)
+	if summary.Message != want {
+		t.Errorf("summary.Message = %q, want %q", summary.Message, want)
+	}
+}
+
+func TestWithMaxDiagnostics_UnderCap(t *testing.T) {
+	_, diags := New(WithMaxDiagnostics(5)).Parse("123 Main St, Springfield")
+	for _, d := range diags {
+		if d.Code == "DIAGNOSTICS_TRUNCATED" {
+			t.Errorf("unexpected truncation summary: %v", diags)
+		}
+	}
+}
+
+func TestWithAggressiveSplitting(t *testing.T) {
+	input := "123 Main St APT4B62704 Springfield IL"
+
+	parsed, diags := New(WithAggressiveSplitting(true)).Parse(input)
+	if parsed.SecondaryUnit != "APT" || parsed.SecondaryNumber != "4B" {
+		t.Errorf("secondary = %q %q, want APT 4B", parsed.SecondaryUnit, parsed.SecondaryNumber)
+	}
+	if parsed.ZIPCode != "62704" {
+		t.Errorf("ZIPCode = %q, want %q", parsed.ZIPCode, "62704")
+	}
+	if parsed.City != "SPRINGFIELD" || parsed.State != "IL" {
+		t.Errorf("city/state = %q %q, want SPRINGFIELD IL", parsed.City, parsed.State)
+	}
+
+	var split *Diagnostic
+	for i := range diags {
+		if diags[i].Code == "SPLIT_GLUED_TOKENS" {
+			split = &diags[i]
+		}
+	}
+	if split == nil {
+		t.Fatalf("expected SPLIT_GLUED_TOKENS diagnostic, got %v", diags)
+	}
+	if split.Severity != SeverityInfo {
+		t.Errorf("Severity = %v, want Info", split.Severity)
+	}
+	if got := input[split.Start:split.End]; got != "APT4B62704" {
+		t.Errorf("span = %q, want %q", got, "APT4B62704")
+	}
+}
+
+func TestWithAggressiveSplitting_Disabled(t *testing.T) {
+	input := "123 Main St APT4B62704 Springfield IL"
+
+	parsed, diags := Parse(input)
+	if parsed.ZIPCode != "" || parsed.SecondaryUnit != "" {
+		t.Errorf("expected glued token to be left alone, got ZIP %q secondary %q", parsed.ZIPCode, parsed.SecondaryUnit)
+	}
+	for _, d := range diags {
+		if d.Code == "SPLIT_GLUED_TOKENS" {
+			t.Errorf("unexpected SPLIT_GLUED_TOKENS diagnostic when disabled")
+		}
+	}
+}
+
+func TestWithAggressiveSplitting_NormalInputUntouched(t *testing.T) {
+	tests := []string{
+		"123 Main St Apt 4B, Springfield, IL 62704",
+		"123 Main St Ste 100, Springfield, IL 62704-1234",
+		"12345 Oak Ave, Springfield, IL",
+	}
+
+	for _, input := range tests {
+		want, wantDiags := Parse(input)
+		got, gotDiags := New(WithAggressiveSplitting(true)).Parse(input)
+		if got.ToAddressRequest().String() != want.ToAddressRequest().String() {
+			t.Errorf("Parse(%q) = %q, want %q", input, got.ToAddressRequest().String(), want.ToAddressRequest().String())
+		}
+		if len(gotDiags) != len(wantDiags) {
+			t.Errorf("Parse(%q) diagnostics = %v, want %v", input, gotDiags, wantDiags)
+		}
+	}
+}
+
+func TestWithDedupeSecondary(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		wantDup  string
+		wantSpan string
+	}{
+		{
+			name:     "comma separated duplicate",
+			input:    "123 Main St, Unit 1, Unit 1, Springfield, IL 62704",
+			wantDup:  "UNIT 1",
+			wantSpan: "Unit 1",
+		},
+		{
+			name:     "inline duplicate with abbreviation",
+			input:    "123 Main St Apt 4B Apartment 4B, Springfield, IL 62704",
+			wantDup:  "APT 4B",
+			wantSpan: "Apartment 4B",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed, diags := New(WithDedupeSecondary(true)).Parse(tt.input)
+
+			designators := 0
+			for _, token := range parsed.Tokens {
+				if token.Type == TokenSecondaryDesignator {
+					designators++
+				}
+			}
+			if designators != 1 {
+				t.Errorf("got %d secondary designator tokens, want 1", designators)
+			}
+			if got := parsed.ToAddressRequest().SecondaryAddress; got != tt.wantDup {
+				t.Errorf("SecondaryAddress = %q, want %q", got, tt.wantDup)
+			}
+
+			var dup *Diagnostic
+			for i := range diags {
+				if diags[i].Code == "DUPLICATE_SECONDARY" {
+					dup = &diags[i]
+				}
+			}
+			if dup == nil {
+				t.Fatalf("expected DUPLICATE_SECONDARY diagnostic, got %v", diags)
+			}
+			if dup.Severity != SeverityInfo {
+				t.Errorf("Severity = %v, want Info", dup.Severity)
+			}
+			if got := tt.input[dup.Start:dup.End]; got != tt.wantSpan {
+				t.Errorf("span = %q, want %q", got, tt.wantSpan)
+			}
+		})
+	}
+}
+
+func TestWithDedupeSecondary_DistinctChain(t *testing.T) {
+	input := "123 Main St Unit 1 Ste 2, Springfield, IL 62704"
+
+	want, _ := Parse(input)
+	parsed, diags := New(WithDedupeSecondary(true)).Parse(input)
+
+	if len(parsed.Tokens) != len(want.Tokens) {
+		t.Errorf("got %d tokens, want %d: distinct secondaries must be kept", len(parsed.Tokens), len(want.Tokens))
+	}
+	for _, d := range diags {
+		if d.Code == "DUPLICATE_SECONDARY" {
+			t.Errorf("unexpected DUPLICATE_SECONDARY diagnostic for distinct secondaries")
+		}
+	}
+}
+
+func TestWithDedupeSecondary_Disabled(t *testing.T) {
+	parsed, diags := Parse("123 Main St, Unit 1, Unit 1, Springfield, IL 62704")
+
+	designators := 0
+	for _, token := range parsed.Tokens {
+		if token.Type == TokenSecondaryDesignator {
+			designators++
+		}
+	}
+	if designators != 2 {
+		t.Errorf("got %d secondary designator tokens, want 2 when disabled", designators)
+	}
+	for _, d := range diags {
+		if d.Code == "DUPLICATE_SECONDARY" {
+			t.Errorf("unexpected DUPLICATE_SECONDARY diagnostic when disabled")
+		}
+	}
+}
+
+func TestWithDiagnosticLocale(t *testing.T) {
+	tests := []struct {
+		name            string
+		lang            string
+		code            string
+		input           string
+		wantMessage     string
+		wantRemediation string
+	}{
+		{
+			name:            "spanish missing ZIP",
+			lang:            "es",
+			code:            "MISSING_ZIP",
+			input:           "123 Main St, San Juan, PR",
+			wantMessage:     "Falta el código ZIP",
+			wantRemediation: "Agregue un código ZIP de 5 dígitos para una mejor validación de la dirección",
+		},
+		{
+			name:            "spanish incomplete secondary with region",
+			lang:            "es-PR",
+			code:            "INCOMPLETE_SECONDARY",
+			input:           "123 Main St Apt, San Juan, PR 00901",
+			wantMessage:     "La unidad secundaria Apt no tiene número",
+			wantRemediation: "Agregue el número de la unidad después del designador (p. ej., APT 4B) o elimine el designador",
+		},
+		{
+			name:            "spanish suspicious characters keeps the count",
+			lang:            "es",
+			code:            "SUSPICIOUS_CHARACTERS",
+			input:           "123 Ma\u200bin St\u200b, Springfield, IL 62704",
+			wantMessage:     "Se eliminaron 2 caracteres invisibles o de control de la entrada",
+			wantRemediation: "Elimine los caracteres de ancho cero o de control, que suelen introducirse al copiar y pegar",
+		},
+		{
+			name:            "unsupported locale falls back to English",
+			lang:            "fr",
+			code:            "MISSING_ZIP",
+			input:           "123 Main St, San Juan, PR",
+			wantMessage:     "Missing ZIP code",
+			wantRemediation: "Add a 5-digit ZIP code for better address validation",
+		},
+		{
+			name:            "english",
+			lang:            "en-US",
+			code:            "MISSING_ZIP",
+			input:           "123 Main St, San Juan, PR",
+			wantMessage:     "Missing ZIP code",
+			wantRemediation: "Add a 5-digit ZIP code for better address validation",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := New(WithDiagnosticLocale(tt.lang))
+			_, diagnostics := p.Parse(tt.input)
+
+			var found *Diagnostic
+			for i := range diagnostics {
+				if diagnostics[i].Code == tt.code {
+					found = &diagnostics[i]
+					break
+				}
+			}
+			if found == nil {
+				t.Fatalf("no %s diagnostic in %+v", tt.code, diagnostics)
+			}
+			if found.Message != tt.wantMessage {
+				t.Errorf("Message = %q, want %q", found.Message, tt.wantMessage)
+			}
+			if found.Remediation != tt.wantRemediation {
+				t.Errorf("Remediation = %q, want %q", found.Remediation, tt.wantRemediation)
+			}
+		})
+	}
+}
+
+func TestWithDiagnosticLocale_FormatArguments(t *testing.T) {
+	p := New(WithDiagnosticLocale("es"), WithMaxDiagnostics(1))
+	_, diagnostics := p.Parse("Main St Apt")
+
+	last := diagnostics[len(diagnostics)-1]
+	if last.Code != "DIAGNOSTICS_TRUNCATED" {
+		t.Fatalf("last diagnostic = %s, want DIAGNOSTICS_TRUNCATED", last.Code)
+	}
+	_, all := Parse("Main St Apt")
+	want := fmt.Sprintf("Se omitieron %d diagnósticos adicionales", len(all)-1)
+	if last.Message != want {
+		t.Errorf("Message = %q, want %q", last.Message, want)
+	}
+
+	fields := New(WithDiagnosticLocale("es")).RequestDiagnostics(&models.AddressRequest{StreetAddress: "123 Main St", State: "ILL", City: "Springfield"})
+	if len(fields) != 1 {
+		t.Fatalf("got %d request diagnostics, want 1: %v", len(fields), fields)
+	}
+	if want := "El campo state no es válido: must be a 2-letter state code"; fields[0].Message != want {
+		t.Errorf("Message = %q, want %q", fields[0].Message, want)
+	}
+	if want := "Corrija el campo state antes de enviar la solicitud"; fields[0].Remediation != want {
+		t.Errorf("Remediation = %q, want %q", fields[0].Remediation, want)
+	}
+}
+
+func TestDiagnosticCatalogs_CoverAllCodes(t *testing.T) {
+	entries, err := os.ReadDir(".")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	codePattern := regexp.MustCompile(`Code:\s*"([A-Z0-9_]+)"`)
+	codes := make(map[string]bool)
+	for _, entry := range entries {
+		name := entry.Name()
+		if !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		src, err := os.ReadFile(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, m := range codePattern.FindAllStringSubmatch(string(src), -1) {
+			codes[m[1]] = true
+		}
+	}
+	if len(codes) == 0 {
+		t.Fatal("found no diagnostic codes in the package source")
+	}
+
+	for lang, catalog := range diagnosticCatalogs {
+		for code := range codes {
+			if _, ok := catalog[code]; !ok {
+				t.Errorf("catalog %q has no entry for diagnostic code %s", lang, code)
+			}
+		}
+	}
+}
+
+func TestWithDiagnosticLocale_CodesStable(t *testing.T) {
+	input := "Main St Apt"
+	_, english := Parse(input)
+	_, spanish := New(WithDiagnosticLocale("es")).Parse(input)
+
+	if len(spanish) != len(english) {
+		t.Fatalf("got %d diagnostics, want %d", len(spanish), len(english))
+	}
+	for i := range english {
+		if spanish[i].Code != english[i].Code {
+			t.Errorf("diagnostic %d: Code = %q, want %q", i, spanish[i].Code, english[i].Code)
+		}
+		if spanish[i].Message == english[i].Message {
+			t.Errorf("diagnostic %d (%s): message was not translated: %q", i, english[i].Code, spanish[i].Message)
+		}
+	}
+}
+
+func TestWithFastPathForNormalized(t *testing.T) {
+	inputs := []string{
+		"123 N MAIN ST, SPRINGFIELD, IL 62701",
+		"123 NORTH MAIN STREET APARTMENT 4B, SPRINGFIELD, ILLINOIS 62701-1234",
+		"ACME WIDGETS INC, 350 5TH AVENUE SUITE 3300, NEW YORK, NY 10118",
+		"1600 PENNSYLVANIA AVE NW,WASHINGTON,DC 20500",
+		"123 MAIN ST # 12, SPRINGFIELD, IL 62704 USA",
+		"PO BOX 500, SPRINGFIELD, IL",
+		"123 MAIN ST",
+		// Not normalized; these take the normal path
+		"123 Main St, Springfield, IL 62701",
+		"123 MAIN ST.  SPRINGFIELD IL 62701",
+		" 123 MAIN ST, SPRINGFIELD, IL 62701 ",
+	}
+
+	normal := New()
+	fast := New(WithFastPathForNormalized(true))
+	for _, input := range inputs {
+		t.Run(input, func(t *testing.T) {
+			wantParsed, wantDiags := normal.Parse(input)
+			gotParsed, gotDiags := fast.Parse(input)
+			if !reflect.DeepEqual(gotParsed, wantParsed) {
+				t.Errorf("fast path parsed = %+v, want %+v", gotParsed, wantParsed)
+			}
+			if !reflect.DeepEqual(gotDiags, wantDiags) {
+				t.Errorf("fast path diagnostics = %+v, want %+v", gotDiags, wantDiags)
+			}
+		})
+	}
+}
+
+func TestNormalizedInputMapping(t *testing.T) {
+	tests := []struct {
+		input  string
+		wantOK bool
+	}{
+		{"123 N MAIN ST, SPRINGFIELD, IL 62701", true},
+		{"123 MAIN ST,SPRINGFIELD,IL 62701", true},
+		{"123 MAIN ST # 4B, SPRINGFIELD, IL 62701-1234", true},
+		{"", false},
+		{"123 Main St", false},
+		{"123 MAIN ST.", false},
+		{"123 MAIN ST; SPRINGFIELD", false},
+		{"123  MAIN ST", false},
+		{" 123 MAIN ST", false},
+		{"123 MAIN ST ", false},
+		{"123 MAIN ST ,SPRINGFIELD", false},
+		{"123 MAIN ST,, SPRINGFIELD", false},
+		{"123 MAIN ST,", false},
+		{"123 MAIN ST\tSPRINGFIELD", false},
+		{"123 MAIN ST\u200b", false},
+		{"123 CALLE ÑANDÚ", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, gotMap, ok := normalizedInputMapping(tt.input)
+			if ok != tt.wantOK {
+				t.Fatalf("ok = %v, want %v", ok, tt.wantOK)
+			}
+			if !ok {
+				return
+			}
+			want, wantMap := normalizeInputWithMapping(tt.input)
+			if got != want {
+				t.Errorf("normalized = %q, want %q", got, want)
+			}
+			if !reflect.DeepEqual(gotMap, wantMap) {
+				t.Errorf("positionMap = %v, want %v", gotMap, wantMap)
+			}
+		})
+	}
+}
+
+func BenchmarkParse_Normalized(b *testing.B) {
+	const input = "123 N MAIN ST APT 4B, SPRINGFIELD, IL 62701-1234"
+	b.Run("normal", func(b *testing.B) {
+		p := New()
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			p.Parse(input)
+		}
+	})
+	b.Run("fast path", func(b *testing.B) {
+		p := New(WithFastPathForNormalized(true))
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			p.Parse(input)
+		}
+	})
+}
+
+func TestWithMaxSecondarySegments(t *testing.T) {
+	var chain strings.Builder
+	chain.WriteString("123 Main St")
+	for i := 1; i <= 1000; i++ {
+		fmt.Fprintf(&chain, " Unit %d", i)
+	}
+	chain.WriteString(", Springfield, IL 62701")
+	longChain := chain.String()
+
+	tests := []struct {
+		name          string
+		opts          []Option
+		input         string
+		wantSecondary string
+		wantDropped   bool
+	}{
+		{
+			name:          "default cap on very long chain",
+			input:         longChain,
+			wantSecondary: "UNIT 1 UNIT 2 UNIT 3 UNIT 4",
+			wantDropped:   true,
+		},
+		{
+			name:          "custom cap on very long chain",
+			opts:          []Option{WithMaxSecondarySegments(2)},
+			input:         longChain,
+			wantSecondary: "UNIT 1 UNIT 2",
+			wantDropped:   true,
+		},
+		{
+			name:          "zero restores default",
+			opts:          []Option{WithMaxSecondarySegments(0)},
+			input:         longChain,
+			wantSecondary: "UNIT 1 UNIT 2 UNIT 3 UNIT 4",
+			wantDropped:   true,
+		},
+		{
+			name:          "chain within cap",
+			input:         "123 Main St, Bldg 5, Ste 200, Rm 3, Springfield, IL 62701",
+			wantSecondary: "BLDG 5 STE 200 RM 3",
+		},
+		{
+			name:          "designator without number counts as a unit",
+			opts:          []Option{WithMaxSecondarySegments(1)},
+			input:         "123 Main St Bldg 5 Rear, Springfield, IL 62701",
+			wantSecondary: "BLDG 5",
+			wantDropped:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed, diagnostics := New(tt.opts...).Parse(tt.input)
+
+			if got := parsed.ToAddressRequest().SecondaryAddress; got != tt.wantSecondary {
+				t.Errorf("SecondaryAddress = %q, want %q", got, tt.wantSecondary)
+			}
+			if parsed.City != "SPRINGFIELD" {
+				t.Errorf("City = %q, want %q", parsed.City, "SPRINGFIELD")
+			}
+
+			var truncated []Diagnostic
+			for _, d := range diagnostics {
+				if d.Code == "SECONDARY_SEGMENTS_TRUNCATED" {
+					truncated = append(truncated, d)
+				}
+			}
+			if !tt.wantDropped {
+				if len(truncated) != 0 {
+					t.Errorf("unexpected SECONDARY_SEGMENTS_TRUNCATED diagnostic: %+v", truncated)
+				}
+				return
+			}
+			if len(truncated) != 1 {
+				t.Fatalf("got %d SECONDARY_SEGMENTS_TRUNCATED diagnostics, want 1", len(truncated))
+			}
+			d := truncated[0]
+			if d.Severity != SeverityWarning {
+				t.Errorf("Severity = %v, want %v", d.Severity, SeverityWarning)
+			}
+			if d.Start >= d.End || d.End > len(tt.input) {
+				t.Errorf("span [%d, %d) is not within the input", d.Start, d.End)
+			}
+		})
+	}
+}
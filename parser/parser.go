@@ -1,52 +1,253 @@
 package parser
 
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
 // Parser coordinates the tokenization, normalization, validation, and formatting pipeline.
 type Parser struct {
 	tokenizer  *Tokenizer
 	normalizer *Normalizer
 	validator  *Validator
+
+	hashDesignator       HashDesignatorMode
+	territoryNames       bool
+	maxDiagnostics       int
+	aggressiveSplitting  bool
+	dedupeSecondary      bool
+	diagnosticLocale     string
+	stateLookup          CityStateLookup
+	fastPathNormalized   bool
+	maxSecondarySegments int
 }
 
-// New creates a new Parser with default configuration.
-func New() *Parser {
-	return &Parser{
-		tokenizer:  newTokenizer(),
-		normalizer: newNormalizer(),
-		validator:  newValidator(),
+// New creates a new Parser. Without options the parser uses the default
+// configuration; pass options to customize its behavior.
+func New(opts ...Option) *Parser {
+	p := &Parser{
+		tokenizer:      newTokenizer(),
+		normalizer:     newNormalizer(),
+		validator:      newValidator(),
+		hashDesignator: HashKeep,
 	}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p
 }
 
+// defaultParser is the Parser with the default configuration used by the
+// package-level functions. A Parser is not modified after New returns, so it
+// is safe to share between goroutines.
+var defaultParser = New()
+
 // Parse parses a free-form address string into a structured ParsedAddress.
 // It tokenizes the input, applies USPS standardization rules, and validates
 // the address components. Returns the parsed address and any diagnostics
 // (warnings or errors) encountered during parsing.
 func Parse(input string) (*ParsedAddress, []Diagnostic) {
-	p := New()
-	return p.Parse(input)
+	return defaultParser.Parse(input)
 }
 
 // Parse parses a free-form address string using this parser instance.
 func (p *Parser) Parse(input string) (*ParsedAddress, []Diagnostic) {
+	// Set aside a phone number pasted alongside the address
+	text, phone, phoneDiagnostic, hasPhone := extractPhone(input)
+
 	// Tokenize
-	tokens := p.tokenizer.tokenize(input)
+	tokens := p.tokenize(text)
+	tokens, splitDiagnostics := p.splitGluedTokens(tokens)
+	tokens, dupDiagnostics := p.removeDuplicateSecondary(tokens)
+	tokens, segDiagnostics := p.limitSecondarySegments(tokens)
+	tokens = p.applyTerritoryNames(tokens)
 
 	// Normalize
 	normalizedTokens, normDiagnostics := p.normalizer.normalize(tokens)
 
 	// Build ParsedAddress
-	parsed := p.buildParsedAddress(normalizedTokens, input)
+	parsed, cityStart := p.buildParsedAddress(normalizedTokens, input)
+	parsed.Phone = phone
+	p.applyHashDesignator(parsed)
+
+	// Drop designators that are missing their unit number
+	secDiagnostics := p.resolveIncompleteSecondary(parsed)
+
+	// Look up a missing state from the ZIP code, if configured
+	inferDiagnostics := p.inferState(input, parsed)
 
 	// Validate
 	valDiagnostics := p.validator.validate(parsed)
 
 	// Combine diagnostics
-	diagnostics := append(normDiagnostics, valDiagnostics...)
+	var diagnostics []Diagnostic
+	if hasPhone {
+		diagnostics = append(diagnostics, phoneDiagnostic)
+	}
+	diagnostics = append(diagnostics, splitDiagnostics...)
+	diagnostics = append(diagnostics, dupDiagnostics...)
+	diagnostics = append(diagnostics, segDiagnostics...)
+	diagnostics = append(diagnostics, normDiagnostics...)
+	diagnostics = append(diagnostics, secDiagnostics...)
+	diagnostics = append(diagnostics, inferDiagnostics...)
+	diagnostics = append(diagnostics, valDiagnostics...)
+
+	if d, ok := suspiciousCharactersDiagnostic(input); ok {
+		diagnostics = append(diagnostics, d)
+	}
+
+	if d, ok := segmentationDiagnostic(input, parsed, cityStart); ok {
+		diagnostics = append(diagnostics, d)
+	}
+
+	if d, ok := leadingNameDiagnostic(parsed); ok {
+		diagnostics = append(diagnostics, d)
+	}
+
+	parsed.Confidence = p.validator.confidence(parsed, diagnostics)
+
+	return parsed, p.localizeDiagnostics(input, p.limitDiagnostics(diagnostics))
+}
+
+// isSuffixAsStreetName reports whether the street suffix at index i is actually
+// part of a lettered street name such as "AVENUE B" or "AVENUE N". This is the
+// case when the suffix directly follows the house number (or a pre-directional)
+// with no street name before it, and a single letter follows it. The letter is
+// kept as-is even if it could be read as a directional.
+func isSuffixAsStreetName(tokens []Token, i, streetNameParts int) bool {
+	if streetNameParts > 0 || i == 0 || i+1 >= len(tokens) {
+		return false
+	}
+	prev := tokens[i-1].Type
+	if prev != TokenHouseNumber && prev != TokenPreDirectional {
+		return false
+	}
+	letter := tokens[i+1].Original
+	return len(letter) == 1 && letter[0] >= 'A' && letter[0] <= 'Z'
+}
+
+// isUnknownSecondary reports whether the street name token at index i is a
+// word used as a secondary unit designator, such as "FLAT" in
+// "123 MAIN ST FLAT 4": it is directly followed by a number in the same comma
+// segment. The validator reports these as UNKNOWN_SECONDARY.
+func isUnknownSecondary(tokens []Token, i int, input string) bool {
+	if i+1 >= len(tokens) || tokens[i+1].Type != TokenHouseNumber {
+		return false
+	}
+	return !commaBetween(input, tokens[i], tokens[i+1])
+}
+
+// resolveIncompleteSecondary clears a secondary unit designator that requires a
+// unit number but has none (e.g. the trailing "Apt" in "123 Main St Apt") so
+// that an empty secondary is not sent to USPS, and reports it as a warning.
+func (p *Parser) resolveIncompleteSecondary(addr *ParsedAddress) []Diagnostic {
+	if addr.SecondaryUnit == "" || addr.SecondaryNumber != "" {
+		return nil
+	}
+	if !p.tokenizer.lexicon.RequiresSecondaryNumber(addr.SecondaryUnit) {
+		return nil
+	}
+
+	d := Diagnostic{
+		Severity:    SeverityWarning,
+		Message:     fmt.Sprintf("Secondary unit designator %s has no unit number", addr.SecondaryUnit),
+		Code:        "INCOMPLETE_SECONDARY",
+		Remediation: "Add the unit number after the designator (e.g., APT 4B) or remove the designator",
+	}
+	for _, token := range addr.Tokens {
+		if token.Type == TokenSecondaryDesignator && token.Value == addr.SecondaryUnit {
+			d.Start, d.End = token.Start, token.End
+			break
+		}
+	}
 
-	return parsed, diagnostics
+	addr.SecondaryUnit = ""
+	return []Diagnostic{d}
 }
 
-// buildParsedAddress constructs a ParsedAddress from normalized tokens.
-func (p *Parser) buildParsedAddress(tokens []Token, originalInput string) *ParsedAddress {
+// suspiciousCharactersDiagnostic reports invisible formatting or control
+// characters in the input. These characters are stripped by the tokenizer
+// (see isSuspiciousRune), so the diagnostic spans from the first to the last
+// occurrence to help callers locate the corrupted region.
+func suspiciousCharactersDiagnostic(input string) (Diagnostic, bool) {
+	count := 0
+	start, end := -1, -1
+	for i, r := range input {
+		if !isSuspiciousRune(r) {
+			continue
+		}
+		if start < 0 {
+			start = i
+		}
+		end = i + utf8.RuneLen(r)
+		count++
+	}
+	if count == 0 {
+		return Diagnostic{}, false
+	}
+	return Diagnostic{
+		Severity:    SeverityWarning,
+		Message:     fmt.Sprintf("Removed %d invisible or control character(s) from input", count),
+		Start:       start,
+		End:         end,
+		Remediation: "Remove zero-width or control characters, which are often introduced by copy and paste",
+		Code:        "SUSPICIOUS_CHARACTERS",
+		args:        [2]string{strconv.Itoa(count)},
+	}, true
+}
+
+// leadingNameDiagnostic reports a leading segment that markLeadingFirm left out
+// of the firm because it has no business marker, such as the recipient name in
+// "John Smith, 123 Main St, Springfield, IL 62704".
+func leadingNameDiagnostic(parsed *ParsedAddress) (Diagnostic, bool) {
+	for _, token := range parsed.Tokens {
+		if token.Type != TokenUnknown {
+			continue
+		}
+		return Diagnostic{
+			Severity:    SeverityInfo,
+			Message:     fmt.Sprintf("Ignored leading name %s, which has no business marker such as INC or LLC", token.Value),
+			Start:       token.Start,
+			End:         token.End,
+			Remediation: "If this is a business, include its designator (e.g., INC, LLC, CORP) or set the firm separately",
+			Code:        "LEADING_NAME_IGNORED",
+		}, true
+	}
+	return Diagnostic{}, false
+}
+
+// segmentationDiagnostic reports when component boundaries had to be inferred.
+// Input without any comma delimiters (e.g. "123 MAIN ST SPRINGFIELD IL 62704"),
+// or with no comma between the street and the city (e.g. "123 MAIN ST
+// SPRINGFIELD, IL 62704"), is segmented using the state and ZIP anchors and
+// known street suffix and secondary designator tokens rather than explicit
+// separators. cityStart is the index in parsed.Tokens of the first city token,
+// or -1 if there is none.
+func segmentationDiagnostic(input string, parsed *ParsedAddress, cityStart int) (Diagnostic, bool) {
+	if parsed.State == "" && parsed.ZIPCode == "" {
+		return Diagnostic{}, false
+	}
+	if strings.Contains(input, ",") {
+		if cityStart < 1 || commaBetween(input, parsed.Tokens[cityStart-1], parsed.Tokens[cityStart]) {
+			return Diagnostic{}, false
+		}
+	}
+	return Diagnostic{
+		Severity: SeverityInfo,
+		Message:  "Address segments were inferred from the state and ZIP code because no comma separates the street from the city",
+		Start:    0,
+		End:      len(input),
+		Code:     "INFERRED_SEGMENTATION",
+	}, true
+}
+
+// buildParsedAddress constructs a ParsedAddress from normalized tokens. It also
+// returns the index of the first token assigned to the city, or -1 if none was.
+func (p *Parser) buildParsedAddress(tokens []Token, originalInput string) (*ParsedAddress, int) {
 	addr := &ParsedAddress{
 		Tokens:        tokens,
 		OriginalInput: originalInput,
@@ -55,9 +256,12 @@ func (p *Parser) buildParsedAddress(tokens []Token, originalInput string) *Parse
 	// Track what we've seen to handle ordering
 	var streetNameParts []string
 	var cityParts []string
+	cityStart := -1
 	seenStreetSuffix := false
 	seenSecondaryDesignator := false
 	seenState := false
+	streetClosed := false
+	var extraSecondaryParts []string
 
 	// Find state index to help identify city
 	stateIndex := -1
@@ -68,16 +272,22 @@ func (p *Parser) buildParsedAddress(tokens []Token, originalInput string) *Parse
 		}
 	}
 
-	for i, token := range tokens {
+	for i := 0; i < len(tokens); i++ {
+		token := tokens[i]
+
+		// A comma after the street name ends the street, so a street with no
+		// suffix, such as "1 CALLE SOL, SAN JUAN", does not absorb the city
+		if i > 0 && len(streetNameParts) > 0 && commaBetween(originalInput, tokens[i-1], token) {
+			streetClosed = true
+		}
+
 		switch token.Type {
 		case TokenHouseNumber:
 			// If we've seen a state, this is probably a ZIP code
 			if seenState && addr.HouseNumber != "" {
 				// Treat as ZIP code if it's 5 or 9 digits
 				if len(token.Value) == 5 || len(token.Value) == 9 {
-					if addr.ZIPCode == "" {
-						addr.ZIPCode = token.Value
-					}
+					setZIP(addr, token.Value)
 				}
 			} else if addr.HouseNumber == "" {
 				addr.HouseNumber = token.Value
@@ -94,14 +304,34 @@ func (p *Parser) buildParsedAddress(tokens []Token, originalInput string) *Parse
 			// If we have a state and this token is right before it, it's city
 			if stateIndex >= 0 && i == stateIndex-1 {
 				cityParts = append(cityParts, token.Value)
-			} else if !seenStreetSuffix && !seenSecondaryDesignator {
+				if cityStart < 0 {
+					cityStart = i
+				}
+			} else if seenStreetSuffix && !seenState && addr.SecondaryUnit == "" && isUnknownSecondary(tokens, i, originalInput) {
+				// "FLAT 4": keep a word used as a unit designator, and its
+				// number, as the secondary unit rather than the city
+				addr.SecondaryUnit = token.Value
+				addr.SecondaryNumber = tokens[i+1].Value
+				i++
+				continue
+			} else if !seenStreetSuffix && !seenSecondaryDesignator && !streetClosed {
 				// Before street suffix or secondary designator = street name
 				streetNameParts = append(streetNameParts, token.Value)
-			} else if seenStreetSuffix || seenSecondaryDesignator {
+			} else {
 				// After street components = city
 				cityParts = append(cityParts, token.Value)
+				if cityStart < 0 {
+					cityStart = i
+				}
 			}
 		case TokenStreetSuffix:
+			if isSuffixAsStreetName(tokens, i, len(streetNameParts)) {
+				// "AVENUE B": the suffix word and the letter form the street name
+				streetNameParts = append(streetNameParts, token.Value, tokens[i+1].Original)
+				seenStreetSuffix = true
+				i++
+				continue
+			}
 			addr.StreetSuffix = token.Value
 			seenStreetSuffix = true
 		case TokenPostDirectional:
@@ -111,23 +341,29 @@ func (p *Parser) buildParsedAddress(tokens []Token, originalInput string) *Parse
 		case TokenSecondaryDesignator:
 			if addr.SecondaryUnit == "" {
 				addr.SecondaryUnit = token.Value
+			} else if addr.SecondaryNumber != "" || len(extraSecondaryParts) > 0 {
+				// A chained unit such as the suite in "BLDG 5, STE 200"
+				extraSecondaryParts = append(extraSecondaryParts, token.Value)
 			}
 			seenSecondaryDesignator = true
 		case TokenSecondaryNumber:
-			if addr.SecondaryNumber == "" {
+			if len(extraSecondaryParts) > 0 {
+				extraSecondaryParts = append(extraSecondaryParts, token.Value)
+			} else if addr.SecondaryNumber == "" {
 				addr.SecondaryNumber = token.Value
 			}
 		case TokenCity:
 			cityParts = append(cityParts, token.Value)
+			if cityStart < 0 {
+				cityStart = i
+			}
 		case TokenState:
 			if addr.State == "" {
 				addr.State = token.Value
 			}
 			seenState = true
 		case TokenZIPCode:
-			if addr.ZIPCode == "" {
-				addr.ZIPCode = token.Value
-			}
+			setZIP(addr, token.Value)
 		case TokenZIPPlus4:
 			if addr.ZIPPlus4 == "" {
 				addr.ZIPPlus4 = token.Value
@@ -136,6 +372,8 @@ func (p *Parser) buildParsedAddress(tokens []Token, originalInput string) *Parse
 			if addr.Firm == "" {
 				addr.Firm = token.Value
 			}
+		case TokenCountry:
+			addr.Country = token.Value
 		}
 	}
 
@@ -144,10 +382,33 @@ func (p *Parser) buildParsedAddress(tokens []Token, originalInput string) *Parse
 		addr.StreetName = joinTokens(streetNameParts)
 	}
 
+	if len(extraSecondaryParts) > 0 {
+		addr.SecondaryExtra = joinTokens(extraSecondaryParts)
+	}
+
 	// Join city parts
 	if len(cityParts) > 0 {
 		addr.City = joinTokens(cityParts)
 	}
 
-	return addr
+	return addr, cityStart
+}
+
+// setZIP stores a 5- or 9-digit ZIP code on addr unless one is already set,
+// splitting a 9-digit ZIP into ZIPCode and ZIPPlus4. ZIP codes are handled
+// only as strings and must never be converted to numbers, which would drop
+// the leading zeros of ZIPs such as 02101 (Boston), 00901 (San Juan), and
+// 09123 (APO).
+func setZIP(addr *ParsedAddress, zip string) {
+	if addr.ZIPCode != "" {
+		return
+	}
+	if len(zip) == 9 {
+		addr.ZIPCode = zip[:5]
+		if addr.ZIPPlus4 == "" {
+			addr.ZIPPlus4 = zip[5:]
+		}
+		return
+	}
+	addr.ZIPCode = zip
 }
@@ -1,7 +1,11 @@
 package parser
 
 import (
+	"encoding/json"
+	"strings"
 	"testing"
+
+	"github.com/my-eq/go-usps/models"
 )
 
 func TestParse_SimpleAddress(t *testing.T) {
@@ -306,6 +310,55 @@ func TestParsedAddress_ToAddressRequest(t *testing.T) {
 	}
 }
 
+func TestParse_StreetComponents(t *testing.T) {
+	parsed, _ := Parse("123 N Main St")
+
+	tests := []struct {
+		field string
+		got   string
+		want  string
+	}{
+		{"HouseNumber", parsed.HouseNumber, "123"},
+		{"PreDirectional", parsed.PreDirectional, "N"},
+		{"StreetName", parsed.StreetName, "MAIN"},
+		{"StreetSuffix", parsed.StreetSuffix, "ST"},
+		{"PostDirectional", parsed.PostDirectional, ""},
+		{"StreetAddress", parsed.ToAddressRequest().StreetAddress, "123 N MAIN ST"},
+	}
+
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s = %q, want %q", tt.field, tt.got, tt.want)
+		}
+	}
+}
+
+func TestParsedAddress_ToAddressRequestJSON(t *testing.T) {
+	parsed, _ := Parse("123 N Main St Apt 4B, New York, NY 10001")
+
+	got, err := parsed.ToAddressRequestJSON()
+	if err != nil {
+		t.Fatalf("ToAddressRequestJSON() error = %v", err)
+	}
+
+	want, _ := json.Marshal(&models.AddressRequest{
+		StreetAddress:    "123 N MAIN ST",
+		SecondaryAddress: "APT 4B",
+		City:             "NEW YORK",
+		State:            "NY",
+		ZIPCode:          "10001",
+	})
+	if string(got) != string(want) {
+		t.Errorf("ToAddressRequestJSON() = %s, want %s", got, want)
+	}
+
+	// Empty optional fields are omitted
+	wantLiteral := `{"streetAddress":"123 N MAIN ST","secondaryAddress":"APT 4B","city":"NEW YORK","state":"NY","ZIPCode":"10001"}`
+	if string(got) != wantLiteral {
+		t.Errorf("ToAddressRequestJSON() = %s, want %s", got, wantLiteral)
+	}
+}
+
 func TestParser_New(t *testing.T) {
 	p := New()
 	if p == nil {
@@ -324,3 +377,1274 @@ func TestParser_New(t *testing.T) {
 		t.Error("validator is nil")
 	}
 }
+
+func TestParse_CommaLessInput(t *testing.T) {
+	tests := []struct {
+		name          string
+		input         string
+		wantStreet    string
+		wantSecondary string
+		wantCity      string
+		wantState     string
+		wantZIP       string
+		wantZIPPlus4  string
+	}{
+		{
+			name:       "street city state zip",
+			input:      "123 MAIN ST SPRINGFIELD IL 62704",
+			wantStreet: "123 MAIN ST",
+			wantCity:   "SPRINGFIELD",
+			wantState:  "IL",
+			wantZIP:    "62704",
+		},
+		{
+			name:          "with apartment",
+			input:         "123 Main St Apt 4B Springfield IL 62704",
+			wantStreet:    "123 MAIN ST",
+			wantSecondary: "APT 4B",
+			wantCity:      "SPRINGFIELD",
+			wantState:     "IL",
+			wantZIP:       "62704",
+		},
+		{
+			name:          "with suite and ZIP+4",
+			input:         "456 Oak Ave Suite 200 Boston MA 02101-1234",
+			wantStreet:    "456 OAK AVE",
+			wantSecondary: "STE 200",
+			wantCity:      "BOSTON",
+			wantState:     "MA",
+			wantZIP:       "02101",
+			wantZIPPlus4:  "1234",
+		},
+		{
+			name:       "multi-word city",
+			input:      "789 Elm Blvd Los Angeles CA 90001",
+			wantStreet: "789 ELM BLVD",
+			wantCity:   "LOS ANGELES",
+			wantState:  "CA",
+			wantZIP:    "90001",
+		},
+		{
+			name:          "secondary before multi-word city",
+			input:         "123 N Main St Ste 200 New York NY 10001",
+			wantStreet:    "123 N MAIN ST",
+			wantSecondary: "STE 200",
+			wantCity:      "NEW YORK",
+			wantState:     "NY",
+			wantZIP:       "10001",
+		},
+		{
+			name:          "post-directional, secondary, and state-name city",
+			input:         "123 Main St NW Apt 2 Washington DC 20001",
+			wantStreet:    "123 MAIN ST NW",
+			wantSecondary: "APT 2",
+			wantCity:      "WASHINGTON",
+			wantState:     "DC",
+			wantZIP:       "20001",
+		},
+		{
+			name:       "city starting with a state name",
+			input:      "1 Main St Kansas City MO 64101",
+			wantStreet: "1 MAIN ST",
+			wantCity:   "KANSAS CITY",
+			wantState:  "MO",
+			wantZIP:    "64101",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed, diagnostics := Parse(tt.input)
+			req := parsed.ToAddressRequest()
+
+			if req.StreetAddress != tt.wantStreet {
+				t.Errorf("StreetAddress = %q, want %q", req.StreetAddress, tt.wantStreet)
+			}
+			if req.SecondaryAddress != tt.wantSecondary {
+				t.Errorf("SecondaryAddress = %q, want %q", req.SecondaryAddress, tt.wantSecondary)
+			}
+			if req.City != tt.wantCity {
+				t.Errorf("City = %q, want %q", req.City, tt.wantCity)
+			}
+			if req.State != tt.wantState {
+				t.Errorf("State = %q, want %q", req.State, tt.wantState)
+			}
+			if req.ZIPCode != tt.wantZIP {
+				t.Errorf("ZIPCode = %q, want %q", req.ZIPCode, tt.wantZIP)
+			}
+			if req.ZIPPlus4 != tt.wantZIPPlus4 {
+				t.Errorf("ZIPPlus4 = %q, want %q", req.ZIPPlus4, tt.wantZIPPlus4)
+			}
+
+			found := false
+			for _, d := range diagnostics {
+				if d.Code == "INFERRED_SEGMENTATION" {
+					found = true
+					if d.Severity != SeverityInfo {
+						t.Errorf("Severity = %v, want %v", d.Severity, SeverityInfo)
+					}
+				} else {
+					t.Errorf("unexpected diagnostic %s: %s", d.Code, d.Message)
+				}
+			}
+			if !found {
+				t.Error("expected INFERRED_SEGMENTATION diagnostic")
+			}
+		})
+	}
+}
+
+func TestParse_CommaDelimitedNotInferred(t *testing.T) {
+	_, diagnostics := Parse("123 Main St, Springfield, IL 62704")
+
+	for _, d := range diagnostics {
+		if d.Code == "INFERRED_SEGMENTATION" {
+			t.Errorf("unexpected INFERRED_SEGMENTATION diagnostic for comma-delimited input")
+		}
+	}
+}
+
+func TestParse_PartialCommasInferred(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		wantCity string
+		want     bool
+	}{
+		{"no comma before city", "123 Main St Springfield, IL 62704", "SPRINGFIELD", true},
+		{"secondary before city", "123 Main St Apt 4 Springfield, IL 62704", "SPRINGFIELD", true},
+		{"comma before city only", "123 Main St, Springfield IL 62704", "SPRINGFIELD", false},
+		{"comma-delimited secondary", "123 Main St, Apt 4, Springfield, IL 62704", "SPRINGFIELD", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed, diagnostics := Parse(tt.input)
+
+			if parsed.City != tt.wantCity {
+				t.Errorf("City = %q, want %q", parsed.City, tt.wantCity)
+			}
+			found := false
+			for _, d := range diagnostics {
+				if d.Code == "INFERRED_SEGMENTATION" {
+					found = true
+				}
+			}
+			if found != tt.want {
+				t.Errorf("INFERRED_SEGMENTATION = %v, want %v", found, tt.want)
+			}
+		})
+	}
+}
+
+func TestParse_SuspiciousCharacters(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		wantStart int
+		wantEnd   int
+	}{
+		{
+			name:      "zero-width space in street name",
+			input:     "123 Ma\u200bin St, Springfield, IL 62704",
+			wantStart: 6,
+			wantEnd:   9,
+		},
+		{
+			name:      "byte order mark and control character",
+			input:     "\ufeff123 Main St, Spring\x07field, IL 62704",
+			wantStart: 0,
+			wantEnd:   23,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed, diagnostics := Parse(tt.input)
+			req := parsed.ToAddressRequest()
+
+			if req.StreetAddress != "123 MAIN ST" {
+				t.Errorf("StreetAddress = %q, want %q", req.StreetAddress, "123 MAIN ST")
+			}
+			if req.City != "SPRINGFIELD" {
+				t.Errorf("City = %q, want %q", req.City, "SPRINGFIELD")
+			}
+			if parsed.OriginalInput != tt.input {
+				t.Errorf("OriginalInput = %q, want %q", parsed.OriginalInput, tt.input)
+			}
+
+			if len(diagnostics) != 1 {
+				t.Fatalf("got %d diagnostics, want 1: %+v", len(diagnostics), diagnostics)
+			}
+			d := diagnostics[0]
+			if d.Code != "SUSPICIOUS_CHARACTERS" {
+				t.Errorf("Code = %q, want %q", d.Code, "SUSPICIOUS_CHARACTERS")
+			}
+			if d.Severity != SeverityWarning {
+				t.Errorf("Severity = %v, want %v", d.Severity, SeverityWarning)
+			}
+			if d.Start != tt.wantStart || d.End != tt.wantEnd {
+				t.Errorf("span = [%d, %d), want [%d, %d)", d.Start, d.End, tt.wantStart, tt.wantEnd)
+			}
+		})
+	}
+}
+
+func TestParse_IncompleteSecondary(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		wantCity  string
+		wantState string
+		wantDiag  string
+	}{
+		{
+			name:      "dangling apt before city",
+			input:     "123 Main St Apt, Springfield, IL 62704",
+			wantCity:  "SPRINGFIELD",
+			wantState: "IL",
+			wantDiag:  "Apt",
+		},
+		{
+			name:      "dangling suite before city",
+			input:     "123 Main St Ste, Springfield, IL 62704",
+			wantCity:  "SPRINGFIELD",
+			wantState: "IL",
+			wantDiag:  "Ste",
+		},
+		{
+			name:      "dangling floor at end of input",
+			input:     "123 Main St Fl",
+			wantCity:  "",
+			wantState: "",
+			wantDiag:  "Fl",
+		},
+		{
+			name:      "dangling room at end of input",
+			input:     "123 Main St Rm",
+			wantCity:  "",
+			wantState: "",
+			wantDiag:  "Rm",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed, diagnostics := Parse(tt.input)
+			req := parsed.ToAddressRequest()
+
+			if req.StreetAddress != "123 MAIN ST" {
+				t.Errorf("StreetAddress = %q, want %q", req.StreetAddress, "123 MAIN ST")
+			}
+			if req.SecondaryAddress != "" {
+				t.Errorf("SecondaryAddress = %q, want empty", req.SecondaryAddress)
+			}
+			if req.City != tt.wantCity {
+				t.Errorf("City = %q, want %q", req.City, tt.wantCity)
+			}
+			if req.State != tt.wantState {
+				t.Errorf("State = %q, want %q", req.State, tt.wantState)
+			}
+
+			var found *Diagnostic
+			for i := range diagnostics {
+				if diagnostics[i].Code == "INCOMPLETE_SECONDARY" {
+					found = &diagnostics[i]
+				}
+			}
+			if found == nil {
+				t.Fatal("expected INCOMPLETE_SECONDARY diagnostic")
+			}
+			if found.Severity != SeverityWarning {
+				t.Errorf("Severity = %v, want %v", found.Severity, SeverityWarning)
+			}
+			if got := tt.input[found.Start:found.End]; got != tt.wantDiag {
+				t.Errorf("diagnostic span = %q, want %q", got, tt.wantDiag)
+			}
+		})
+	}
+}
+
+func TestParse_StandaloneSecondaryDesignator(t *testing.T) {
+	parsed, diagnostics := Parse("123 Main St Rear, Springfield, IL 62704")
+
+	if parsed.SecondaryUnit != "REAR" {
+		t.Errorf("SecondaryUnit = %q, want %q", parsed.SecondaryUnit, "REAR")
+	}
+	if parsed.City != "SPRINGFIELD" {
+		t.Errorf("City = %q, want %q", parsed.City, "SPRINGFIELD")
+	}
+	for _, d := range diagnostics {
+		t.Errorf("unexpected diagnostic %s: %s", d.Code, d.Message)
+	}
+}
+
+func TestParse_SingleLetterStreetNames(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      string
+		wantStreet string
+		wantPre    string
+		wantPost   string
+		wantCity   string
+	}{
+		{
+			name:       "suffix before letter",
+			input:      "123 Avenue B, Springfield, IL 62704",
+			wantStreet: "123 AVE B",
+			wantCity:   "SPRINGFIELD",
+		},
+		{
+			name:       "directional letter after suffix",
+			input:      "123 Avenue N, Brooklyn, NY 11230",
+			wantStreet: "123 AVE N",
+			wantCity:   "BROOKLYN",
+		},
+		{
+			name:       "letter before suffix",
+			input:      "123 C St, Springfield, IL 62704",
+			wantStreet: "123 C ST",
+			wantCity:   "SPRINGFIELD",
+		},
+		{
+			name:       "lettered avenue without commas",
+			input:      "123 Avenue B New York NY 10009",
+			wantStreet: "123 AVE B",
+			wantCity:   "NEW YORK",
+		},
+		{
+			name:       "lettered avenue with pre-directional",
+			input:      "123 E Avenue K, Lancaster, CA 93535",
+			wantStreet: "123 E AVE K",
+			wantPre:    "E",
+			wantCity:   "LANCASTER",
+		},
+		{
+			name:       "control with real post-directional",
+			input:      "123 Main St N, Springfield, IL 62704",
+			wantStreet: "123 MAIN ST N",
+			wantPost:   "N",
+			wantCity:   "SPRINGFIELD",
+		},
+		{
+			name:       "control with real pre-directional",
+			input:      "123 N Main St, Springfield, IL 62704",
+			wantStreet: "123 N MAIN ST",
+			wantPre:    "N",
+			wantCity:   "SPRINGFIELD",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed, diagnostics := Parse(tt.input)
+			req := parsed.ToAddressRequest()
+
+			if req.StreetAddress != tt.wantStreet {
+				t.Errorf("StreetAddress = %q, want %q", req.StreetAddress, tt.wantStreet)
+			}
+			if parsed.PreDirectional != tt.wantPre {
+				t.Errorf("PreDirectional = %q, want %q", parsed.PreDirectional, tt.wantPre)
+			}
+			if parsed.PostDirectional != tt.wantPost {
+				t.Errorf("PostDirectional = %q, want %q", parsed.PostDirectional, tt.wantPost)
+			}
+			if req.City != tt.wantCity {
+				t.Errorf("City = %q, want %q", req.City, tt.wantCity)
+			}
+			for _, d := range diagnostics {
+				if d.Severity != SeverityInfo {
+					t.Errorf("unexpected diagnostic %s: %s", d.Code, d.Message)
+				}
+			}
+		})
+	}
+}
+
+func TestParse_TwoSegmentInput(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      string
+		wantStreet string
+		wantCity   string
+		wantState  string
+		wantZIP    string
+		wantCodes  []string
+	}{
+		{
+			name:       "street and ZIP",
+			input:      "123 Main St, 62704",
+			wantStreet: "123 MAIN ST",
+			wantZIP:    "62704",
+			wantCodes:  []string{"MISSING_STATE"},
+		},
+		{
+			name:       "street and state",
+			input:      "123 Main St, IL",
+			wantStreet: "123 MAIN ST",
+			wantState:  "IL",
+			wantCodes:  []string{"MISSING_CITY", "MISSING_ZIP"},
+		},
+		{
+			name:       "street and city",
+			input:      "123 Main St, Springfield",
+			wantStreet: "123 MAIN ST",
+			wantCity:   "SPRINGFIELD",
+			wantCodes:  []string{"MISSING_STATE", "MISSING_ZIP"},
+		},
+		{
+			name:       "street and state ZIP",
+			input:      "123 Main St, IL 62704",
+			wantStreet: "123 MAIN ST",
+			wantState:  "IL",
+			wantZIP:    "62704",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed, diags := Parse(tt.input)
+			req := parsed.ToAddressRequest()
+
+			if req.StreetAddress != tt.wantStreet {
+				t.Errorf("StreetAddress = %q, want %q", req.StreetAddress, tt.wantStreet)
+			}
+			if req.City != tt.wantCity {
+				t.Errorf("City = %q, want %q", req.City, tt.wantCity)
+			}
+			if req.State != tt.wantState {
+				t.Errorf("State = %q, want %q", req.State, tt.wantState)
+			}
+			if req.ZIPCode != tt.wantZIP {
+				t.Errorf("ZIPCode = %q, want %q", req.ZIPCode, tt.wantZIP)
+			}
+
+			var codes []string
+			for _, d := range diags {
+				codes = append(codes, d.Code)
+			}
+			if strings.Join(codes, ",") != strings.Join(tt.wantCodes, ",") {
+				t.Errorf("codes = %v, want %v", codes, tt.wantCodes)
+			}
+		})
+	}
+}
+
+func TestParse_LeadingBuildingName(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      string
+		wantFirm   string
+		wantStreet string
+		wantCity   string
+	}{
+		{
+			name:       "building name",
+			input:      "Empire State Building, 350 5th Ave, New York, NY 10118",
+			wantFirm:   "EMPIRE STATE BUILDING",
+			wantStreet: "350 5TH AVE",
+			wantCity:   "NEW YORK",
+		},
+		{
+			name:       "business name",
+			input:      "Acme Corp, 123 Main St, Springfield, IL 62704",
+			wantFirm:   "ACME CORP",
+			wantStreet: "123 MAIN ST",
+			wantCity:   "SPRINGFIELD",
+		},
+		{
+			name:       "street-led address unaffected",
+			input:      "123 Main St, Springfield, IL 62704",
+			wantStreet: "123 MAIN ST",
+			wantCity:   "SPRINGFIELD",
+		},
+		{
+			name:       "business designator with period",
+			input:      "Smith & Sons Co., 10 Elm St, Springfield, IL 62704",
+			wantFirm:   "SMITH & SONS CO",
+			wantStreet: "10 ELM ST",
+			wantCity:   "SPRINGFIELD",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed, diags := Parse(tt.input)
+			req := parsed.ToAddressRequest()
+
+			if req.Firm != tt.wantFirm {
+				t.Errorf("Firm = %q, want %q", req.Firm, tt.wantFirm)
+			}
+			if req.StreetAddress != tt.wantStreet {
+				t.Errorf("StreetAddress = %q, want %q", req.StreetAddress, tt.wantStreet)
+			}
+			if req.City != tt.wantCity {
+				t.Errorf("City = %q, want %q", req.City, tt.wantCity)
+			}
+			if len(diags) != 0 {
+				t.Errorf("unexpected diagnostics: %v", diags)
+			}
+		})
+	}
+}
+
+func TestParse_LeadingPersonalName(t *testing.T) {
+	input := "John Smith, 123 Main St, Springfield, IL 62704"
+	parsed, diags := Parse(input)
+	req := parsed.ToAddressRequest()
+
+	if req.Firm != "" {
+		t.Errorf("Firm = %q, want empty", req.Firm)
+	}
+	if req.StreetAddress != "123 MAIN ST" {
+		t.Errorf("StreetAddress = %q, want %q", req.StreetAddress, "123 MAIN ST")
+	}
+	if req.City != "SPRINGFIELD" {
+		t.Errorf("City = %q, want %q", req.City, "SPRINGFIELD")
+	}
+
+	if len(diags) != 1 {
+		t.Fatalf("got %d diagnostics, want 1: %v", len(diags), diags)
+	}
+	d := diags[0]
+	if d.Code != "LEADING_NAME_IGNORED" || d.Severity != SeverityInfo {
+		t.Errorf("diagnostic = %s/%v, want LEADING_NAME_IGNORED/Info", d.Code, d.Severity)
+	}
+	if got := input[d.Start:d.End]; got != "John Smith" {
+		t.Errorf("diagnostic span = %q, want %q", got, "John Smith")
+	}
+	if parsed.Confidence != 1 {
+		t.Errorf("Confidence = %v, want 1", parsed.Confidence)
+	}
+}
+
+func TestParse_TrailingCountry(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		wantCity    string
+		wantZIP     string
+		wantCountry string
+	}{
+		{"USA", "123 Main St, Springfield, IL 62704, USA", "SPRINGFIELD", "62704", "USA"},
+		{"dotted", "123 Main St, Springfield, IL 62704, U.S.A.", "SPRINGFIELD", "62704", "USA"},
+		{"United States", "123 Main St, Springfield, IL 62704 United States", "SPRINGFIELD", "62704", "USA"},
+		{"after state", "123 Main St, Springfield, IL, USA", "SPRINGFIELD", "", "USA"},
+		{"Canada", "123 Main St, Buffalo, NY 14201, Canada", "BUFFALO", "14201", "CAN"},
+		{"city named Mexico", "100 Main St, Mexico, MO 65265", "MEXICO", "65265", ""},
+		{"none", "123 Main St, Springfield, IL 62704", "SPRINGFIELD", "62704", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed, _ := Parse(tt.input)
+			req := parsed.ToAddressRequest()
+
+			if req.City != tt.wantCity {
+				t.Errorf("City = %q, want %q", req.City, tt.wantCity)
+			}
+			if req.ZIPCode != tt.wantZIP {
+				t.Errorf("ZIPCode = %q, want %q", req.ZIPCode, tt.wantZIP)
+			}
+			if parsed.Country != tt.wantCountry || req.Country != tt.wantCountry {
+				t.Errorf("Country = %q (request %q), want %q", parsed.Country, req.Country, tt.wantCountry)
+			}
+		})
+	}
+}
+
+func TestParse_TrailingCountryAfterRegion(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		wantCountry string
+	}{
+		{"Canadian province", "100 King St W, Toronto, ON M5H 2N2, Canada", "CAN"},
+		{"Mexican state", "Av Reforma 222, Juarez, CDMX 06600, Mexico", "MEX"},
+		{"second segment", "100 Main St, Mexico", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed, _ := Parse(tt.input)
+			if parsed.Country != tt.wantCountry {
+				t.Errorf("Country = %q, want %q", parsed.Country, tt.wantCountry)
+			}
+			if got := parsed.ToAddressRequest().IsDomestic(); got != (tt.wantCountry == "") {
+				t.Errorf("IsDomestic() = %v, want %v", got, tt.wantCountry == "")
+			}
+		})
+	}
+}
+
+func TestParse_PeriodsAfterSuffixAndDesignator(t *testing.T) {
+	tests := []struct {
+		input         string
+		wantStreet    string
+		wantSecondary string
+	}{
+		{"123 Main St. Apt. 5B, Springfield, IL 62704", "123 MAIN ST", "APT 5B"},
+		{"456 Oak Ave. Ste. 200, Boston, MA 02101", "456 OAK AVE", "STE 200"},
+		{"789 N. Elm Blvd. Unit. 12, Chicago, IL 60601", "789 N ELM BLVD", "UNIT 12"},
+		{"123 Main St.Apt.5B, Springfield, IL 62704", "123 MAIN ST", "APT 5B"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			parsed, diags := Parse(tt.input)
+			req := parsed.ToAddressRequest()
+
+			if req.StreetAddress != tt.wantStreet {
+				t.Errorf("StreetAddress = %q, want %q", req.StreetAddress, tt.wantStreet)
+			}
+			if req.SecondaryAddress != tt.wantSecondary {
+				t.Errorf("SecondaryAddress = %q, want %q", req.SecondaryAddress, tt.wantSecondary)
+			}
+			if len(diags) != 0 {
+				t.Errorf("unexpected diagnostics: %v", diags)
+			}
+		})
+	}
+}
+
+func TestParse_DepartmentDesignator(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"inline", "123 Main St Dept 5, Springfield, IL 62704"},
+		{"standalone segment", "123 Main St, Dept 5, Springfield, IL 62704"},
+		{"spelled out", "123 Main St Department 5, Springfield, IL 62704"},
+		{"with period", "123 Main St, Dept. 5, Springfield, IL 62704"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed, diags := Parse(tt.input)
+			req := parsed.ToAddressRequest()
+
+			if req.StreetAddress != "123 MAIN ST" {
+				t.Errorf("StreetAddress = %q, want %q", req.StreetAddress, "123 MAIN ST")
+			}
+			if req.SecondaryAddress != "DEPT 5" {
+				t.Errorf("SecondaryAddress = %q, want %q", req.SecondaryAddress, "DEPT 5")
+			}
+			if req.City != "SPRINGFIELD" {
+				t.Errorf("City = %q, want %q", req.City, "SPRINGFIELD")
+			}
+			if len(diags) != 0 {
+				t.Errorf("unexpected diagnostics: %v", diags)
+			}
+		})
+	}
+}
+
+func TestParse_OriginalInputVerbatim(t *testing.T) {
+	inputs := []string{
+		"123 Main St, Springfield, IL 62704",
+		"  123 n. main street,  apt #4b ; springfield IL 62704-1234  ",
+		"Empire State Building, 350 5th Ave, New York, NY 10118",
+		"123 Main St, Springfield, IL 62704, (555) 123-4567",
+		"123 Main\u200b St, Springfield, IL 62704",
+		"",
+	}
+
+	for _, input := range inputs {
+		parsed, _ := Parse(input)
+		if parsed.OriginalInput != input {
+			t.Errorf("OriginalInput = %q, want %q", parsed.OriginalInput, input)
+		}
+	}
+
+	input := "123 Main St APT4B62704"
+	parsed, _ := New(WithAggressiveSplitting(true), WithDedupeSecondary(true), WithTerritoryNames()).Parse(input)
+	if parsed.OriginalInput != input {
+		t.Errorf("OriginalInput with options = %q, want %q", parsed.OriginalInput, input)
+	}
+}
+
+func TestParsedAddress_LabelLines(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{
+			name:  "residential",
+			input: "123 North Main Street, Springfield, IL 62704",
+			want:  []string{"123 N MAIN ST", "SPRINGFIELD, IL 62704"},
+		},
+		{
+			name:  "firm and secondary",
+			input: "Acme Widgets Inc, 350 5th Ave Suite 3300, New York, NY 10118-0110",
+			want:  []string{"ACME WIDGETS INC", "350 5TH AVE STE 3300", "NEW YORK, NY 10118-0110"},
+		},
+		{
+			name:  "missing ZIP",
+			input: "456 Oak Ave Apt 2, Boston, MA",
+			want:  []string{"456 OAK AVE APT 2", "BOSTON, MA"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed, _ := Parse(tt.input)
+			got := parsed.LabelLines()
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("LabelLines() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	var nilAddr *ParsedAddress
+	if got := nilAddr.LabelLines(); len(got) != 0 {
+		t.Errorf("LabelLines() on nil = %q, want empty", got)
+	}
+}
+
+func TestParse_ChainedSecondary(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		wantUnit  string
+		wantExtra string
+		want      string
+	}{
+		{"building and suite segments", "123 Main St, Building 5, Suite 200, Springfield, IL 62704", "BLDG", "STE 200", "BLDG 5 STE 200"},
+		{"floor and room segments", "123 Main St, Floor 3, Room 12, Springfield, IL 62704", "FL", "RM 12", "FL 3 RM 12"},
+		{"inline chain", "123 Main St Bldg 5 Ste 200, Springfield, IL 62704", "BLDG", "STE 200", "BLDG 5 STE 200"},
+		{"single secondary", "123 Main St, Suite 200, Springfield, IL 62704", "STE", "", "STE 200"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed, diags := Parse(tt.input)
+			req := parsed.ToAddressRequest()
+
+			if parsed.SecondaryUnit != tt.wantUnit {
+				t.Errorf("SecondaryUnit = %q, want %q", parsed.SecondaryUnit, tt.wantUnit)
+			}
+			if parsed.SecondaryExtra != tt.wantExtra {
+				t.Errorf("SecondaryExtra = %q, want %q", parsed.SecondaryExtra, tt.wantExtra)
+			}
+			if req.SecondaryAddress != tt.want {
+				t.Errorf("SecondaryAddress = %q, want %q", req.SecondaryAddress, tt.want)
+			}
+			if req.StreetAddress != "123 MAIN ST" {
+				t.Errorf("StreetAddress = %q, want %q", req.StreetAddress, "123 MAIN ST")
+			}
+			if req.City != "SPRINGFIELD" {
+				t.Errorf("City = %q, want %q", req.City, "SPRINGFIELD")
+			}
+			if len(diags) != 0 {
+				t.Errorf("unexpected diagnostics: %v", diags)
+			}
+		})
+	}
+}
+
+func TestParse_StateNameInStreet(t *testing.T) {
+	parsed, diags := Parse("123 Virginia Ave, Arlington, VA 22201")
+	req := parsed.ToAddressRequest()
+
+	if req.StreetAddress != "123 VIRGINIA AVE" {
+		t.Errorf("StreetAddress = %q, want %q", req.StreetAddress, "123 VIRGINIA AVE")
+	}
+	if req.City != "ARLINGTON" || req.State != "VA" {
+		t.Errorf("City, State = %q, %q, want ARLINGTON, VA", req.City, req.State)
+	}
+	if len(diags) != 0 {
+		t.Errorf("unexpected diagnostics: %v", diags)
+	}
+}
+
+func TestParse_ZIPLeadingZeros(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		wantZIP   string
+		wantPlus4 string
+		wantLast  string
+	}{
+		{"boston", "1 Beacon St, Boston, MA 02101", "02101", "", "BOSTON, MA 02101"},
+		{"boston zip+4", "1 Beacon St, Boston, MA 02101-0042", "02101", "0042", "BOSTON, MA 02101-0042"},
+		{"boston 9 digits", "1 Beacon St, Boston, MA 021010042", "02101", "0042", "BOSTON, MA 02101-0042"},
+		{"boston without commas", "1 Beacon St Boston MA 02101", "02101", "", "BOSTON, MA 02101"},
+		{"puerto rico", "1 Main St, San Juan, PR 00901", "00901", "", "SAN JUAN, PR 00901"},
+		{"puerto rico zip+4", "1 Main St, San Juan, PR 00901-0001", "00901", "0001", "SAN JUAN, PR 00901-0001"},
+		{"military", "PSC 1234 Box 5678, APO, AE 09123", "09123", "", ""},
+		{"military zip+4", "PSC 1234 Box 5678, APO, AE 09123-0001", "09123", "0001", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed, _ := Parse(tt.input)
+
+			if parsed.ZIPCode != tt.wantZIP {
+				t.Errorf("ZIPCode = %q, want %q", parsed.ZIPCode, tt.wantZIP)
+			}
+			if parsed.ZIPPlus4 != tt.wantPlus4 {
+				t.Errorf("ZIPPlus4 = %q, want %q", parsed.ZIPPlus4, tt.wantPlus4)
+			}
+
+			req := parsed.ToAddressRequest()
+			if req.ZIPCode != tt.wantZIP {
+				t.Errorf("ToAddressRequest().ZIPCode = %q, want %q", req.ZIPCode, tt.wantZIP)
+			}
+			if req.ZIPPlus4 != tt.wantPlus4 {
+				t.Errorf("ToAddressRequest().ZIPPlus4 = %q, want %q", req.ZIPPlus4, tt.wantPlus4)
+			}
+
+			if tt.wantLast != "" {
+				lines := parsed.LabelLines()
+				if got := lines[len(lines)-1]; got != tt.wantLast {
+					t.Errorf("last line = %q, want %q", got, tt.wantLast)
+				}
+			}
+		})
+	}
+}
+
+func TestParse_SpacedZIPPlus4(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		wantZIP   string
+		wantPlus4 string
+	}{
+		{"spaces around hyphen", "123 Main St, Springfield, IL 62704 - 1234", "62704", "1234"},
+		{"space before hyphen", "123 Main St, Springfield, IL 62704 -1234", "62704", "1234"},
+		{"double space", "123 Main St, Springfield, IL 62704  1234", "62704", "1234"},
+		{"single space", "123 Main St, Springfield, IL 62704 1234", "62704", "1234"},
+		{"trailing country", "123 Main St, Springfield, IL 62704 - 1234 USA", "62704", "1234"},
+		{"short extension", "123 Main St, Springfield, IL 62704 - 123", "62704", ""},
+		{"long extension", "123 Main St, Springfield, IL 62704 - 12345", "62704", ""},
+		{"short zip", "123 Main St, Springfield, IL 6270 - 1234", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed, _ := Parse(tt.input)
+
+			if parsed.ZIPCode != tt.wantZIP {
+				t.Errorf("ZIPCode = %q, want %q", parsed.ZIPCode, tt.wantZIP)
+			}
+			if parsed.ZIPPlus4 != tt.wantPlus4 {
+				t.Errorf("ZIPPlus4 = %q, want %q", parsed.ZIPPlus4, tt.wantPlus4)
+			}
+		})
+	}
+}
+
+// A letter attached to the house number is part of the USPS primary number,
+// with or without a hyphen, and is kept as written rather than split into a
+// secondary unit.
+func TestParse_LetteredHouseNumber(t *testing.T) {
+	tests := []struct {
+		name          string
+		input         string
+		wantHouse     string
+		wantStreet    string
+		wantSecondary string
+	}{
+		{"attached letter", "123A Main St, Springfield, IL 62704", "123A", "123A MAIN ST", ""},
+		{"hyphenated letter", "123-A Main St, Springfield, IL 62704", "123-A", "123-A MAIN ST", ""},
+		{"with unit", "123A Main St Apt 4B, Springfield, IL 62704", "123A", "123A MAIN ST", "APT 4B"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed, diagnostics := Parse(tt.input)
+
+			if parsed.HouseNumber != tt.wantHouse {
+				t.Errorf("HouseNumber = %q, want %q", parsed.HouseNumber, tt.wantHouse)
+			}
+			req := parsed.ToAddressRequest()
+			if req.StreetAddress != tt.wantStreet {
+				t.Errorf("StreetAddress = %q, want %q", req.StreetAddress, tt.wantStreet)
+			}
+			if req.SecondaryAddress != tt.wantSecondary {
+				t.Errorf("SecondaryAddress = %q, want %q", req.SecondaryAddress, tt.wantSecondary)
+			}
+			for _, d := range diagnostics {
+				if d.Code == "MISSING_STREET" {
+					t.Errorf("unexpected MISSING_STREET diagnostic")
+				}
+			}
+		})
+	}
+}
+
+func TestParse_DirectionalAsStreetName(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      string
+		wantStreet string
+		wantPreDir string
+		wantCodes  []string
+	}{
+		{"directional is the name", "123 North Ave, Springfield, IL 62704", "123 NORTH AVE", "", []string{"DIRECTIONAL_AS_NAME"}},
+		{"directional prefix", "123 North Main St, Springfield, IL 62704", "123 N MAIN ST", "N", nil},
+		{"with post-directional", "123 East St NW, Washington, DC 20001", "123 EAST ST NW", "", []string{"DIRECTIONAL_AS_NAME"}},
+		{"lettered avenue", "123 E Ave K, Lancaster, CA 93535", "123 E AVE K", "E", nil},
+		{"after a pre-directional", "123 W North Ave, Chicago, IL 60622", "123 W NORTH AVE", "W", []string{"DIRECTIONAL_AS_NAME"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed, diagnostics := Parse(tt.input)
+
+			if got := parsed.ToAddressRequest().StreetAddress; got != tt.wantStreet {
+				t.Errorf("StreetAddress = %q, want %q", got, tt.wantStreet)
+			}
+			if parsed.PreDirectional != tt.wantPreDir {
+				t.Errorf("PreDirectional = %q, want %q", parsed.PreDirectional, tt.wantPreDir)
+			}
+
+			var codes []string
+			for _, d := range diagnostics {
+				codes = append(codes, d.Code)
+				if d.Code == "DIRECTIONAL_AS_NAME" && d.Severity != SeverityInfo {
+					t.Errorf("DIRECTIONAL_AS_NAME severity = %v, want SeverityInfo", d.Severity)
+				}
+			}
+			if strings.Join(codes, ",") != strings.Join(tt.wantCodes, ",") {
+				t.Errorf("codes = %v, want %v", codes, tt.wantCodes)
+			}
+		})
+	}
+}
+
+func TestParse_TerritoryAddresses(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      string
+		wantStreet string
+		wantCity   string
+		wantState  string
+		wantZIP    string
+	}{
+		{"puerto rico", "1234 Calle Luna Apt 5, Bayamon, PR 00961", "1234 CALLE LUNA", "BAYAMON", "PR", "00961"},
+		{"san juan", "1 Calle Sol, San Juan, PR 00901", "1 CALLE SOL", "SAN JUAN", "PR", "00901"},
+		{"calle with two-word name", "52 Calle San Francisco, San Juan, PR 00901", "52 CALLE SAN FRANCISCO", "SAN JUAN", "PR", "00901"},
+		{"avenida", "1000 Avenida Ponce de Leon, San Juan, PR 00907", "1000 AVENIDA PONCE DE LEON", "SAN JUAN", "PR", "00907"},
+		{"puerto rico zip+4", "123 Main St, Mayaguez, PR 00680-1234", "123 MAIN ST", "MAYAGUEZ", "PR", "00680"},
+		{"virgin islands", "123 Main St, Christiansted, VI 00820", "123 MAIN ST", "CHRISTIANSTED", "VI", "00820"},
+		{"guam", "123 Marine Corps Dr, Hagatna, GU 96910", "123 MARINE CORPS DR", "HAGATNA", "GU", "96910"},
+		{"northern mariana islands", "789 Beach Rd, Saipan, MP 96950", "789 BEACH RD", "SAIPAN", "MP", "96950"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed, diagnostics := Parse(tt.input)
+
+			if len(diagnostics) != 0 {
+				t.Errorf("diagnostics = %v, want none", diagnostics)
+			}
+			if got := parsed.ToAddressRequest().StreetAddress; got != tt.wantStreet {
+				t.Errorf("StreetAddress = %q, want %q", got, tt.wantStreet)
+			}
+			if parsed.City != tt.wantCity {
+				t.Errorf("City = %q, want %q", parsed.City, tt.wantCity)
+			}
+			if parsed.State != tt.wantState {
+				t.Errorf("State = %q, want %q", parsed.State, tt.wantState)
+			}
+			if parsed.ZIPCode != tt.wantZIP {
+				t.Errorf("ZIPCode = %q, want %q", parsed.ZIPCode, tt.wantZIP)
+			}
+			if !parsed.IsTerritory() {
+				t.Errorf("IsTerritory() = false, want true")
+			}
+		})
+	}
+}
+
+func TestParsedAddress_IsTerritory(t *testing.T) {
+	tests := []struct {
+		name  string
+		state string
+		zip   string
+		want  bool
+	}{
+		{"puerto rico", "PR", "00901", true},
+		{"virgin islands", "VI", "00802", true},
+		{"guam", "GU", "96910", true},
+		{"northern mariana islands", "MP", "96950", true},
+		{"american samoa", "AS", "96799", true},
+		{"state", "NY", "10001", false},
+		{"state wins over zip", "MA", "00901", false},
+		{"territory zip without state", "", "00926", true},
+		{"guam zip without state", "", "96929", true},
+		{"freely associated state zip", "", "96941", false},
+		{"no state or zip", "", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed := &ParsedAddress{State: tt.state, ZIPCode: tt.zip}
+			if got := parsed.IsTerritory(); got != tt.want {
+				t.Errorf("IsTerritory() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	var nilAddr *ParsedAddress
+	if nilAddr.IsTerritory() {
+		t.Errorf("nil IsTerritory() = true, want false")
+	}
+}
+
+func TestParse_ConfidenceOrdering(t *testing.T) {
+	inputs := []struct {
+		name  string
+		input string
+	}{
+		{"complete", "123 Main St, Springfield, IL 62701"},
+		{"missing zip", "123 Main St, Springfield, IL"},
+		{"missing state", "123 Main St, Springfield 62701"},
+		{"street only", "123 Main St"},
+	}
+
+	prev := 1.1
+	for _, in := range inputs {
+		parsed, _ := Parse(in.input)
+		got := parsed.ConfidenceScore()
+		if got < 0 || got > 1 {
+			t.Errorf("%s: ConfidenceScore() = %v, want within [0, 1]", in.name, got)
+		}
+		if got >= prev {
+			t.Errorf("%s: ConfidenceScore() = %v, want less than %v", in.name, got, prev)
+		}
+		prev = got
+	}
+}
+
+func TestParse_Confidence(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  float64
+	}{
+		{"complete", "123 Main St, Springfield, IL 62701", 1.0},
+		{"complete with warning", "123 Main St Apt, Springfield, IL 62701", 0.95},
+		{"missing zip", "123 Main St, Springfield, IL", 0.85},
+		{"missing state", "123 Main St, Springfield 62701", 0.75},
+		{"street only", "123 Main St", 0.4},
+		{"empty", "", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed, _ := Parse(tt.input)
+			if parsed.Confidence != tt.want {
+				t.Errorf("Confidence = %v, want %v", parsed.Confidence, tt.want)
+			}
+		})
+	}
+
+	var nilAddr *ParsedAddress
+	if got := nilAddr.ConfidenceScore(); got != 0 {
+		t.Errorf("nil ConfidenceScore() = %v, want 0", got)
+	}
+}
+
+func TestParse_FractionalHouseNumber(t *testing.T) {
+	tests := []struct {
+		name            string
+		input           string
+		wantHouseNumber string
+		wantStreet      string
+		wantSecondary   string
+	}{
+		{"spaced fraction", "123 1/2 Main St, Springfield, IL 62701", "123 1/2", "123 1/2 MAIN ST", ""},
+		{"hyphenated fraction", "123-1/2 Main St, Springfield, IL 62701", "123 1/2", "123 1/2 MAIN ST", ""},
+		{"quarter", "45 3/4 Elm Ave, Springfield, IL 62701", "45 3/4", "45 3/4 ELM AVE", ""},
+		{"with directional and unit", "123 1/2 N Main St Apt 4, Springfield, IL 62701", "123 1/2", "123 1/2 N MAIN ST", "APT 4"},
+		{"without fraction", "123 Main St, Springfield, IL 62701", "123", "123 MAIN ST", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed, diagnostics := Parse(tt.input)
+
+			if parsed.HouseNumber != tt.wantHouseNumber {
+				t.Errorf("HouseNumber = %q, want %q", parsed.HouseNumber, tt.wantHouseNumber)
+			}
+			req := parsed.ToAddressRequest()
+			if req.StreetAddress != tt.wantStreet {
+				t.Errorf("StreetAddress = %q, want %q", req.StreetAddress, tt.wantStreet)
+			}
+			if req.SecondaryAddress != tt.wantSecondary {
+				t.Errorf("SecondaryAddress = %q, want %q", req.SecondaryAddress, tt.wantSecondary)
+			}
+			if parsed.City != "SPRINGFIELD" {
+				t.Errorf("City = %q, want %q", parsed.City, "SPRINGFIELD")
+			}
+			if len(diagnostics) != 0 {
+				t.Errorf("unexpected diagnostics: %+v", diagnostics)
+			}
+		})
+	}
+}
+
+func TestParse_GridHouseNumber(t *testing.T) {
+	tests := []struct {
+		name            string
+		input           string
+		wantHouseNumber string
+		wantPreDir      string
+		wantStreet      string
+		wantCity        string
+	}{
+		{"grid address", "N6W23001 BLUEMOUND RD, WAUKESHA, WI 53188", "N6W23001", "", "N6W23001 BLUEMOUND RD", "WAUKESHA"},
+		{"west then north", "W156N11500 Pilgrim Rd, Germantown, WI 53022", "W156N11500", "", "W156N11500 PILGRIM RD", "GERMANTOWN"},
+		{"lowercase without commas", "n6w23001 bluemound rd waukesha wi 53188", "N6W23001", "", "N6W23001 BLUEMOUND RD", "WAUKESHA"},
+		{"with directional", "S74W16853 S Janesville Rd, Muskego, WI 53150", "S74W16853", "S", "S74W16853 S JANESVILLE RD", "MUSKEGO"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed, diagnostics := Parse(tt.input)
+
+			if parsed.HouseNumber != tt.wantHouseNumber {
+				t.Errorf("HouseNumber = %q, want %q", parsed.HouseNumber, tt.wantHouseNumber)
+			}
+			if parsed.PreDirectional != tt.wantPreDir {
+				t.Errorf("PreDirectional = %q, want %q", parsed.PreDirectional, tt.wantPreDir)
+			}
+			if got := parsed.ToAddressRequest().StreetAddress; got != tt.wantStreet {
+				t.Errorf("StreetAddress = %q, want %q", got, tt.wantStreet)
+			}
+			if parsed.City != tt.wantCity {
+				t.Errorf("City = %q, want %q", parsed.City, tt.wantCity)
+			}
+			for _, d := range diagnostics {
+				if d.Severity == SeverityError {
+					t.Errorf("unexpected error diagnostic: %+v", d)
+				}
+			}
+		})
+	}
+}
+
+func TestParse_LabeledZIPCode(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		wantZIP   string
+		wantPlus4 string
+	}{
+		{"ZIP", "123 Main St, Springfield, IL ZIP 62704", "62704", ""},
+		{"ZIP with colon", "123 Main St, Springfield, IL ZIP: 62704", "62704", ""},
+		{"Zipcode", "123 Main St, Springfield, IL Zipcode 62704", "62704", ""},
+		{"Zip Code with ZIP+4", "123 Main St, Springfield, IL Zip Code: 62704-1234", "62704", "1234"},
+		{"Postal Code", "123 Main St, Springfield, IL Postal Code 62704", "62704", ""},
+		{"own segment", "123 Main St, Springfield, IL, Zip: 62704", "62704", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed, diagnostics := Parse(tt.input)
+
+			if parsed.ZIPCode != tt.wantZIP {
+				t.Errorf("ZIPCode = %q, want %q", parsed.ZIPCode, tt.wantZIP)
+			}
+			if parsed.ZIPPlus4 != tt.wantPlus4 {
+				t.Errorf("ZIPPlus4 = %q, want %q", parsed.ZIPPlus4, tt.wantPlus4)
+			}
+			if parsed.City != "SPRINGFIELD" {
+				t.Errorf("City = %q, want %q", parsed.City, "SPRINGFIELD")
+			}
+			if parsed.State != "IL" {
+				t.Errorf("State = %q, want %q", parsed.State, "IL")
+			}
+			if len(diagnostics) != 0 {
+				t.Errorf("unexpected diagnostics: %+v", diagnostics)
+			}
+		})
+	}
+
+	// A street named Zip is not a label
+	parsed, _ := Parse("123 Zip St, Springfield, IL 62704")
+	if got := parsed.ToAddressRequest().StreetAddress; got != "123 ZIP ST" {
+		t.Errorf("StreetAddress = %q, want %q", got, "123 ZIP ST")
+	}
+}
+
+func TestParse_DiagnosticRemediation(t *testing.T) {
+	tests := []struct {
+		name            string
+		input           string
+		code            string
+		wantRemediation string
+	}{
+		{"missing state", "123 Main St, Springfield 62704", "MISSING_STATE", "Add a 2-letter state code (e.g., NY, CA, TX)"},
+		{"missing zip", "123 Main St, Springfield, IL", "MISSING_ZIP", "Add a 5-digit ZIP code for better address validation"},
+		{"unknown secondary", "123 Main St Flat 4, Springfield, IL 62704", "UNKNOWN_SECONDARY", "Use a USPS-recognized unit designator such as APT, STE, or UNIT"},
+		{"directional as name", "123 North Ave, Springfield, IL 62704", "DIRECTIONAL_AS_NAME", "If NORTH is a direction rather than the street name, add the street name after it (e.g., N MAIN ST)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, diagnostics := Parse(tt.input)
+
+			var found *Diagnostic
+			for i := range diagnostics {
+				if diagnostics[i].Code == tt.code {
+					found = &diagnostics[i]
+				}
+			}
+			if found == nil {
+				t.Fatalf("no %s diagnostic in %+v", tt.code, diagnostics)
+			}
+			if found.Remediation == "" {
+				t.Fatalf("%s Remediation is empty", tt.code)
+			}
+			if found.Remediation != tt.wantRemediation {
+				t.Errorf("Remediation = %q, want %q", found.Remediation, tt.wantRemediation)
+			}
+		})
+	}
+}
+
+func TestParse_UnknownSecondary(t *testing.T) {
+	tests := []struct {
+		name          string
+		input         string
+		wantSpan      string
+		wantSecondary string
+	}{
+		{"after suffix", "123 Main St Flat 4, Springfield, IL 62704", "Flat", "FLAT 4"},
+		{"own segment", "123 Main St, Cabin 4, Springfield, IL 62704", "Cabin", "CABIN 4"},
+		{"no commas", "123 Main St Flat 4 Springfield IL 62704", "Flat", "FLAT 4"},
+		{"recognized designator", "123 Main St Apt 4, Springfield, IL 62704", "", "APT 4"},
+		{"no secondary", "123 N Main St, Springfield, IL 62704", "", " "},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed, diagnostics := Parse(tt.input)
+
+			if parsed.City != "SPRINGFIELD" {
+				t.Errorf("City = %q, want %q", parsed.City, "SPRINGFIELD")
+			}
+			if got := parsed.SecondaryUnit + " " + parsed.SecondaryNumber; got != tt.wantSecondary {
+				t.Errorf("secondary = %q, want %q", got, tt.wantSecondary)
+			}
+
+			var span string
+			for _, d := range diagnostics {
+				if d.Code == "UNKNOWN_SECONDARY" {
+					if d.Severity != SeverityWarning {
+						t.Errorf("Severity = %v, want %v", d.Severity, SeverityWarning)
+					}
+					span = tt.input[d.Start:d.End]
+				}
+			}
+			if span != tt.wantSpan {
+				t.Errorf("UNKNOWN_SECONDARY spans %q, want %q", span, tt.wantSpan)
+			}
+		})
+	}
+}
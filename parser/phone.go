@@ -0,0 +1,51 @@
+package parser
+
+import (
+	"regexp"
+	"strings"
+)
+
+// phonePattern matches a segment that is entirely a North American phone
+// number: an optional label (Phone, Tel, Ph, Cell, Mobile, or Fax), an
+// optional +1 or 1 country code, a 3-digit area code with or without
+// parentheses, and a 3-4 digit number separated by spaces, dots, or hyphens,
+// followed by an optional extension such as "x12" or "ext. 12". Examples:
+// "(555) 123-4567", "555.123.4567", "+1 555 123 4567", "Tel: 555-123-4567 ext 8".
+var phonePattern = regexp.MustCompile(`(?i)^(?:(?:phone|tel|ph|cell|mobile|fax)\.?:?\s*)?((?:\+?1[\s.-]?)?(?:\(\d{3}\)|\d{3})[\s.-]?\d{3}[\s.-]?\d{4}(?:\s*(?:x|ext\.?|extension)\s*\d{1,6})?)$`)
+
+// extractPhone finds the first comma-, semicolon-, or line-delimited segment
+// of input that matches phonePattern. It returns the input with that segment
+// blanked out by spaces, so token offsets still index the original input, and
+// the diagnostic reporting the removal. The phone number is returned as
+// written, without its label.
+func extractPhone(input string) (string, string, Diagnostic, bool) {
+	start := 0
+	for start <= len(input) {
+		end := strings.IndexAny(input[start:], ",;\n")
+		if end < 0 {
+			end = len(input)
+		} else {
+			end += start
+		}
+
+		segment := input[start:end]
+		text := strings.TrimSpace(segment)
+		if m := phonePattern.FindStringSubmatch(text); m != nil {
+			phone := m[1]
+			textStart := start + strings.Index(segment, text)
+			textEnd := textStart + len(text)
+			cleaned := input[:textStart] + strings.Repeat(" ", len(text)) + input[textEnd:]
+			return cleaned, phone, Diagnostic{
+				Severity:    SeverityInfo,
+				Message:     "Removed phone number " + phone + " from address input",
+				Start:       textStart,
+				End:         textEnd,
+				Remediation: "Enter the phone number in a separate field",
+				Code:        "PHONE_REMOVED",
+			}, true
+		}
+
+		start = end + 1
+	}
+	return input, "", Diagnostic{}, false
+}
@@ -0,0 +1,88 @@
+package parser
+
+import "testing"
+
+func TestParse_PhoneNumber(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		wantPhone string
+	}{
+		{"parenthesized area code", "123 Main St, Springfield, IL 62704, (555) 123-4567", "(555) 123-4567"},
+		{"hyphens", "123 Main St, Springfield, IL 62704, 555-123-4567", "555-123-4567"},
+		{"dots", "123 Main St, Springfield, IL 62704, 555.123.4567", "555.123.4567"},
+		{"country code", "123 Main St, Springfield, IL 62704, +1 555 123 4567", "+1 555 123 4567"},
+		{"digits only", "123 Main St, Springfield, IL 62704, 5551234567", "5551234567"},
+		{"extension", "123 Main St, Springfield, IL 62704, 555-123-4567 ext. 22", "555-123-4567 ext. 22"},
+		{"labeled leading line", "Tel: 555-123-4567\n123 Main St\nSpringfield, IL 62704", "555-123-4567"},
+		{"middle segment", "123 Main St, Phone 555-123-4567, Springfield, IL 62704", "555-123-4567"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed, diagnostics := Parse(tt.input)
+
+			if parsed.Phone != tt.wantPhone {
+				t.Errorf("Phone = %q, want %q", parsed.Phone, tt.wantPhone)
+			}
+			if got := parsed.HouseNumber + " " + parsed.StreetName + " " + parsed.StreetSuffix; got != "123 MAIN ST" {
+				t.Errorf("street = %q, want %q", got, "123 MAIN ST")
+			}
+			if parsed.City != "SPRINGFIELD" {
+				t.Errorf("City = %q, want %q", parsed.City, "SPRINGFIELD")
+			}
+			if parsed.State != "IL" || parsed.ZIPCode != "62704" {
+				t.Errorf("State, ZIP = %q, %q, want IL, 62704", parsed.State, parsed.ZIPCode)
+			}
+
+			var found bool
+			for _, d := range diagnostics {
+				if d.Severity > SeverityInfo {
+					t.Errorf("unexpected %s diagnostic %s: %s", d.Severity, d.Code, d.Message)
+				}
+				if d.Code == "PHONE_REMOVED" {
+					found = true
+				}
+			}
+			if !found {
+				t.Errorf("expected PHONE_REMOVED diagnostic, got %+v", diagnostics)
+			}
+		})
+	}
+}
+
+func TestParse_PhoneNumberSpan(t *testing.T) {
+	input := "123 Main St, Springfield, IL 62704, (555) 123-4567"
+	_, diagnostics := Parse(input)
+
+	for _, d := range diagnostics {
+		if d.Code != "PHONE_REMOVED" {
+			continue
+		}
+		if got := input[d.Start:d.End]; got != "(555) 123-4567" {
+			t.Errorf("span = %q, want %q", got, "(555) 123-4567")
+		}
+		return
+	}
+	t.Fatal("expected PHONE_REMOVED diagnostic")
+}
+
+func TestParse_NoPhoneNumber(t *testing.T) {
+	inputs := []string{
+		"123 Main St, Springfield, IL 62704-1234",
+		"5551234 Main St, Springfield, IL 62704",
+		"123 Main St, Springfield, IL 62704",
+	}
+
+	for _, input := range inputs {
+		parsed, diagnostics := Parse(input)
+		if parsed.Phone != "" {
+			t.Errorf("Parse(%q).Phone = %q, want empty", input, parsed.Phone)
+		}
+		for _, d := range diagnostics {
+			if d.Code == "PHONE_REMOVED" {
+				t.Errorf("Parse(%q) reported PHONE_REMOVED", input)
+			}
+		}
+	}
+}
@@ -21,7 +21,13 @@ func newTokenizer() *Tokenizer {
 func (t *Tokenizer) tokenize(input string) []Token {
 	// Normalize input while tracking original positions
 	normalized, positionMap := normalizeInputWithMapping(input)
+	return t.tokenizeNormalized(input, normalized, positionMap)
+}
 
+// tokenizeNormalized splits normalized input, as produced by
+// normalizeInputWithMapping, into tokens and classifies them. positionMap maps
+// each byte of normalized to its offset in input.
+func (t *Tokenizer) tokenizeNormalized(input, normalized string, positionMap []int) []Token {
 	// Split on common delimiters
 	parts := splitAddressParts(normalized)
 
@@ -39,9 +45,259 @@ func (t *Tokenizer) tokenize(input string) []Token {
 		position += len(part) + 1 // +1 for delimiter
 	}
 
+	tokens = joinSpacedZIPPlus4(tokens)
+	tokens = stripZIPLabels(tokens)
+	detachDanglingDesignators(tokens, input)
+	demoteInnerStates(tokens)
+	tokens = t.markLeadingFirm(tokens, input)
+	tokens = t.markTrailingCountry(tokens, input)
+
+	return tokens
+}
+
+// maxCountryWords is the word count of the longest country name in the lexicon.
+const maxCountryWords = 5
+
+// markTrailingCountry merges a country name at the end of the input, such as
+// "USA" or "United States", into a single TokenCountry token. The name must
+// directly follow the state or ZIP code, or be its own comma-separated
+// segment after at least two others, as in "100 King St W, Toronto,
+// ON M5H 2N2, Canada", so that city names such as "Mexico" are not mistaken
+// for countries.
+func (t *Tokenizer) markTrailingCountry(tokens []Token, input string) []Token {
+	for n := maxCountryWords; n >= 1; n-- {
+		start := len(tokens) - n
+		if start < 1 {
+			continue
+		}
+		switch tokens[start-1].Type {
+		case TokenState, TokenZIPCode, TokenZIPPlus4:
+		default:
+			if !commaBetween(input, tokens[start-1], tokens[start]) ||
+				strings.Count(input[:tokens[start].Start], ",") < 2 {
+				continue
+			}
+		}
+
+		words := make([]string, 0, n)
+		for _, token := range tokens[start:] {
+			words = append(words, token.Original)
+		}
+		name := strings.Join(words, " ")
+		code, ok := t.lexicon.NormalizeCountry(name)
+		if !ok {
+			continue
+		}
+
+		country := Token{
+			Type:     TokenCountry,
+			Value:    code,
+			Original: name,
+			Start:    tokens[start].Start,
+			End:      tokens[len(tokens)-1].End,
+		}
+		return append(tokens[:start:start], country)
+	}
+	return tokens
+}
+
+// joinSpacedZIPPlus4 recognizes a ZIP+4 extension separated from its ZIP
+// code by spaces, as in "62704 - 1234", "62704 -1234", or "62704  1234". The
+// ZIP code must have exactly five digits and the extension exactly four, and
+// only a trailing country name may follow, so house numbers and malformed
+// lengths are left alone.
+func joinSpacedZIPPlus4(tokens []Token) []Token {
+	for i := 0; i+1 < len(tokens); i++ {
+		if tokens[i].Type != TokenZIPCode || len(tokens[i].Original) != 5 {
+			continue
+		}
+
+		next := i + 1
+		hyphen := false
+		if tokens[next].Original == "-" && next+1 < len(tokens) {
+			next++
+			hyphen = true
+		}
+		ext := tokens[next].Original
+		start := tokens[next].Start
+		if !hyphen && len(ext) == 5 && ext[0] == '-' {
+			ext = ext[1:]
+			start++
+		}
+		if len(ext) != 4 || !isNumeric(ext) {
+			continue
+		}
+		if !onlyWordsFollow(tokens[next+1:]) {
+			continue
+		}
+
+		zip4 := Token{
+			Type:     TokenZIPPlus4,
+			Value:    ext,
+			Original: ext,
+			Start:    start,
+			End:      tokens[next].End,
+		}
+		rest := append([]Token{zip4}, tokens[next+1:]...)
+		return append(tokens[:i+1], rest...)
+	}
+	return tokens
+}
+
+// zipLabels are the labels form exports put before a ZIP code, as uppercased
+// words, longest first.
+var zipLabels = [][]string{
+	{"POSTAL", "CODE"},
+	{"ZIP", "CODE"},
+	{"ZIPCODE"},
+	{"ZIP"},
+}
+
+// stripZIPLabels removes a label such as "ZIP", "ZIP:", "ZIP CODE", or
+// "POSTAL CODE" directly before a ZIP code, as in "Springfield, IL ZIP 62704",
+// so the label is not absorbed into the city. A street named "Zip" is left
+// alone because it is not followed by a ZIP code.
+func stripZIPLabels(tokens []Token) []Token {
+	for i, token := range tokens {
+		if token.Type != TokenZIPCode {
+			continue
+		}
+		for _, label := range zipLabels {
+			start := i - len(label)
+			if start < 0 || !matchesLabel(tokens[start:i], label) {
+				continue
+			}
+			return append(tokens[:start], tokens[i:]...)
+		}
+	}
+	return tokens
+}
+
+// matchesLabel reports whether the words of tokens are label, allowing a colon
+// after the last word.
+func matchesLabel(tokens []Token, label []string) bool {
+	for j, word := range label {
+		original := tokens[j].Original
+		if j == len(label)-1 {
+			original = strings.TrimSuffix(original, ":")
+		}
+		if original != word {
+			return false
+		}
+	}
+	return true
+}
+
+// onlyWordsFollow reports whether tokens contains no digits, such as a
+// trailing country name.
+func onlyWordsFollow(tokens []Token) bool {
+	for _, token := range tokens {
+		if strings.ContainsAny(token.Original, "0123456789") {
+			return false
+		}
+	}
+	return true
+}
+
+// demoteInnerStates keeps only the last state token and returns earlier ones
+// to the street name/city pool. A state name that appears before the actual
+// state is part of the street or city, as in "123 Virginia Ave" or
+// "Washington DC 20001", and must not take the state slot.
+func demoteInnerStates(tokens []Token) {
+	last := -1
+	for i := range tokens {
+		if tokens[i].Type == TokenState {
+			last = i
+		}
+	}
+	for i := 0; i < last; i++ {
+		if tokens[i].Type == TokenState {
+			tokens[i].Type = TokenStreetName
+			tokens[i].Value = tokens[i].Original
+		}
+	}
+}
+
+// detachDanglingDesignators reclassifies a secondary number that is separated
+// from its designator by a comma. In "123 Main St Apt, Springfield" the word
+// after the comma starts a new segment and is not the unit number, so it is
+// returned to the street name/city pool.
+func detachDanglingDesignators(tokens []Token, input string) {
+	for i := 0; i+1 < len(tokens); i++ {
+		if tokens[i].Type != TokenSecondaryDesignator || tokens[i+1].Type != TokenSecondaryNumber {
+			continue
+		}
+		if commaBetween(input, tokens[i], tokens[i+1]) {
+			tokens[i+1].Type = TokenStreetName
+		}
+	}
+}
+
+// markLeadingFirm merges a leading named place, such as the building name in
+// "Empire State Building, 350 5th Ave, New York, NY 10118", into a single
+// TokenFirm token. The leading segment must end at a comma, contain no numbers,
+// state, or ZIP code, and be followed by a segment that starts with a house
+// number and a street name. It becomes a firm only if one of its words is a
+// business marker (see Lexicon.isFirmMarker); otherwise, as with a personal
+// name such as "John Smith", it is merged into a single TokenUnknown token that
+// is left out of the parsed address and reported by leadingNameDiagnostic.
+// Inputs that start with a house number are left unchanged.
+func (t *Tokenizer) markLeadingFirm(tokens []Token, input string) []Token {
+	for i := 0; i+1 < len(tokens); i++ {
+		switch tokens[i].Type {
+		case TokenHouseNumber, TokenSecondaryNumber, TokenState, TokenZIPCode, TokenZIPPlus4:
+			return tokens
+		}
+		if isNumeric(tokens[i].Original) {
+			return tokens
+		}
+		if !commaBetween(input, tokens[i], tokens[i+1]) {
+			continue
+		}
+
+		// The next segment must look like "350 5TH AVE": a number, then a word
+		house, next := tokens[i+1], tokens[i+2:]
+		if !isNumeric(house.Original) || house.Type == TokenZIPCode || len(next) == 0 {
+			return tokens
+		}
+		switch next[0].Type {
+		case TokenStreetName, TokenStreetSuffix, TokenPreDirectional:
+		default:
+			return tokens
+		}
+
+		words := make([]string, 0, i+1)
+		firmType := TokenUnknown
+		for _, token := range tokens[:i+1] {
+			words = append(words, token.Original)
+			if t.lexicon.isFirmMarker(token.Original) {
+				firmType = TokenFirm
+			}
+		}
+		firm := Token{
+			Type:     firmType,
+			Value:    strings.Join(words, " "),
+			Original: strings.Join(words, " "),
+			Start:    tokens[0].Start,
+			End:      tokens[i].End,
+		}
+		house.Type = TokenHouseNumber
+
+		return append([]Token{firm, house}, next...)
+	}
 	return tokens
 }
 
+// commaBetween reports whether a comma separates tokens a and b in the
+// original input.
+func commaBetween(input string, a, b Token) bool {
+	end, start := a.End, b.Start
+	if end < 0 || start > len(input) || end > start {
+		return false
+	}
+	return strings.Contains(input[end:start], ",")
+}
+
 // normalizeInputWithMapping cleans and normalizes the input string while maintaining
 // a mapping from normalized positions back to original positions.
 func normalizeInputWithMapping(input string) (string, []int) {
@@ -53,6 +309,11 @@ func normalizeInputWithMapping(input string) (string, []int) {
 	
 	// Convert to uppercase and build position map
 	for i, r := range s {
+		// Drop invisible formatting and control characters entirely
+		if isSuspiciousRune(r) {
+			continue
+		}
+
 		upper := unicode.ToUpper(r)
 		
 		// Treat punctuation as word separators (convert to space)
@@ -98,6 +359,20 @@ func normalizeInputWithMapping(input string) (string, []int) {
 	return normalized, positionMap
 }
 
+// isSuspiciousRune reports whether r is an invisible character that should be
+// stripped before tokenization. Two categories are stripped:
+//   - Unicode format characters (Cf), such as zero-width spaces (U+200B),
+//     zero-width joiners (U+200C, U+200D), byte order marks (U+FEFF), soft
+//     hyphens (U+00AD), and bidirectional marks (U+200E, U+200F)
+//   - Control characters (Cc) other than whitespace; tabs, newlines, and
+//     carriage returns are treated as ordinary word separators instead
+func isSuspiciousRune(r rune) bool {
+	if unicode.Is(unicode.Cf, r) {
+		return true
+	}
+	return unicode.IsControl(r) && !unicode.IsSpace(r)
+}
+
 // normalizeInput cleans and normalizes the input string.
 // This is kept for backward compatibility but should use normalizeInputWithMapping.
 func normalizeInput(input string) string {
@@ -154,6 +429,25 @@ func (t *Tokenizer) tokenizePart(part string, basePosition int, positionMap []in
 			endPos = position + len(word)
 		}
 
+		// Split a glued pound-sign designator ("#12") into "#" and its value
+		if len(word) > 1 && word[0] == '#' {
+			tokens = append(tokens, Token{
+				Type:     TokenSecondaryDesignator,
+				Value:    "#",
+				Original: "#",
+				Start:    startPos,
+				End:      startPos + 1,
+			})
+			word = word[1:]
+			original = word
+			position++
+			if position < len(positionMap) {
+				startPos = positionMap[position]
+			} else {
+				startPos++
+			}
+		}
+
 		// Try to classify the token
 		token := Token{
 			Value:    word,
@@ -223,7 +517,25 @@ func (t *Tokenizer) tokenizePart(part string, basePosition int, positionMap []in
 				token.Type = TokenSecondaryNumber
 			} else {
 				token.Type = TokenHouseNumber
+				// A fraction after the house number ("123 1/2") is part of it
+				if i+1 < len(words) && isFraction(words[i+1]) {
+					word += " " + words[i+1]
+					i++
+					token.Value = word
+					token.Original = word
+					if end := position + len(word) - 1; end < len(positionMap) {
+						token.End = positionMap[end] + 1
+					}
+				}
 			}
+		} else if number, ok := fractionalHouseNumber(word); ok && (len(tokens) == 0 || tokens[len(tokens)-1].Type != TokenSecondaryDesignator) {
+			// "123-1/2" is written "123 1/2" per USPS Publication 28
+			token.Type = TokenHouseNumber
+			token.Value = number
+		} else if isGridHouseNumber(word) && (len(tokens) == 0 || tokens[len(tokens)-1].Type != TokenSecondaryDesignator) {
+			// A grid address such as "N6W23001" must not lose its leading
+			// letter to directional normalization
+			token.Type = TokenHouseNumber
 		} else if normalized, ok := t.lexicon.NormalizeDirectional(word); ok {
 			token.Type = TokenPreDirectional // May need to disambiguate later
 			token.Value = normalized
@@ -240,6 +552,10 @@ func (t *Tokenizer) tokenizePart(part string, basePosition int, positionMap []in
 			// Check if it's alphanumeric (like "4B" for apartment)
 			if len(tokens) > 0 && tokens[len(tokens)-1].Type == TokenSecondaryDesignator {
 				token.Type = TokenSecondaryNumber
+			} else if i == 0 && i+1 < len(words) && isAlphanumericHouseNumber(word) {
+				// A letter attached to the house number ("123A", "123-A") is
+				// part of the primary number, not a unit
+				token.Type = TokenHouseNumber
 			} else {
 				// Default to street name or city
 				token.Type = TokenStreetName
@@ -266,6 +582,75 @@ func isNumeric(s string) bool {
 	return true
 }
 
+// isFraction checks if a string is a simple fraction of one- or two-digit
+// numbers, such as "1/2" or "3/4", as used in fractional house numbers.
+func isFraction(s string) bool {
+	numerator, denominator, ok := strings.Cut(s, "/")
+	return ok && isDigits(numerator, 2) && isDigits(denominator, 2)
+}
+
+// isDigits checks if a string is made of 1 to max digits.
+func isDigits(s string, max int) bool {
+	if len(s) == 0 || len(s) > max {
+		return false
+	}
+	for _, r := range s {
+		if !unicode.IsDigit(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// fractionalHouseNumber converts a house number joined to its fraction by a
+// hyphen, such as "123-1/2", to the spaced form "123 1/2".
+func fractionalHouseNumber(s string) (string, bool) {
+	number, fraction, ok := strings.Cut(s, "-")
+	if !ok || !isDigits(number, len(number)) || !isFraction(fraction) {
+		return "", false
+	}
+	return number + " " + fraction, true
+}
+
+// isGridHouseNumber checks if a string is a grid-style house number, as used
+// in parts of Wisconsin and Illinois: a direction letter and digits, twice,
+// such as "N6W23001" or "W156N11500".
+func isGridHouseNumber(s string) bool {
+	for half := 0; half < 2; half++ {
+		if len(s) < 2 || !strings.ContainsRune("NSEW", rune(s[0])) {
+			return false
+		}
+		n := 1
+		for n < len(s) && s[n] >= '0' && s[n] <= '9' {
+			n++
+		}
+		if n == 1 {
+			return false
+		}
+		s = s[n:]
+	}
+	return s == ""
+}
+
+// isAlphanumericHouseNumber checks if a string is a house number with a
+// single trailing letter, optionally hyphenated, such as "123A" or "123-A".
+func isAlphanumericHouseNumber(s string) bool {
+	n := len(s)
+	if n < 2 || !unicode.IsLetter(rune(s[n-1])) {
+		return false
+	}
+	digits := strings.TrimSuffix(s[:n-1], "-")
+	if digits == "" {
+		return false
+	}
+	for _, r := range digits {
+		if !unicode.IsDigit(r) {
+			return false
+		}
+	}
+	return true
+}
+
 // isZIPCode checks if a string looks like a ZIP code.
 func isZIPCode(s string) bool {
 	// 5-digit or 9-digit (with hyphen) or 10-digit (no hyphen)
@@ -180,3 +180,71 @@ func TestNewTokenizer(t *testing.T) {
 		t.Error("lexicon is nil")
 	}
 }
+
+func TestTokenizer_GluedHashDesignator(t *testing.T) {
+	tok := newTokenizer()
+	input := "123 Main St #12"
+
+	tokens := tok.tokenize(input)
+	if len(tokens) != 5 {
+		t.Fatalf("got %d tokens, want 5", len(tokens))
+	}
+
+	hash, number := tokens[3], tokens[4]
+	if hash.Type != TokenSecondaryDesignator || hash.Value != "#" {
+		t.Errorf("token 3 = %v %q, want designator \"#\"", hash.Type, hash.Value)
+	}
+	if number.Type != TokenSecondaryNumber || number.Value != "12" {
+		t.Errorf("token 4 = %v %q, want secondary number \"12\"", number.Type, number.Value)
+	}
+	if input[hash.Start:hash.End] != "#" {
+		t.Errorf("hash span = %q, want %q", input[hash.Start:hash.End], "#")
+	}
+	if input[number.Start:number.End] != "12" {
+		t.Errorf("number span = %q, want %q", input[number.Start:number.End], "12")
+	}
+}
+
+func TestTokenize_FractionalHouseNumberSpan(t *testing.T) {
+	input := "123 1/2 Main St"
+	tokens := newTokenizer().tokenize(input)
+	if len(tokens) == 0 || tokens[0].Type != TokenHouseNumber {
+		t.Fatalf("first token = %+v, want a house number", tokens)
+	}
+	if got := input[tokens[0].Start:tokens[0].End]; got != "123 1/2" {
+		t.Errorf("house number spans %q, want %q", got, "123 1/2")
+	}
+	if tokens[1].Value != "MAIN" {
+		t.Errorf("next token = %q, want %q", tokens[1].Value, "MAIN")
+	}
+}
+
+func TestIsGridHouseNumber(t *testing.T) {
+	tests := []struct {
+		input string
+		want  bool
+	}{
+		{"N6W23001", true},
+		{"W156N11500", true},
+		{"S74W16853", true},
+		{"E1N2", true},
+		{"N6W", false},
+		{"N6", false},
+		{"NW23001", false},
+		{"6W23001", false},
+		{"X6W23001", false},
+		{"N6W23001A", false},
+		{"N6W2N3", false},
+		{"N", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got := isGridHouseNumber(tt.input)
+			if got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package parser
 
 import (
+	"encoding/json"
 	"strings"
 	
 	"github.com/my-eq/go-usps/models"
@@ -36,6 +37,8 @@ const (
 	TokenZIPPlus4
 	// TokenFirm represents a firm or business name.
 	TokenFirm
+	// TokenCountry represents a trailing country name.
+	TokenCountry
 )
 
 // Token represents a classified lexeme from the input.
@@ -55,6 +58,8 @@ type Diagnostic struct {
 	End         int    // End position in input
 	Remediation string // Suggested fix
 	Code        string // Machine-readable code
+
+	args [2]string // Values for the "{0}" and "{1}" placeholders in localized text
 }
 
 // DiagnosticSeverity represents the severity level of a diagnostic.
@@ -93,12 +98,16 @@ type ParsedAddress struct {
 	PostDirectional  string
 	SecondaryUnit    string
 	SecondaryNumber  string
+	SecondaryExtra   string // Chained secondary units after the first, e.g. "STE 200" in "BLDG 5, STE 200"
 	City             string
 	State            string
 	ZIPCode          string
 	ZIPPlus4         string
+	Country          string // ISO alpha-3 code of a trailing country name, if present
+	Phone            string // Phone number removed from the input, as written
 	Tokens           []Token
-	OriginalInput    string
+	OriginalInput    string // Verbatim input passed to Parse, before any normalization
+	Confidence       float64 // 0.0 to 1.0; see ConfidenceScore
 }
 
 // ToAddressRequest converts a ParsedAddress to a models.AddressRequest.
@@ -141,6 +150,9 @@ func (p *ParsedAddress) ToAddressRequest() *models.AddressRequest {
 		if p.SecondaryNumber != "" {
 			secondaryParts = append(secondaryParts, p.SecondaryNumber)
 		}
+		if p.SecondaryExtra != "" {
+			secondaryParts = append(secondaryParts, p.SecondaryExtra)
+		}
 		req.SecondaryAddress = joinTokens(secondaryParts)
 	}
 
@@ -156,10 +168,90 @@ func (p *ParsedAddress) ToAddressRequest() *models.AddressRequest {
 	if p.ZIPPlus4 != "" {
 		req.ZIPPlus4 = p.ZIPPlus4
 	}
+	if p.Country != "" {
+		req.Country = p.Country
+	}
 
 	return req
 }
 
+// ToAddressRequestJSON returns the JSON encoding of ToAddressRequest, ready to
+// pass to another service that calls the USPS API. Empty optional fields are
+// omitted per the models.AddressRequest JSON tags; streetAddress and state are
+// always present.
+func (p *ParsedAddress) ToAddressRequestJSON() ([]byte, error) {
+	return json.Marshal(p.ToAddressRequest())
+}
+
+// LabelLines returns the address as mailing label lines in USPS Publication 28
+// order: the firm (when present), the delivery line with any secondary unit,
+// and the last line with city, state, and ZIP code. The delivery and last
+// lines come from models.AddressRequest.Lines; a firm is placed on its own
+// line above the delivery line. Empty lines are omitted.
+func (p *ParsedAddress) LabelLines() []string {
+	if p == nil {
+		return []string{}
+	}
+
+	req := p.ToAddressRequest()
+	lines := req.Lines()
+
+	// Lines only uses the firm as the delivery line when there is no street
+	if req.Firm != "" && req.StreetAddress != "" {
+		lines = append([]string{req.Firm}, lines...)
+	}
+
+	return lines
+}
+
+// ConfidenceScore returns how completely the address was recognized, from
+// 0.0 to 1.0. The score is the sum of the weights of the components present:
+// street (house number and street name) 0.40, state 0.25, city 0.20, and ZIP
+// code 0.15. Each error diagnostic subtracts 0.10 and each warning 0.05,
+// except the missing-component diagnostics, which the weights already cover.
+// A complete address with no diagnostics scores 1.0.
+func (p *ParsedAddress) ConfidenceScore() float64 {
+	if p == nil {
+		return 0
+	}
+	return p.Confidence
+}
+
+// territoryStates are the USPS codes of U.S. territories.
+var territoryStates = map[string]bool{"AS": true, "GU": true, "MP": true, "PR": true, "VI": true}
+
+// territoryZIPRanges are the inclusive ZIP code ranges of U.S. territories.
+// The 969xx block outside GU and MP belongs to the freely associated states
+// (FM, MH, PW), which are not territories.
+var territoryZIPRanges = []struct{ low, high string }{
+	{"00600", "00999"}, // PR and VI
+	{"96799", "96799"}, // AS
+	{"96910", "96932"}, // GU
+	{"96950", "96952"}, // MP
+}
+
+// IsTerritory reports whether the address is in a U.S. territory: Puerto
+// Rico, the U.S. Virgin Islands, Guam, the Northern Mariana Islands, or
+// American Samoa. The state code decides when present; otherwise the ZIP code
+// is checked against the territory ZIP ranges.
+func (p *ParsedAddress) IsTerritory() bool {
+	if p == nil {
+		return false
+	}
+	if p.State != "" {
+		return territoryStates[p.State]
+	}
+	if len(p.ZIPCode) != 5 {
+		return false
+	}
+	for _, r := range territoryZIPRanges {
+		if p.ZIPCode >= r.low && p.ZIPCode <= r.high {
+			return true
+		}
+	}
+	return false
+}
+
 // joinTokens joins string parts with a single space.
 func joinTokens(parts []string) string {
 	if len(parts) == 0 {
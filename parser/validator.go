@@ -1,5 +1,7 @@
 package parser
 
+import "math"
+
 // Validator enforces USPS Publication 28 component ordering and requirements.
 type Validator struct{}
 
@@ -31,6 +33,16 @@ func (v *Validator) validate(parsed *ParsedAddress) []Diagnostic {
 		})
 	}
 
+	// USPS needs either a city or a ZIP code to locate the address
+	if parsed.City == "" && parsed.ZIPCode == "" {
+		diagnostics = append(diagnostics, Diagnostic{
+			Severity:    SeverityError,
+			Message:     "Missing city; a city or ZIP code is required",
+			Code:        "MISSING_CITY",
+			Remediation: "Add a city name or a 5-digit ZIP code",
+		})
+	}
+
 	// Check for ZIP code
 	if parsed.ZIPCode == "" {
 		diagnostics = append(diagnostics, Diagnostic{
@@ -41,5 +53,97 @@ func (v *Validator) validate(parsed *ParsedAddress) []Diagnostic {
 		})
 	}
 
+	if d, ok := unknownSecondaryDiagnostic(parsed.Tokens); ok {
+		diagnostics = append(diagnostics, d)
+	}
+
 	return diagnostics
 }
+
+// unknownSecondaryDiagnostic reports a word that is not a USPS secondary unit
+// designator used as one, such as "FLAT" in "123 Main St Flat 4": a word
+// directly followed by a number after the street suffix and before the state.
+// The parser keeps the word and number as the secondary unit (see
+// isUnknownSecondary), but USPS will not recognize the designator.
+func unknownSecondaryDiagnostic(tokens []Token) (Diagnostic, bool) {
+	seenStreetSuffix := false
+	for i, token := range tokens {
+		switch token.Type {
+		case TokenStreetSuffix:
+			seenStreetSuffix = true
+		case TokenState:
+			return Diagnostic{}, false
+		case TokenHouseNumber:
+			if !seenStreetSuffix || tokens[i-1].Type != TokenStreetName {
+				continue
+			}
+			designator := tokens[i-1]
+			return Diagnostic{
+				Severity:    SeverityWarning,
+				Message:     designator.Value + " is not a USPS secondary unit designator",
+				Start:       designator.Start,
+				End:         designator.End,
+				Remediation: "Use a USPS-recognized unit designator such as APT, STE, or UNIT",
+				Code:        "UNKNOWN_SECONDARY",
+			}, true
+		}
+	}
+	return Diagnostic{}, false
+}
+
+// Confidence weights for the address components. They sum to 1.0, so an
+// address with every component and no diagnostics scores 1.0. The state
+// outweighs the ZIP code because USPS requires a state, while a missing ZIP
+// code is only a warning.
+const (
+	confidenceStreet = 0.40 // house number and street name
+	confidenceState  = 0.25
+	confidenceCity   = 0.20
+	confidenceZIP    = 0.15
+)
+
+// Confidence penalties for diagnostics other than the missing-component ones,
+// whose cost is already reflected in the component weights.
+const (
+	confidenceErrorPenalty   = 0.10
+	confidenceWarningPenalty = 0.05
+)
+
+// confidence scores how completely parsed was recognized, from 0.0 to 1.0.
+// Each present component adds its weight; each error or warning diagnostic,
+// other than the missing-component ones raised by validate, subtracts a
+// penalty. Info diagnostics do not affect the score.
+func (v *Validator) confidence(parsed *ParsedAddress, diagnostics []Diagnostic) float64 {
+	var score float64
+	if parsed.HouseNumber != "" && parsed.StreetName != "" {
+		score += confidenceStreet
+	}
+	if parsed.State != "" {
+		score += confidenceState
+	}
+	if parsed.City != "" {
+		score += confidenceCity
+	}
+	if parsed.ZIPCode != "" {
+		score += confidenceZIP
+	}
+
+	for _, d := range diagnostics {
+		switch d.Code {
+		case "MISSING_STATE", "MISSING_STREET", "MISSING_CITY", "MISSING_ZIP":
+			continue
+		}
+		switch d.Severity {
+		case SeverityError:
+			score -= confidenceErrorPenalty
+		case SeverityWarning:
+			score -= confidenceWarningPenalty
+		}
+	}
+
+	if score < 0 {
+		return 0
+	}
+	// Round away floating-point noise so a complete address scores exactly 1.0
+	return math.Round(score*100) / 100
+}
@@ -0,0 +1,81 @@
+package usps
+
+import (
+	"errors"
+	"time"
+)
+
+// RetryDecision is the outcome of a retry classifier: fail immediately, retry
+// after the usual exponential backoff, or retry after a fixed delay. Build one
+// with Retry, Fail, or RetryAfter.
+type RetryDecision struct {
+	retry bool
+	delay time.Duration
+}
+
+// Retry retries the request after the configured exponential backoff.
+func Retry() RetryDecision {
+	return RetryDecision{retry: true}
+}
+
+// Fail returns the error without retrying.
+func Fail() RetryDecision {
+	return RetryDecision{}
+}
+
+// RetryAfter retries the request after d instead of the exponential backoff.
+// A zero or negative d behaves like Retry.
+func RetryAfter(d time.Duration) RetryDecision {
+	if d <= 0 {
+		return Retry()
+	}
+	return RetryDecision{retry: true, delay: d}
+}
+
+// ShouldRetry reports whether the request should be retried.
+func (d RetryDecision) ShouldRetry() bool {
+	return d.retry
+}
+
+// Delay returns the fixed delay requested with RetryAfter, if any.
+func (d RetryDecision) Delay() (time.Duration, bool) {
+	return d.delay, d.delay > 0
+}
+
+// WithRetryClassifier replaces the rules that decide whether a failed request
+// is retried. Use it when a gateway in front of the USPS APIs maps errors to
+// different status codes, for example returning 502 for a bad request. The
+// classifier is consulted by every retrying operation of the client,
+// including BulkProcessor, and is never called with a nil error. A nil
+// classifier restores DefaultRetryClassifier.
+func WithRetryClassifier(classifier func(err error) RetryDecision) Option {
+	return func(c *Client) {
+		c.retryClassifier = classifier
+	}
+}
+
+// DefaultRetryClassifier retries 429 and 5xx API errors and transport
+// errors, including the HTTP client's own timeout, honoring a Retry-After
+// header when the response carries one. It fails on other API errors and on
+// the caller's context cancellation or deadline, including the *TimeoutError
+// wrapping them (ErrRequestCanceled and ErrRequestTimeout).
+func DefaultRetryClassifier(err error) RetryDecision {
+	if !isRetryableError(err) {
+		return Fail()
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		if retryAfter, ok := apiErr.RetryAfterDuration(); ok {
+			return RetryAfter(retryAfter)
+		}
+	}
+	return Retry()
+}
+
+// classifyRetry applies the client's retry classifier to err
+func (c *Client) classifyRetry(err error) RetryDecision {
+	if c == nil || c.retryClassifier == nil {
+		return DefaultRetryClassifier(err)
+	}
+	return c.retryClassifier(err)
+}
@@ -0,0 +1,168 @@
+package usps
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/my-eq/go-usps/models"
+)
+
+// newStatusServer returns a server that answers the first failures requests
+// with status and JSON error bodies, then succeeds.
+func newStatusServer(status int, failures int32, calls *int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if atomic.AddInt32(calls, 1) <= failures {
+			w.WriteHeader(status)
+			_ = json.NewEncoder(w).Encode(models.ErrorMessage{
+				Error: &models.ErrorInfo{Code: http.StatusText(status), Message: "Gateway error"},
+			})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(models.CityStateResponse{City: "NEW YORK", State: "NY", ZIPCode: "10001"})
+	}))
+}
+
+func TestWithRetryClassifier(t *testing.T) {
+	tests := []struct {
+		name         string
+		status       int
+		classifier   func(err error) RetryDecision
+		wantErr      bool
+		wantCalls    int32
+		wantBackoffs []time.Duration
+	}{
+		{
+			name:   "retry",
+			status: http.StatusBadRequest,
+			classifier: func(err error) RetryDecision {
+				return Retry()
+			},
+			wantCalls:    2,
+			wantBackoffs: []time.Duration{time.Millisecond},
+		},
+		{
+			name:   "fail",
+			status: http.StatusServiceUnavailable,
+			classifier: func(err error) RetryDecision {
+				return Fail()
+			},
+			wantErr:   true,
+			wantCalls: 1,
+		},
+		{
+			name:   "retry after",
+			status: http.StatusBadGateway,
+			classifier: func(err error) RetryDecision {
+				return RetryAfter(5 * time.Millisecond)
+			},
+			wantCalls:    2,
+			wantBackoffs: []time.Duration{5 * time.Millisecond},
+		},
+		{
+			name:       "nil restores default",
+			status:     http.StatusBadRequest,
+			classifier: nil,
+			wantErr:    true,
+			wantCalls:  1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls int32
+			server := newStatusServer(tt.status, 1, &calls)
+			defer server.Close()
+
+			client := NewClient(
+				NewStaticTokenProvider("test-token"),
+				WithBaseURL(server.URL),
+				WithRetryClassifier(tt.classifier),
+			)
+
+			var backoffs []time.Duration
+			processor := NewBulkProcessor(client, &BulkConfig{
+				MaxConcurrency:    1,
+				RequestsPerSecond: 100,
+				MaxRetries:        1,
+				RetryBackoff:      time.Millisecond,
+				BeforeRetry: func(attempt int, err error, backoff time.Duration) bool {
+					backoffs = append(backoffs, backoff)
+					return true
+				},
+			})
+
+			results := processor.ProcessCityStates(context.Background(), []*models.CityStateRequest{{ZIPCode: "10001"}})
+
+			if gotErr := results[0].Error != nil; gotErr != tt.wantErr {
+				t.Errorf("Expected error %v, got %v", tt.wantErr, results[0].Error)
+			}
+			if got := atomic.LoadInt32(&calls); got != tt.wantCalls {
+				t.Errorf("Expected %d calls, got %d", tt.wantCalls, got)
+			}
+			if len(backoffs) != len(tt.wantBackoffs) {
+				t.Fatalf("Expected backoffs %v, got %v", tt.wantBackoffs, backoffs)
+			}
+			for i := range backoffs {
+				if backoffs[i] != tt.wantBackoffs[i] {
+					t.Errorf("Expected backoffs %v, got %v", tt.wantBackoffs, backoffs)
+				}
+			}
+			if got := client.Config().RetryClassifier; got != (tt.classifier != nil) {
+				t.Errorf("Expected Config().RetryClassifier %v, got %v", tt.classifier != nil, got)
+			}
+		})
+	}
+}
+
+func TestDefaultRetryClassifier(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		wantRetry bool
+		wantDelay time.Duration
+	}{
+		{"rate limited", &APIError{StatusCode: http.StatusTooManyRequests}, true, 0},
+		{"retry after", &APIError{StatusCode: http.StatusServiceUnavailable, RetryAfter: 2 * time.Second}, true, 2 * time.Second},
+		{"server error", &APIError{StatusCode: http.StatusInternalServerError}, true, 0},
+		{"bad request", &APIError{StatusCode: http.StatusBadRequest}, false, 0},
+		{"canceled", context.Canceled, false, 0},
+		{"caller canceled", &TimeoutError{Canceled: true, Err: context.Canceled}, false, 0},
+		{"caller deadline", &TimeoutError{Err: context.DeadlineExceeded}, false, 0},
+		{"wrapped caller canceled", fmt.Errorf("lookup: %w", &TimeoutError{Canceled: true, Err: context.Canceled}), false, 0},
+		{"client timeout", &TimeoutError{ClientTimeout: true, Timeout: time.Second, Err: errors.New("Client.Timeout exceeded")}, true, 0},
+		{"wrapped rate limited", fmt.Errorf("lookup: %w", &APIError{StatusCode: http.StatusTooManyRequests}), true, 0},
+		{"network error", errors.New("connection refused"), true, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decision := DefaultRetryClassifier(tt.err)
+
+			if decision.ShouldRetry() != tt.wantRetry {
+				t.Errorf("Expected ShouldRetry %v, got %v", tt.wantRetry, decision.ShouldRetry())
+			}
+			if delay, _ := decision.Delay(); delay != tt.wantDelay {
+				t.Errorf("Expected delay %v, got %v", tt.wantDelay, delay)
+			}
+		})
+	}
+}
+
+func TestRetryAfter_NonPositive(t *testing.T) {
+	decision := RetryAfter(0)
+
+	if !decision.ShouldRetry() {
+		t.Error("Expected RetryAfter(0) to retry")
+	}
+	if _, ok := decision.Delay(); ok {
+		t.Error("Expected RetryAfter(0) to use the exponential backoff")
+	}
+}
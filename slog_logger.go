@@ -0,0 +1,85 @@
+package usps
+
+import (
+	"context"
+	"log/slog"
+	"net/url"
+	"time"
+)
+
+// WithSlogLogger logs every API request to logger as a structured record with
+// the attributes http.method, http.path, http.status_code, usps.endpoint, and
+// duration_ms, plus usps.correlation_id when the call has a correlation ID
+// (see WithObservabilityContext). Records are logged with the request
+// context, so handlers can read trace data from it. Successful responses are logged at Info level; 4xx and 5xx
+// responses, and requests that received no response (status code 0), at
+// Warn. Only the URL path is logged, never headers, query parameters, or
+// bodies, so tokens and credentials cannot appear in the output.
+//
+// It is a convenience over WithLogger and replaces any logger set there. Like
+// WithLogger, it also applies to NewOAuthClient, where the endpoint is
+// EndpointOAuthToken, EndpointOAuthRevoke, or EndpointOAuthDeviceCode. A nil
+// logger disables logging.
+func WithSlogLogger(logger *slog.Logger) Option {
+	if logger == nil {
+		return WithLogger(nil)
+	}
+	return WithLogger(&slogLogger{logger: logger})
+}
+
+// slogLogger adapts a *slog.Logger to the Logger interface.
+type slogLogger struct {
+	logger *slog.Logger
+}
+
+// LogRequest logs the outgoing request at Debug level.
+func (l *slogLogger) LogRequest(method, rawURL string) {
+	l.log(context.Background(), slog.LevelDebug, "usps request",
+		slog.String("http.method", method),
+		slog.String("http.path", urlPath(rawURL)),
+	)
+}
+
+// LogResponse logs the finished round trip without request details.
+func (l *slogLogger) LogResponse(statusCode int, duration time.Duration) {
+	l.log(context.Background(), responseLevel(statusCode), "usps response",
+		slog.Int("http.status_code", statusCode),
+		slog.Int64("duration_ms", duration.Milliseconds()),
+	)
+}
+
+// logRoundTrip logs the whole round trip as a single record.
+func (l *slogLogger) logRoundTrip(ctx context.Context, endpoint, method, rawURL string, statusCode int, duration time.Duration) {
+	l.log(ctx, responseLevel(statusCode), "usps request",
+		slog.String("http.method", method),
+		slog.String("http.path", urlPath(rawURL)),
+		slog.Int("http.status_code", statusCode),
+		slog.String("usps.endpoint", endpoint),
+		slog.Int64("duration_ms", duration.Milliseconds()),
+	)
+}
+
+// log writes a record with ctx, adding the correlation ID from ctx, if any.
+func (l *slogLogger) log(ctx context.Context, level slog.Level, msg string, attrs ...slog.Attr) {
+	if id := CorrelationIDFromContext(ctx); id != "" {
+		attrs = append(attrs, slog.String("usps.correlation_id", id))
+	}
+	l.logger.LogAttrs(ctx, level, msg, attrs...)
+}
+
+// responseLevel returns Info for a successful status code and Warn otherwise.
+func responseLevel(statusCode int) slog.Level {
+	if statusCode > 0 && statusCode < 400 {
+		return slog.LevelInfo
+	}
+	return slog.LevelWarn
+}
+
+// urlPath returns the path of rawURL, or an empty string if it does not parse.
+func urlPath(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	return u.Path
+}
@@ -0,0 +1,201 @@
+package usps
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/my-eq/go-usps/models"
+)
+
+// captureHandler is a slog.Handler that keeps every record it handles.
+type captureHandler struct {
+	mu      sync.Mutex
+	records []slog.Record
+	ctxIDs  []string
+}
+
+func (h *captureHandler) Enabled(context.Context, slog.Level) bool { return true }
+
+func (h *captureHandler) Handle(ctx context.Context, r slog.Record) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.records = append(h.records, r.Clone())
+	h.ctxIDs = append(h.ctxIDs, CorrelationIDFromContext(ctx))
+	return nil
+}
+
+func (h *captureHandler) WithAttrs([]slog.Attr) slog.Handler { return h }
+func (h *captureHandler) WithGroup(string) slog.Handler      { return h }
+
+// attrs returns the attributes of record i keyed by name.
+func (h *captureHandler) attrs(i int) map[string]slog.Value {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	attrs := make(map[string]slog.Value)
+	h.records[i].Attrs(func(a slog.Attr) bool {
+		attrs[a.Key] = a.Value
+		return true
+	})
+	return attrs
+}
+
+func TestWithSlogLogger(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("ZIPCode") == "00000" {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(models.ErrorMessage{
+				Error: &models.ErrorInfo{Code: "400", Message: "Invalid ZIP Code"},
+			})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(models.CityStateResponse{City: "NEW YORK", State: "NY", ZIPCode: "10001"})
+	}))
+	defer server.Close()
+
+	handler := &captureHandler{}
+	client := NewClient(
+		NewStaticTokenProvider("secret-token"),
+		WithBaseURL(server.URL),
+		WithSlogLogger(slog.New(handler)),
+	)
+
+	if _, err := client.GetCityState(context.Background(), &models.CityStateRequest{ZIPCode: "10001"}); err != nil {
+		t.Fatalf("GetCityState failed: %v", err)
+	}
+	if _, err := client.GetCityState(context.Background(), &models.CityStateRequest{ZIPCode: "00000"}); err == nil {
+		t.Fatal("Expected error for 400 response")
+	}
+
+	if len(handler.records) != 2 {
+		t.Fatalf("Expected 2 log records, got %d", len(handler.records))
+	}
+
+	tests := []struct {
+		level  slog.Level
+		status int64
+	}{
+		{slog.LevelInfo, http.StatusOK},
+		{slog.LevelWarn, http.StatusBadRequest},
+	}
+	for i, tt := range tests {
+		if handler.records[i].Level != tt.level {
+			t.Errorf("Record %d: expected level %v, got %v", i, tt.level, handler.records[i].Level)
+		}
+		attrs := handler.attrs(i)
+		if got := attrs["http.method"].String(); got != http.MethodGet {
+			t.Errorf("Record %d: expected http.method GET, got %s", i, got)
+		}
+		if got := attrs["http.path"].String(); got != "/city-state" {
+			t.Errorf("Record %d: expected http.path /city-state, got %s", i, got)
+		}
+		if got := attrs["http.status_code"].Int64(); got != tt.status {
+			t.Errorf("Record %d: expected http.status_code %d, got %d", i, tt.status, got)
+		}
+		if got := attrs["usps.endpoint"].String(); got != EndpointCityState {
+			t.Errorf("Record %d: expected usps.endpoint %s, got %s", i, EndpointCityState, got)
+		}
+		if _, ok := attrs["duration_ms"]; !ok {
+			t.Errorf("Record %d: expected duration_ms attribute", i)
+		}
+		if strings.Contains(fmt.Sprint(attrs), "secret-token") || strings.Contains(fmt.Sprint(attrs), "10001") {
+			t.Errorf("Record %d: expected no token or query parameters, got %v", i, attrs)
+		}
+	}
+}
+
+func TestWithSlogLogger_OAuthClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(models.ProviderAccessTokenResponse{
+			AccessToken: "issued-access-token",
+			ExpiresIn:   3600,
+			TokenType:   "Bearer",
+		})
+	}))
+	defer server.Close()
+
+	handler := &captureHandler{}
+	client := NewOAuthClient(WithBaseURL(server.URL), WithSlogLogger(slog.New(handler)))
+
+	_, err := client.PostToken(context.Background(), &models.ClientCredentials{
+		GrantType:    "client_credentials",
+		ClientID:     "client-id",
+		ClientSecret: "client-secret",
+	})
+	if err != nil {
+		t.Fatalf("PostToken failed: %v", err)
+	}
+
+	if len(handler.records) != 1 {
+		t.Fatalf("Expected 1 log record, got %d", len(handler.records))
+	}
+	attrs := handler.attrs(0)
+	if got := attrs["usps.endpoint"].String(); got != EndpointOAuthToken {
+		t.Errorf("Expected usps.endpoint %s, got %s", EndpointOAuthToken, got)
+	}
+	if got := attrs["http.method"].String(); got != http.MethodPost {
+		t.Errorf("Expected http.method POST, got %s", got)
+	}
+	if got := attrs["http.path"].String(); got != "/token" {
+		t.Errorf("Expected http.path /token, got %s", got)
+	}
+	for _, secret := range []string{"client-secret", "issued-access-token"} {
+		if strings.Contains(fmt.Sprint(attrs), secret) {
+			t.Errorf("Expected %q not to be logged, got %v", secret, attrs)
+		}
+	}
+}
+
+func TestWithSlogLogger_Nil(t *testing.T) {
+	client := NewClient(NewStaticTokenProvider("test-token"), WithSlogLogger(nil))
+
+	if client.Config().Logger {
+		t.Error("Expected no logger for WithSlogLogger(nil)")
+	}
+}
+
+func TestWithSlogLogger_CorrelationID(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(models.CityStateResponse{City: "NEW YORK", State: "NY", ZIPCode: "10001"})
+	}))
+	defer server.Close()
+
+	handler := &captureHandler{}
+	client := NewClient(NewStaticTokenProvider("test-token"), WithBaseURL(server.URL),
+		WithSlogLogger(slog.New(handler)), WithObservabilityContext())
+
+	var meta ResponseMeta
+	ctx := ContextWithResponseMeta(context.Background(), &meta)
+	if _, err := client.GetCityState(ctx, &models.CityStateRequest{ZIPCode: "10001"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(handler.records) != 1 {
+		t.Fatalf("Expected 1 record, got %d", len(handler.records))
+	}
+	if got := handler.attrs(0)["usps.correlation_id"].String(); got != meta.CorrelationID || got == "" {
+		t.Errorf("Expected usps.correlation_id %q, got %q", meta.CorrelationID, got)
+	}
+	if handler.ctxIDs[0] != meta.CorrelationID {
+		t.Errorf("Expected the record to be logged with the request context carrying %q, got %q", meta.CorrelationID, handler.ctxIDs[0])
+	}
+
+	// Without a correlation ID the attribute is omitted
+	plain := &captureHandler{}
+	client = NewClient(NewStaticTokenProvider("test-token"), WithBaseURL(server.URL), WithSlogLogger(slog.New(plain)))
+	if _, err := client.GetCityState(context.Background(), &models.CityStateRequest{ZIPCode: "10001"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, ok := plain.attrs(0)["usps.correlation_id"]; ok {
+		t.Error("Expected no usps.correlation_id attribute without a correlation ID")
+	}
+}
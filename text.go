@@ -0,0 +1,31 @@
+package usps
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/my-eq/go-usps/models"
+	"github.com/my-eq/go-usps/parser"
+)
+
+// GetAddressFromText standardizes a free-form address such as
+// "123 north main street apt 4b, new york ny 10001". It parses raw with
+// parser.Parse, converts the result with ToAddressRequest, and calls
+// GetAddress, returning the API response along with the parser's
+// diagnostics. If any diagnostic has SeverityError, no request is made and
+// the returned error wraps ErrUnparseableAddress and names the first problem.
+// Options such as WithRequestDeadline apply to the GetAddress call.
+func (c *Client) GetAddressFromText(ctx context.Context, raw string, opts ...RequestOption) (*models.AddressResponse, []parser.Diagnostic, error) {
+	parsed, diagnostics := parser.Parse(raw)
+	for _, d := range diagnostics {
+		if d.Severity == parser.SeverityError {
+			return nil, diagnostics, fmt.Errorf("%w: %s", ErrUnparseableAddress, d.Message)
+		}
+	}
+
+	resp, err := c.GetAddress(ctx, parsed.ToAddressRequest(), opts...)
+	if err != nil {
+		return nil, diagnostics, err
+	}
+	return resp, diagnostics, nil
+}
@@ -0,0 +1,137 @@
+package usps
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/my-eq/go-usps/models"
+	"github.com/my-eq/go-usps/parser"
+)
+
+func TestGetAddressFromText(t *testing.T) {
+	tests := []struct {
+		name          string
+		raw           string
+		wantQuery     url.Values
+		wantDiagCodes []string
+	}{
+		{
+			name: "clean text",
+			raw:  "123 Main St, New York, NY 10001",
+			wantQuery: url.Values{
+				"streetAddress": {"123 MAIN ST"},
+				"city":          {"NEW YORK"},
+				"state":         {"NY"},
+				"ZIPCode":       {"10001"},
+			},
+		},
+		{
+			name: "text needing standardization",
+			raw:  "123 north main street apt 4b new york ny 10001",
+			wantQuery: url.Values{
+				"streetAddress":    {"123 N MAIN ST"},
+				"secondaryAddress": {"APT 4B"},
+				"city":             {"NEW YORK"},
+				"state":            {"NY"},
+				"ZIPCode":          {"10001"},
+			},
+			wantDiagCodes: []string{"INFERRED_SEGMENTATION"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotQuery url.Values
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotQuery = r.URL.Query()
+				w.Header().Set("Content-Type", "application/json")
+				_ = json.NewEncoder(w).Encode(models.AddressResponse{
+					Address: &models.DomesticAddress{
+						Address: models.Address{StreetAddress: r.URL.Query().Get("streetAddress")},
+						State:   r.URL.Query().Get("state"),
+					},
+				})
+			}))
+			defer server.Close()
+
+			client := NewClient(NewStaticTokenProvider("test-token"), WithBaseURL(server.URL))
+
+			resp, diagnostics, err := client.GetAddressFromText(context.Background(), tt.raw)
+			if err != nil {
+				t.Fatalf("GetAddressFromText failed: %v", err)
+			}
+			if resp.Address.StreetAddress != tt.wantQuery.Get("streetAddress") {
+				t.Errorf("Expected StreetAddress '%s', got '%s'", tt.wantQuery.Get("streetAddress"), resp.Address.StreetAddress)
+			}
+			if gotQuery.Encode() != tt.wantQuery.Encode() {
+				t.Errorf("Expected query %s, got %s", tt.wantQuery.Encode(), gotQuery.Encode())
+			}
+
+			if len(diagnostics) != len(tt.wantDiagCodes) {
+				t.Fatalf("Expected diagnostics %v, got %v", tt.wantDiagCodes, diagnostics)
+			}
+			for i, d := range diagnostics {
+				if d.Code != tt.wantDiagCodes[i] {
+					t.Errorf("Expected diagnostic %s, got %s", tt.wantDiagCodes[i], d.Code)
+				}
+			}
+		})
+	}
+}
+
+func TestGetAddressFromText_Unparseable(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+	}))
+	defer server.Close()
+
+	client := NewClient(NewStaticTokenProvider("test-token"), WithBaseURL(server.URL))
+
+	resp, diagnostics, err := client.GetAddressFromText(context.Background(), "hello world")
+	if !errors.Is(err, ErrUnparseableAddress) {
+		t.Fatalf("Expected ErrUnparseableAddress, got %v", err)
+	}
+	if resp != nil {
+		t.Errorf("Expected nil response, got %+v", resp)
+	}
+	if calls != 0 {
+		t.Errorf("Expected no API call, got %d", calls)
+	}
+
+	hasError := false
+	for _, d := range diagnostics {
+		if d.Severity == parser.SeverityError {
+			hasError = true
+		}
+	}
+	if !hasError {
+		t.Errorf("Expected error diagnostics, got %v", diagnostics)
+	}
+}
+
+func TestGetAddressFromText_APIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		_ = json.NewEncoder(w).Encode(models.ErrorMessage{
+			Error: &models.ErrorInfo{Code: "404", Message: "Address Not Found"},
+		})
+	}))
+	defer server.Close()
+
+	client := NewClient(NewStaticTokenProvider("test-token"), WithBaseURL(server.URL))
+
+	_, diagnostics, err := client.GetAddressFromText(context.Background(), "999 Nowhere Rd, Springfield, IL 62704")
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+	if len(diagnostics) != 0 {
+		t.Errorf("Expected no diagnostics, got %v", diagnostics)
+	}
+}
@@ -0,0 +1,167 @@
+package usps
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// TokenStore persists OAuthTokenProvider tokens across process restarts, so
+// short-lived processes such as serverless functions can reuse a valid token
+// instead of requesting a new one on every cold start.
+type TokenStore interface {
+	// Load returns the stored access token, its expiry, and refresh token. It
+	// returns an empty token and a nil error when nothing is stored.
+	Load(ctx context.Context) (token string, expiry time.Time, refresh string, err error)
+	// Save replaces the stored tokens.
+	Save(ctx context.Context, token string, expiry time.Time, refresh string) error
+}
+
+// WithTokenStore makes the provider load a token from store on the first
+// GetToken call and save each newly acquired or refreshed token to it. A
+// stored token that has already expired is not used, though its refresh
+// token is when refresh tokens are enabled. Load and Save errors do not fail
+// GetToken: on a Load error the provider requests a new token, and a Save
+// error only means the next process cannot reuse it. Refresh tokens are not
+// saved when WithRefreshTokenStorage(false) is set.
+//
+// Load runs while the provider's lock is held, so it blocks concurrent
+// GetToken calls and must not call back into the provider. Save runs after
+// the lock is released, on the goroutine that called GetToken.
+func WithTokenStore(store TokenStore) OAuthTokenOption {
+	return func(p *OAuthTokenProvider) {
+		p.tokenStore = store
+	}
+}
+
+// loadFromStoreLocked loads the stored token the first time it is called.
+// Caller must hold the write lock.
+func (p *OAuthTokenProvider) loadFromStoreLocked(ctx context.Context) {
+	if p.tokenStore == nil || p.tokenStoreLoaded {
+		return
+	}
+	p.tokenStoreLoaded = true
+
+	token, expiry, refresh, err := p.tokenStore.Load(ctx)
+	if err != nil {
+		return
+	}
+	if p.keepsRefreshTokens() {
+		p.refreshToken = refresh
+	}
+	lifetime := time.Until(expiry)
+	if token == "" || lifetime <= 0 {
+		return
+	}
+	p.cachedToken = token
+	p.tokenExpiresAt = expiry
+	p.tokenExpiration = p.refreshTime(lifetime)
+}
+
+// pendingToken is a token waiting to be saved to the TokenStore once the lock
+// is released
+type pendingToken struct {
+	token   string
+	expiry  time.Time
+	refresh string
+}
+
+// queueSaveLocked queues the current token to be saved once the lock is
+// released. Caller must hold the write lock.
+func (p *OAuthTokenProvider) queueSaveLocked() {
+	if p.tokenStore == nil {
+		return
+	}
+	p.pendingSave = &pendingToken{token: p.cachedToken, expiry: p.tokenExpiresAt, refresh: p.refreshToken}
+}
+
+// saveToStore saves t without holding the provider's lock. Saves are
+// serialized, and a token older than the last one saved is skipped, so a slow
+// Save cannot overwrite a newer token with a stale one.
+func (p *OAuthTokenProvider) saveToStore(ctx context.Context, t *pendingToken) {
+	p.saveMutex.Lock()
+	defer p.saveMutex.Unlock()
+	if t.expiry.Before(p.savedExpiry) {
+		return
+	}
+	if err := p.tokenStore.Save(ctx, t.token, t.expiry, t.refresh); err == nil {
+		p.savedExpiry = t.expiry
+	}
+}
+
+// FileTokenStore is a TokenStore that keeps tokens in a JSON file readable
+// only by the current user. Writes replace the file atomically, so a crash
+// never leaves a partial token behind.
+type FileTokenStore struct {
+	path string
+}
+
+// NewFileTokenStore returns a TokenStore backed by the file at path. The file
+// and its directory are created on the first Save.
+//
+// Example:
+//
+//	store := usps.NewFileTokenStore("/tmp/usps-token.json")
+//	provider := usps.NewOAuthTokenProvider(clientID, clientSecret, usps.WithTokenStore(store))
+func NewFileTokenStore(path string) *FileTokenStore {
+	return &FileTokenStore{path: path}
+}
+
+// storedToken is the on-disk format of FileTokenStore
+type storedToken struct {
+	AccessToken  string    `json:"access_token"`
+	ExpiresAt    time.Time `json:"expires_at"`
+	RefreshToken string    `json:"refresh_token,omitempty"`
+}
+
+// Load reads the stored tokens. A missing file is not an error.
+func (s *FileTokenStore) Load(ctx context.Context) (string, time.Time, string, error) {
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", time.Time{}, "", nil
+	}
+	if err != nil {
+		return "", time.Time{}, "", fmt.Errorf("failed to read token file: %w", err)
+	}
+
+	var stored storedToken
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return "", time.Time{}, "", fmt.Errorf("failed to decode token file: %w", err)
+	}
+	return stored.AccessToken, stored.ExpiresAt, stored.RefreshToken, nil
+}
+
+// Save writes the tokens to a temporary file and renames it over the store.
+func (s *FileTokenStore) Save(ctx context.Context, token string, expiry time.Time, refresh string) error {
+	data, err := json.Marshal(storedToken{AccessToken: token, ExpiresAt: expiry, RefreshToken: refresh})
+	if err != nil {
+		return fmt.Errorf("failed to encode token file: %w", err)
+	}
+
+	dir := filepath.Dir(s.path)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return fmt.Errorf("failed to create token directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(dir, filepath.Base(s.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create token file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write token file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write token file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("failed to replace token file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,235 @@
+package usps
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/my-eq/go-usps/models"
+)
+
+// memoryTokenStore is an in-memory TokenStore for tests
+type memoryTokenStore struct {
+	token   string
+	expiry  time.Time
+	refresh string
+	loadErr error
+	loads   int
+	saves   int
+	onSave  func()
+}
+
+func (s *memoryTokenStore) Load(ctx context.Context) (string, time.Time, string, error) {
+	s.loads++
+	return s.token, s.expiry, s.refresh, s.loadErr
+}
+
+func (s *memoryTokenStore) Save(ctx context.Context, token string, expiry time.Time, refresh string) error {
+	s.saves++
+	s.token, s.expiry, s.refresh = token, expiry, refresh
+	if s.onSave != nil {
+		s.onSave()
+	}
+	return nil
+}
+
+func newTokenStoreTestServer(t *testing.T, callCount *int) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*callCount++
+		resp := models.ProviderAccessTokenResponse{
+			AccessToken: "network-token",
+			ExpiresIn:   28800,
+			TokenType:   "Bearer",
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestOAuthTokenProvider_TokenStore_ReusesStoredToken(t *testing.T) {
+	callCount := 0
+	server := newTokenStoreTestServer(t, &callCount)
+
+	expiry := time.Now().Add(4 * time.Hour)
+	store := &memoryTokenStore{token: "stored-token", expiry: expiry}
+
+	provider := NewOAuthTokenProvider("client-id", "client-secret", WithTokenStore(store))
+	provider.oauthClient = NewOAuthClient(WithBaseURL(server.URL))
+
+	for i := 0; i < 2; i++ {
+		token, err := provider.GetToken(context.Background())
+		if err != nil {
+			t.Fatalf("GetToken failed: %v", err)
+		}
+		if token != "stored-token" {
+			t.Errorf("Expected token 'stored-token', got '%s'", token)
+		}
+	}
+
+	if callCount != 0 {
+		t.Errorf("Expected no server calls, got %d", callCount)
+	}
+	if store.loads != 1 {
+		t.Errorf("Expected 1 store load, got %d", store.loads)
+	}
+	if store.saves != 0 {
+		t.Errorf("Expected no store saves, got %d", store.saves)
+	}
+	if !provider.TokenExpiresAt().Equal(expiry) {
+		t.Errorf("Expected TokenExpiresAt %v, got %v", expiry, provider.TokenExpiresAt())
+	}
+}
+
+func TestOAuthTokenProvider_TokenStore_SavesNewToken(t *testing.T) {
+	tests := []struct {
+		name  string
+		store *memoryTokenStore
+	}{
+		{name: "empty store", store: &memoryTokenStore{}},
+		{name: "expired token", store: &memoryTokenStore{token: "stale-token", expiry: time.Now().Add(-time.Minute)}},
+		{name: "load error", store: &memoryTokenStore{loadErr: errors.New("disk unavailable")}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			callCount := 0
+			server := newTokenStoreTestServer(t, &callCount)
+
+			provider := NewOAuthTokenProvider("client-id", "client-secret", WithTokenStore(tt.store))
+			provider.oauthClient = NewOAuthClient(WithBaseURL(server.URL))
+
+			token, err := provider.GetToken(context.Background())
+			if err != nil {
+				t.Fatalf("GetToken failed: %v", err)
+			}
+			if token != "network-token" {
+				t.Errorf("Expected token 'network-token', got '%s'", token)
+			}
+			if callCount != 1 {
+				t.Errorf("Expected 1 server call, got %d", callCount)
+			}
+			if tt.store.saves != 1 {
+				t.Errorf("Expected 1 store save, got %d", tt.store.saves)
+			}
+			if tt.store.token != "network-token" {
+				t.Errorf("Expected stored token 'network-token', got '%s'", tt.store.token)
+			}
+			if !tt.store.expiry.Equal(provider.TokenExpiresAt()) {
+				t.Errorf("Expected stored expiry %v, got %v", provider.TokenExpiresAt(), tt.store.expiry)
+			}
+
+			// A fresh provider sharing the store should not hit the network
+			tt.store.loadErr = nil
+			next := NewOAuthTokenProvider("client-id", "client-secret", WithTokenStore(tt.store))
+			next.oauthClient = NewOAuthClient(WithBaseURL(server.URL))
+			if _, err := next.GetToken(context.Background()); err != nil {
+				t.Fatalf("GetToken failed: %v", err)
+			}
+			if callCount != 1 {
+				t.Errorf("Expected stored token to be reused, got %d server calls", callCount)
+			}
+		})
+	}
+}
+
+func TestOAuthTokenProvider_TokenStore_SavesUnlocked(t *testing.T) {
+	callCount := 0
+	server := newTokenStoreTestServer(t, &callCount)
+
+	var provider *OAuthTokenProvider
+	var savedExpiresAt time.Time
+	store := &memoryTokenStore{onSave: func() {
+		// The lock is released, so Save can query the provider
+		savedExpiresAt = provider.TokenExpiresAt()
+	}}
+	provider = NewOAuthTokenProvider("client-id", "client-secret", WithTokenStore(store))
+	provider.oauthClient = NewOAuthClient(WithBaseURL(server.URL))
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := provider.GetToken(context.Background())
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("GetToken failed: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("GetToken deadlocked saving the token")
+	}
+	if store.saves != 1 {
+		t.Errorf("Expected 1 store save, got %d", store.saves)
+	}
+	if !savedExpiresAt.Equal(store.expiry) {
+		t.Errorf("Expected Save to see TokenExpiresAt %v, got %v", store.expiry, savedExpiresAt)
+	}
+
+	// A token older than the last one saved is not written over it
+	provider.saveToStore(context.Background(), &pendingToken{token: "old-token", expiry: store.expiry.Add(-time.Hour)})
+	if store.token != "network-token" {
+		t.Errorf("Expected stored token 'network-token', got '%s'", store.token)
+	}
+}
+
+func TestFileTokenStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tokens", "usps.json")
+	store := NewFileTokenStore(path)
+	ctx := context.Background()
+
+	token, expiry, refresh, err := store.Load(ctx)
+	if err != nil {
+		t.Fatalf("Load of missing file failed: %v", err)
+	}
+	if token != "" || !expiry.IsZero() || refresh != "" {
+		t.Errorf("Expected empty values for missing file, got %q %v %q", token, expiry, refresh)
+	}
+
+	wantExpiry := time.Date(2026, 1, 2, 15, 4, 5, 0, time.UTC)
+	if err := store.Save(ctx, "access-token", wantExpiry, "refresh-token"); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("Stat failed: %v", err)
+	}
+	if perm := info.Mode().Perm(); perm != 0o600 {
+		t.Errorf("Expected file mode 0600, got %o", perm)
+	}
+
+	token, expiry, refresh, err = store.Load(ctx)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if token != "access-token" {
+		t.Errorf("Expected token 'access-token', got '%s'", token)
+	}
+	if !expiry.Equal(wantExpiry) {
+		t.Errorf("Expected expiry %v, got %v", wantExpiry, expiry)
+	}
+	if refresh != "refresh-token" {
+		t.Errorf("Expected refresh 'refresh-token', got '%s'", refresh)
+	}
+}
+
+func TestFileTokenStore_InvalidFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "usps.json")
+	if err := os.WriteFile(path, []byte("not json"), 0o600); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+
+	if _, _, _, err := NewFileTokenStore(path).Load(context.Background()); err == nil {
+		t.Error("Expected error for invalid token file")
+	}
+}
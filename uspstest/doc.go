@@ -0,0 +1,23 @@
+// Package uspstest provides utilities for testing code that uses the USPS API
+// client offline.
+//
+// RecordingTransport captures real request/response pairs to a fixture file,
+// and ReplayTransport serves them back, so a test can run against live USPS
+// responses once and then deterministically without network access. Both are
+// http.RoundTrippers and are wired in with usps.WithTransport:
+//
+//	// Record once against the USPS testing environment
+//	rec := uspstest.NewRecordingTransport("testdata/get_address.json", nil)
+//	provider := usps.NewOAuthTestTokenProvider(clientID, clientSecret)
+//	client := usps.NewTestClient(provider, usps.WithTransport(rec))
+//
+//	// Replay in CI
+//	replay, err := uspstest.NewReplayTransport("testdata/get_address.json")
+//	if err != nil {
+//	    t.Fatal(err)
+//	}
+//	client := usps.NewTestClient(usps.NewStaticTokenProvider("unused"), usps.WithTransport(replay))
+//
+// Recordings never contain the Authorization header. Token requests made by
+// an OAuthTokenProvider use its own HTTP client and are not recorded.
+package uspstest
@@ -0,0 +1,203 @@
+package uspstest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+)
+
+// RedactedValue replaces the value of redacted headers in recordings
+const RedactedValue = "REDACTED"
+
+// redactedHeaders are never written to a recording
+var redactedHeaders = []string{"Authorization"}
+
+// Interaction is a single recorded request/response pair
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// RecordedRequest is the recorded part of an HTTP request
+type RecordedRequest struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body,omitempty"`
+}
+
+// RecordedResponse is the recorded part of an HTTP response
+type RecordedResponse struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body,omitempty"`
+}
+
+// RecordingTransport is an http.RoundTripper that sends requests through an
+// underlying transport and writes each request/response pair to a JSON
+// fixture file, rewriting the whole file after every interaction. The
+// Authorization header value is replaced with RedactedValue. Request and
+// response bodies are recorded verbatim, so record OAuth token calls only if
+// the fixture will not be shared.
+//
+// It is safe for concurrent use.
+type RecordingTransport struct {
+	path         string
+	next         http.RoundTripper
+	mu           sync.Mutex
+	interactions []Interaction
+}
+
+// NewRecordingTransport returns a RecordingTransport that writes to path and
+// sends requests through next, or http.DefaultTransport if next is nil.
+func NewRecordingTransport(path string, next http.RoundTripper) *RecordingTransport {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &RecordingTransport{path: path, next: next}
+}
+
+// RoundTrip implements http.RoundTripper. Transport errors are returned
+// unrecorded; an error writing the fixture file is returned in place of the
+// response.
+func (t *RecordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	reqBody, err := readBody(req.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read request body: %w", err)
+	}
+	if reqBody != nil {
+		req = req.Clone(req.Context())
+		req.Body = io.NopCloser(bytes.NewReader(reqBody))
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := readBody(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	interaction := Interaction{
+		Request: RecordedRequest{
+			Method: req.Method,
+			URL:    req.URL.String(),
+			Header: redact(req.Header),
+			Body:   string(reqBody),
+		},
+		Response: RecordedResponse{
+			StatusCode: resp.StatusCode,
+			Header:     redact(resp.Header),
+			Body:       string(respBody),
+		},
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.interactions = append(t.interactions, interaction)
+	if err := writeInteractions(t.path, t.interactions); err != nil {
+		_ = resp.Body.Close()
+		return nil, err
+	}
+	return resp, nil
+}
+
+// Interactions returns a copy of the interactions recorded so far
+func (t *RecordingTransport) Interactions() []Interaction {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]Interaction(nil), t.interactions...)
+}
+
+// ReplayTransport is an http.RoundTripper that serves responses from a
+// fixture written by RecordingTransport without touching the network. Each
+// request is answered with the first unused interaction with the same method
+// and URL, so repeated calls replay in recorded order. A request with no
+// remaining match fails with an error.
+//
+// It is safe for concurrent use.
+type ReplayTransport struct {
+	mu           sync.Mutex
+	interactions []Interaction
+	used         []bool
+}
+
+// NewReplayTransport loads the fixture at path.
+func NewReplayTransport(path string) (*ReplayTransport, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read recording: %w", err)
+	}
+	var interactions []Interaction
+	if err := json.Unmarshal(data, &interactions); err != nil {
+		return nil, fmt.Errorf("failed to decode recording: %w", err)
+	}
+	return &ReplayTransport{interactions: interactions, used: make([]bool, len(interactions))}, nil
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *ReplayTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		_ = req.Body.Close()
+	}
+	url := req.URL.String()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for i, in := range t.interactions {
+		if t.used[i] || in.Request.Method != req.Method || in.Request.URL != url {
+			continue
+		}
+		t.used[i] = true
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", in.Response.StatusCode, http.StatusText(in.Response.StatusCode)),
+			StatusCode:    in.Response.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        in.Response.Header.Clone(),
+			Body:          io.NopCloser(bytes.NewReader([]byte(in.Response.Body))),
+			ContentLength: int64(len(in.Response.Body)),
+			Request:       req,
+		}, nil
+	}
+	return nil, fmt.Errorf("no recorded interaction for %s %s", req.Method, url)
+}
+
+// readBody reads and closes body, which may be nil
+func readBody(body io.ReadCloser) ([]byte, error) {
+	if body == nil || body == http.NoBody {
+		return nil, nil
+	}
+	defer func() { _ = body.Close() }()
+	return io.ReadAll(body)
+}
+
+// redact returns a copy of header with redactedHeaders replaced
+func redact(header http.Header) http.Header {
+	out := header.Clone()
+	for _, name := range redactedHeaders {
+		if out.Get(name) != "" {
+			out.Set(name, RedactedValue)
+		}
+	}
+	return out
+}
+
+// writeInteractions writes interactions to path as indented JSON
+func writeInteractions(path string, interactions []Interaction) error {
+	data, err := json.MarshalIndent(interactions, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode recording: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write recording: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package uspstest
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/my-eq/go-usps"
+	"github.com/my-eq/go-usps/models"
+)
+
+func TestRecordAndReplay_GetAddress(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "get_address.json")
+
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if r.Header.Get("Authorization") != "Bearer secret-token" {
+			t.Errorf("Expected Authorization 'Bearer secret-token', got '%s'", r.Header.Get("Authorization"))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(models.AddressResponse{
+			Address: &models.DomesticAddress{
+				Address: models.Address{StreetAddress: "123 MAIN ST"},
+				State:   "NY",
+				ZIPCode: "10001",
+			},
+		})
+	}))
+
+	req := &models.AddressRequest{StreetAddress: "123 Main St", City: "New York", State: "NY"}
+
+	// Record against the live server
+	rec := NewRecordingTransport(path, nil)
+	client := usps.NewClient(usps.NewStaticTokenProvider("secret-token"),
+		usps.WithBaseURL(server.URL), usps.WithTransport(rec))
+	recorded, err := client.GetAddress(context.Background(), req)
+	if err != nil {
+		t.Fatalf("GetAddress while recording failed: %v", err)
+	}
+	server.Close()
+
+	if calls != 1 {
+		t.Errorf("Expected 1 server call, got %d", calls)
+	}
+	if n := len(rec.Interactions()); n != 1 {
+		t.Fatalf("Expected 1 recorded interaction, got %d", n)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read recording: %v", err)
+	}
+	if strings.Contains(string(data), "secret-token") {
+		t.Errorf("Expected Authorization to be redacted, got %s", data)
+	}
+	if got := rec.Interactions()[0].Request.Header.Get("Authorization"); got != RedactedValue {
+		t.Errorf("Expected Authorization '%s', got '%s'", RedactedValue, got)
+	}
+
+	// Replay with the server gone
+	replay, err := NewReplayTransport(path)
+	if err != nil {
+		t.Fatalf("NewReplayTransport failed: %v", err)
+	}
+	client = usps.NewClient(usps.NewStaticTokenProvider("other-token"),
+		usps.WithBaseURL(server.URL), usps.WithTransport(replay))
+	replayed, err := client.GetAddress(context.Background(), req)
+	if err != nil {
+		t.Fatalf("GetAddress while replaying failed: %v", err)
+	}
+
+	if replayed.Address.StreetAddress != recorded.Address.StreetAddress {
+		t.Errorf("Expected StreetAddress '%s', got '%s'", recorded.Address.StreetAddress, replayed.Address.StreetAddress)
+	}
+	if replayed.Address.ZIPCode != "10001" {
+		t.Errorf("Expected ZIPCode '10001', got '%s'", replayed.Address.ZIPCode)
+	}
+
+	// Each interaction is replayed once
+	if _, err := client.GetAddress(context.Background(), req); err == nil {
+		t.Error("Expected error once the recording is used up")
+	}
+}
+
+func TestReplayTransport_NoMatch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "empty.json")
+	if err := os.WriteFile(path, []byte("[]"), 0o600); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+
+	replay, err := NewReplayTransport(path)
+	if err != nil {
+		t.Fatalf("NewReplayTransport failed: %v", err)
+	}
+	client := usps.NewClient(usps.NewStaticTokenProvider("token"), usps.WithTransport(replay))
+	if _, err := client.GetCityState(context.Background(), &models.CityStateRequest{ZIPCode: "10001"}); err == nil {
+		t.Error("Expected error for unrecorded request")
+	}
+}
+
+func TestNewReplayTransport_MissingFile(t *testing.T) {
+	if _, err := NewReplayTransport(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("Expected error for missing recording")
+	}
+}
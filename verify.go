@@ -0,0 +1,55 @@
+package usps
+
+import (
+	"context"
+	"strings"
+
+	"github.com/my-eq/go-usps/models"
+)
+
+// VerifyZIPPlus4 checks a stored ZIP+4 for data-quality audits. It
+// standardizes req with GetAddress and reports whether the returned ZIP+4
+// matches req.ZIPPlus4, along with the standardized response. When req has a
+// ZIP code, the returned ZIP code must match it as well, since a ZIP+4 is only
+// meaningful with its 5-digit ZIP code.
+//
+// The stored ZIP+4 is left out of the GetAddress request so that it cannot
+// influence the lookup. ok is false when either side has no ZIP+4: a missing
+// stored value cannot be confirmed, and USPS returns none for an address it
+// could not match to a delivery point. Options such as WithRequestDeadline
+// apply to the GetAddress call.
+//
+// Example:
+//
+//	ok, resp, err := client.VerifyZIPPlus4(ctx, stored)
+//	if err != nil {
+//	    return err
+//	}
+//	if !ok {
+//	    log.Printf("ZIP+4 %q for %s does not match USPS", stored.ZIPPlus4, stored.StreetAddress)
+//	}
+func (c *Client) VerifyZIPPlus4(ctx context.Context, req *models.AddressRequest, opts ...RequestOption) (ok bool, standardized *models.AddressResponse, err error) {
+	var lookup *models.AddressRequest
+	var zip, zipPlus4 string
+	if req != nil {
+		r := *req
+		r.ZIPPlus4 = ""
+		lookup = &r
+		zip = strings.TrimSpace(req.ZIPCode)
+		zipPlus4 = strings.TrimSpace(req.ZIPPlus4)
+	}
+
+	standardized, err = c.GetAddress(ctx, lookup, opts...)
+	if err != nil {
+		return false, nil, err
+	}
+
+	addr := standardized.Address
+	if zipPlus4 == "" || addr == nil || addr.ZIPPlus4 == nil || *addr.ZIPPlus4 == "" {
+		return false, standardized, nil
+	}
+	if zip != "" && zip != addr.ZIPCode {
+		return false, standardized, nil
+	}
+	return *addr.ZIPPlus4 == zipPlus4, standardized, nil
+}
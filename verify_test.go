@@ -0,0 +1,105 @@
+package usps
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/my-eq/go-usps/models"
+)
+
+// newZIPPlus4Server returns a server that standardizes every address to ZIP
+// code 10001 with the given ZIP+4; an empty zipPlus4 returns none.
+func newZIPPlus4Server(t *testing.T, zipPlus4 string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("ZIPPlus4"); got != "" {
+			t.Errorf("Expected no ZIPPlus4 in the lookup, got '%s'", got)
+		}
+
+		addr := &models.DomesticAddress{
+			Address: models.Address{StreetAddress: "123 MAIN ST"},
+			City:    "NEW YORK",
+			State:   "NY",
+			ZIPCode: "10001",
+		}
+		if zipPlus4 != "" {
+			addr.ZIPPlus4 = &zipPlus4
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(models.AddressResponse{Address: addr})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestVerifyZIPPlus4(t *testing.T) {
+	tests := []struct {
+		name         string
+		zipCode      string
+		zipPlus4     string
+		respZIPPlus4 string
+		wantOK       bool
+	}{
+		{"matching", "10001", "1234", "1234", true},
+		{"matching without ZIP code", "", "1234", "1234", true},
+		{"matching with whitespace", "10001", " 1234 ", "1234", true},
+		{"mismatching", "10001", "1234", "5678", false},
+		{"mismatching ZIP code", "10002", "1234", "1234", false},
+		{"missing in request", "10001", "", "1234", false},
+		{"missing in response", "10001", "1234", "", false},
+		{"missing on both sides", "10001", "", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newZIPPlus4Server(t, tt.respZIPPlus4)
+			client := NewClient(NewStaticTokenProvider("test-token"), WithBaseURL(server.URL))
+
+			req := &models.AddressRequest{
+				StreetAddress: "123 Main St",
+				State:         "NY",
+				ZIPCode:       tt.zipCode,
+				ZIPPlus4:      tt.zipPlus4,
+			}
+			ok, resp, err := client.VerifyZIPPlus4(context.Background(), req)
+			if err != nil {
+				t.Fatalf("VerifyZIPPlus4 failed: %v", err)
+			}
+			if ok != tt.wantOK {
+				t.Errorf("Expected ok %v, got %v", tt.wantOK, ok)
+			}
+			if resp == nil || resp.Address == nil || resp.Address.StreetAddress != "123 MAIN ST" {
+				t.Errorf("Expected standardized response, got %+v", resp)
+			}
+			if req.ZIPPlus4 != tt.zipPlus4 {
+				t.Errorf("Expected request ZIPPlus4 '%s' to be unchanged, got '%s'", tt.zipPlus4, req.ZIPPlus4)
+			}
+		})
+	}
+}
+
+func TestVerifyZIPPlus4_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		_ = json.NewEncoder(w).Encode(models.ErrorMessage{})
+	}))
+	defer server.Close()
+
+	client := NewClient(NewStaticTokenProvider("test-token"), WithBaseURL(server.URL))
+	ok, resp, err := client.VerifyZIPPlus4(context.Background(), &models.AddressRequest{
+		StreetAddress: "1 Nowhere Ln",
+		State:         "NY",
+		ZIPPlus4:      "1234",
+	})
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+	if ok || resp != nil {
+		t.Errorf("Expected no result on error, got ok=%v resp=%+v", ok, resp)
+	}
+}